@@ -1,8 +1,11 @@
 package app
 
 import (
+	"fmt"
+
 	"github.com/tranducquang/kiroku/internal/config"
 	"github.com/tranducquang/kiroku/internal/database"
+	"github.com/tranducquang/kiroku/internal/logging"
 	"github.com/tranducquang/kiroku/internal/repository"
 	"github.com/tranducquang/kiroku/internal/service"
 )
@@ -39,15 +42,26 @@ func New(cfg *config.Config) (*App, error) {
 		return nil, err
 	}
 
+	if err := ensureTokenizer(db, cfg.Database.FTSTokenizer); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	files, err := contentFiles(cfg)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	// Initialize repositories
-	noteRepo := repository.NewNoteRepository(db)
+	noteRepo := repository.NewNoteRepository(db, files)
 	folderRepo := repository.NewFolderRepository(db)
 	templateRepo := repository.NewTemplateRepository(db)
-	searchRepo := repository.NewSearchRepository(db)
+	searchRepo := repository.NewSearchRepository(db, files)
 
 	// Initialize services
-	noteService := service.NewNoteService(noteRepo, templateRepo, folderRepo)
-	folderService := service.NewFolderService(folderRepo, noteRepo)
+	noteService := service.NewNoteService(noteRepo, templateRepo, folderRepo, searchRepo, cfg.Database.ContentMaxBytes, cfg.Database.TitleMaxLength)
+	folderService := service.NewFolderService(folderRepo, noteRepo, cfg.UI.MaxDepth)
 	templateService := service.NewTemplateService(templateRepo)
 	searchService := service.NewSearchService(searchRepo)
 	editorService := service.NewEditorService(cfg)
@@ -67,10 +81,45 @@ func New(cfg *config.Config) (*App, error) {
 	}, nil
 }
 
-// Close closes all resources
+// contentFiles returns the note file store for the configured content
+// storage, or nil when content stays in SQLite
+func contentFiles(cfg *config.Config) (*repository.ContentFiles, error) {
+	switch cfg.Database.ContentStorage {
+	case "", config.ContentStorageSQLite:
+		return nil, nil
+	case config.ContentStorageFiles:
+		return repository.NewContentFiles(cfg.Database.NotesDir), nil
+	default:
+		return nil, fmt.Errorf("unknown database.content_storage %q (want %q or %q)",
+			cfg.Database.ContentStorage, config.ContentStorageSQLite, config.ContentStorageFiles)
+	}
+}
+
+// ensureTokenizer rebuilds the search index when it was built with a
+// different tokenizer than the configured one
+func ensureTokenizer(db *database.DB, tokenizer string) error {
+	if tokenizer == "" {
+		return nil
+	}
+	current, err := db.FTSTokenizer()
+	if err != nil {
+		return err
+	}
+	if current == tokenizer {
+		return nil
+	}
+	logging.Info().Str("from", current).Str("to", tokenizer).Msg("Rebuilding search index")
+	return db.Reindex(tokenizer)
+}
+
+// Close checkpoints the WAL and closes all resources. A failed checkpoint
+// is only logged: the WAL is kept and replayed on the next open.
 func (a *App) Close() error {
-	if a.DB != nil {
-		return a.DB.Close()
+	if a.DB == nil {
+		return nil
+	}
+	if err := a.DB.Checkpoint(); err != nil {
+		logging.Warn().Err(err).Msg("Leaving WAL for the next open")
 	}
-	return nil
+	return a.DB.Close()
 }
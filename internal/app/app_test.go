@@ -0,0 +1,45 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tranducquang/kiroku/internal/config"
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+func TestClose_ShrinksWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kiroku.db")
+	a, err := New(&config.Config{Database: config.DatabaseConfig{Path: path}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for _, title := range []string{"one", "two", "three"} {
+		if err := a.NoteService.Create(context.Background(), &models.Note{Title: title}); err != nil {
+			t.Fatalf("Create(%q) error = %v", title, err)
+		}
+	}
+	before, err := os.Stat(path + "-wal")
+	if err != nil || before.Size() == 0 {
+		t.Fatalf("want a non-empty WAL before Close, got %v, %v", before, err)
+	}
+
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	after, err := os.Stat(path + "-wal")
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		t.Fatalf("stat WAL: %v", err)
+	}
+	if after.Size() >= before.Size() {
+		t.Errorf("WAL size after Close() = %d, want less than %d", after.Size(), before.Size())
+	}
+}
@@ -1,7 +1,6 @@
 package cli
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -12,32 +11,64 @@ var addCmd = &cobra.Command{
 	Use:   "add [title]",
 	Short: "Quick add a new note",
 	Long: `Quick add a new note with a title.
+Without a title, or with --editor, the note is written in your editor
+instead, starting from the template content if one is given.
 
 Examples:
   kiroku add "Meeting notes"
-  kiroku add "Sprint planning" --folder work`,
-	Args: cobra.ExactArgs(1),
+  kiroku add "Sprint planning" --folder work
+  kiroku add "Kiroku v2" --template project-note
+  kiroku add
+  kiroku add --editor --template meeting-notes`,
+	Args: cobra.RangeArgs(0, 1),
 	RunE: runAdd,
 }
 
-var addFolder string
+var (
+	addFolder   string
+	addTemplate string
+	addEditor   bool
+)
 
 func init() {
 	addCmd.Flags().StringVarP(&addFolder, "folder", "f", "", "folder name or ID")
+	addCmd.Flags().StringVarP(&addTemplate, "template", "t", "", "template name or ID")
+	addCmd.Flags().BoolVarP(&addEditor, "editor", "e", false, "write the note in the editor")
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, cancel := queryContext()
+	defer cancel()
+	if addEditor || len(args) == 0 {
+		if len(args) > 0 {
+			return fmt.Errorf("%w: --editor takes the title from the editor, not an argument", models.ErrValidation)
+		}
+		return createInEditor(ctx, addTemplate, addFolder)
+	}
 	title := args[0]
 
 	note := &models.Note{
 		Title: title,
 	}
+	if addFolder != "" {
+		folder, err := findFolder(ctx, addFolder)
+		if err != nil {
+			return fmt.Errorf("failed to find folder: %w", err)
+		}
+		note.FolderID = &folder.ID
+	}
+	if addTemplate != "" {
+		template, err := findTemplate(ctx, addTemplate)
+		if err != nil {
+			return fmt.Errorf("failed to find template: %w", err)
+		}
+		note.TemplateID = &template.ID
+	}
 
 	if err := appInst.NoteService.Create(ctx, note); err != nil {
 		return fmt.Errorf("failed to create note: %w", err)
 	}
 
-	fmt.Printf("✨ Created note: %s\n", title)
+	printInfo("✨ Created note: %s\n", title)
 	return nil
 }
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+// stdinPiped reports whether stdin is a pipe or file rather than a terminal
+func stdinPiped() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice == 0
+}
+
+// readIDs reads note IDs separated by whitespace or commas
+func readIDs(r io.Reader) ([]int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read IDs: %w", err)
+	}
+
+	fields := strings.FieldsFunc(string(data), func(c rune) bool {
+		return c == ',' || c == ' ' || c == '\t' || c == '\n' || c == '\r'
+	})
+	ids := make([]int64, 0, len(fields))
+	for _, field := range fields {
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid note ID %q", models.ErrValidation, field)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// bulkTargets returns the notes an --all command acts on: the IDs piped on
+// stdin if there are any, otherwise every note matching opts, narrowed to
+// folder when it is set.
+func bulkTargets(ctx context.Context, folder string, opts models.ListOptions) ([]int64, error) {
+	if stdinPiped() {
+		ids, err := readIDs(os.Stdin)
+		if err != nil || len(ids) > 0 {
+			return ids, err
+		}
+	}
+
+	if folder != "" {
+		f, err := findFolder(ctx, folder)
+		if err != nil {
+			return nil, err
+		}
+		opts.FolderID = &f.ID
+	}
+	notes, err := appInst.NoteService.List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+	ids := make([]int64, len(notes))
+	for i, note := range notes {
+		ids[i] = note.ID
+	}
+	return ids, nil
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var captureCmd = &cobra.Command{
+	Use:   "capture [text]",
+	Short: "Append a quick thought to today's note",
+	Long: `Append a timestamped bullet to today's daily note.
+The daily note is created if it does not exist yet.
+
+Examples:
+  kiroku capture "Call the bank about the card"
+  kiroku capture Idea: cache folder counts`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runCapture,
+}
+
+func runCapture(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+	text := strings.Join(args, " ")
+
+	note, err := appInst.NoteService.Capture(ctx, text)
+	if err != nil {
+		return fmt.Errorf("failed to capture: %w", err)
+	}
+
+	printInfo("📥 Captured to: %s\n", note.Title)
+	return nil
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tranducquang/kiroku/internal/config"
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+// confirmEnv overrides cli.confirm for a single run or script
+const confirmEnv = "KIROKU_CONFIRM"
+
+// confirmMode returns when to ask before a sweeping change: tty, always
+// or never
+func confirmMode() (string, error) {
+	mode := os.Getenv(confirmEnv)
+	if mode == "" && appInst != nil {
+		mode = appInst.Config.CLI.Confirm
+	}
+	switch mode = strings.ToLower(strings.TrimSpace(mode)); mode {
+	case "":
+		return config.ConfirmTTY, nil
+	case config.ConfirmTTY, config.ConfirmAlways, config.ConfirmNever:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("%w: unknown confirm mode %q (use tty, always or never)", models.ErrValidation, mode)
+	}
+}
+
+// confirm asks before a sweeping change. force skips the question; whether
+// it is asked otherwise depends on confirmMode, and without an answer the
+// command needs --force.
+func confirm(force bool, prompt string) (bool, error) {
+	if force {
+		return true, nil
+	}
+	mode, err := confirmMode()
+	if err != nil {
+		return false, err
+	}
+
+	in := os.Stdin
+	switch {
+	case mode == config.ConfirmNever:
+		return false, fmt.Errorf("%w: confirmations are off, use --force to go ahead", models.ErrValidation)
+	case !stdinPiped():
+	case mode == config.ConfirmAlways:
+		// Stdin carries the piped input, so ask on the terminal itself
+		tty, err := os.Open("/dev/tty")
+		if err != nil {
+			return false, fmt.Errorf("%w: no terminal to confirm on, use --force", models.ErrValidation)
+		}
+		defer tty.Close()
+		in = tty
+	default:
+		return false, fmt.Errorf("%w: use --force to confirm without a terminal", models.ErrValidation)
+	}
+
+	fmt.Printf("%s [y/N] ", prompt)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
@@ -0,0 +1,15 @@
+package cli
+
+import "context"
+
+// queryContext returns the context for a command's database work. It
+// expires after database.query_timeout, so a wedged query fails the
+// command instead of hanging it. Waiting on the user for an editor or a
+// prompt does not count against it: take a fresh one afterwards.
+func queryContext() (context.Context, context.CancelFunc) {
+	timeout := appInst.Config.Database.QueryTimeout
+	if timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+var countCmd = &cobra.Command{
+	Use:   "count",
+	Short: "Count notes",
+	Long: `Print the number of notes matching the filters, without listing them.
+
+Examples:
+  kiroku count
+  kiroku count --todos
+  kiroku count --folder work --tag urgent
+  kiroku count --starred -o json`,
+	Args: cobra.NoArgs,
+	RunE: runCount,
+}
+
+var (
+	countTodos   bool
+	countStarred bool
+	countFolder  string
+	countTag     string
+)
+
+func init() {
+	countCmd.Flags().BoolVarP(&countTodos, "todos", "t", false, "count only todos")
+	countCmd.Flags().BoolVarP(&countStarred, "starred", "s", false, "count only starred")
+	countCmd.Flags().StringVarP(&countFolder, "folder", "f", "", "filter by folder name or ID")
+	countCmd.Flags().StringVar(&countTag, "tag", "", "filter by tag")
+}
+
+func runCount(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	opts := models.ListOptions{Tag: countTag}
+	if countTodos {
+		isTodo := true
+		opts.IsTodo = &isTodo
+	}
+	if countStarred {
+		starred := true
+		opts.Starred = &starred
+	}
+	if countFolder != "" {
+		folder, err := findFolder(ctx, countFolder)
+		if err != nil {
+			return fmt.Errorf("failed to count notes: %w", err)
+		}
+		opts.FolderID = &folder.ID
+	}
+
+	count, err := appInst.NoteService.Count(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to count notes: %w", err)
+	}
+
+	if jsonOutput() {
+		return printJSON(struct {
+			Count int `json:"count"`
+		}{count})
+	}
+	fmt.Println(count)
+	return nil
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tranducquang/kiroku/internal/config"
+	"github.com/tranducquang/kiroku/internal/platform"
+)
+
+var dataCmd = &cobra.Command{
+	Use:   "data",
+	Short: "Locate the data directory and database",
+	Long:  `Show or open where Kiroku keeps its database, note files and config, e.g. to back them up.`,
+}
+
+var dataPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Show the data directory, database and config paths",
+	Long: `Show the data directory, the database with any WAL files, the notes
+directory in files mode, and the config file, with their sizes.
+
+Examples:
+  kiroku data path
+  kiroku data path -o json`,
+	Args: cobra.NoArgs,
+	RunE: runDataPath,
+}
+
+var dataOpenCmd = &cobra.Command{
+	Use:   "open",
+	Short: "Open the data directory in file manager",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return platform.OpenPath(config.GetDataDir())
+	},
+}
+
+func init() {
+	dataCmd.AddCommand(dataPathCmd)
+	dataCmd.AddCommand(dataOpenCmd)
+}
+
+// dataFile is a path shown by data path. Size is -1 when it does not exist.
+type dataFile struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+func runDataPath(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	files := []dataFile{
+		statDataFile("Data directory", config.GetDataDir()),
+		statDataFile("Database", cfg.Database.Path),
+	}
+	// SQLite keeps these next to the database while it is open; a backup
+	// taken then needs them too
+	for _, wal := range []dataFile{
+		statDataFile("Database WAL", cfg.Database.Path+"-wal"),
+		statDataFile("Database shared memory", cfg.Database.Path+"-shm"),
+	} {
+		if wal.Size >= 0 {
+			files = append(files, wal)
+		}
+	}
+	if cfg.Database.ContentStorage == config.ContentStorageFiles {
+		files = append(files, statDataFile("Notes directory", cfg.Database.NotesDir))
+	}
+	files = append(files, statDataFile("Config file", configFilePath()))
+
+	if jsonOutput() {
+		return printJSON(files)
+	}
+	for _, f := range files {
+		if f.Size < 0 {
+			fmt.Printf("%s: %s (not found)\n", f.Name, f.Path)
+		} else {
+			fmt.Printf("%s: %s (%d bytes)\n", f.Name, f.Path, f.Size)
+		}
+	}
+	return nil
+}
+
+// statDataFile looks up path; a directory reports the total size of the
+// files directly inside it
+func statDataFile(name, path string) dataFile {
+	f := dataFile{Name: name, Path: path, Size: -1}
+	info, err := os.Stat(path)
+	if err != nil {
+		return f
+	}
+	if !info.IsDir() {
+		f.Size = info.Size()
+		return f
+	}
+
+	f.Size = 0
+	entries, _ := os.ReadDir(path)
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil && info.Mode().IsRegular() {
+			f.Size += info.Size()
+		}
+	}
+	return f
+}
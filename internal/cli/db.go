@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tranducquang/kiroku/internal/config"
+	"github.com/tranducquang/kiroku/internal/database"
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Inspect and repair the database",
+	Long:  `Inspect the database and repair inconsistent data.`,
+}
+
+var dbMigrationsCmd = &cobra.Command{
+	Use:   "migrations",
+	Short: "List applied and pending schema migrations",
+	Long: `List the schema migrations this build ships, when each was applied,
+and which are still pending. Nothing is applied, so it is safe to run at
+any time, including on a database a newer or older kiroku has touched.
+
+Examples:
+  kiroku db migrations
+  kiroku db migrations -o json`,
+	Args: cobra.NoArgs,
+	RunE: runDBMigrations,
+}
+
+var dbRepairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Fix notes that point at deleted folders",
+	Long: `Find notes whose folder no longer exists and move them to the top
+level, where they stay under "Unfiled" in the TUI with the other notes
+outside any folder.
+
+Examples:
+  kiroku db repair`,
+	Args: cobra.NoArgs,
+	RunE: runDBRepair,
+}
+
+var dbStorageCmd = &cobra.Command{
+	Use:   "storage [sqlite|files]",
+	Short: "Show or change where note content is stored",
+	Long: `Show or change where note content is stored.
+
+In files mode each note's content is a markdown file named by its ID
+under database.notes_dir, which you can edit, grep and version with other
+tools. SQLite keeps the metadata and a copy of the content for search.
+
+Switching to files writes every note out; switching back to sqlite copies
+the files into the database and leaves them in place. Running it with the
+current files mode refreshes the search copy after editing files outside
+kiroku.
+
+Examples:
+  kiroku db storage
+  kiroku db storage files
+  kiroku db storage sqlite`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{config.ContentStorageSQLite, config.ContentStorageFiles},
+	RunE:      runDBStorage,
+}
+
+var dbReindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the search index",
+	Long: `Rebuild the full-text search index from the notes, optionally switching
+its tokenizer. The choice is saved as database.fts_tokenizer.
+
+  unicode61  splits words on spaces and punctuation; small index, but text
+             without spaces (Japanese, Chinese) is one long word
+  trigram    matches any substring of 3+ characters, including CJK text;
+             the index is several times larger and shorter queries find
+             nothing
+
+Examples:
+  kiroku db reindex
+  kiroku db reindex --tokenizer trigram`,
+	Args: cobra.NoArgs,
+	RunE: runDBReindex,
+}
+
+func init() {
+	dbCmd.AddCommand(dbMigrationsCmd)
+	dbCmd.AddCommand(dbRepairCmd)
+	dbCmd.AddCommand(dbStorageCmd)
+	dbCmd.AddCommand(dbReindexCmd)
+
+	dbReindexCmd.Flags().String("tokenizer", "", "unicode61 or trigram (default: the configured tokenizer)")
+}
+
+func runDBMigrations(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	db, err := database.New(cfg.Database.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	status, err := db.MigrationStatus()
+	if err != nil {
+		return fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	if jsonOutput() {
+		return printJSON(status)
+	}
+
+	pending := 0
+	for _, m := range status {
+		switch {
+		case !m.Embedded:
+			fmt.Printf("❓ %s  applied %s (unknown to this version)\n", m.Version, m.AppliedAt.Local().Format("2006-01-02 15:04"))
+		case m.Applied():
+			fmt.Printf("✅ %s  applied %s\n", m.Version, m.AppliedAt.Local().Format("2006-01-02 15:04"))
+		default:
+			pending++
+			fmt.Printf("⏳ %s  pending\n", m.Version)
+		}
+	}
+	if pending > 0 {
+		printInfo("\n%d pending; they are applied the next time kiroku opens the database\n", pending)
+	}
+	return nil
+}
+
+func runDBRepair(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	unfiled, err := appInst.NoteService.GetUnfiled(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check folder references: %w", err)
+	}
+	// Unfiled notes without a folder are fine; only dangling ones need repair
+	var dangling []*models.Note
+	for _, note := range unfiled {
+		if note.FolderID != nil {
+			dangling = append(dangling, note)
+		}
+	}
+	if len(dangling) == 0 {
+		printInfo("✅ No notes point at deleted folders\n")
+		return nil
+	}
+
+	for _, note := range dangling {
+		printInfo("🔧 %s (#%d) pointed at deleted folder #%d\n", note.Title, note.ID, *note.FolderID)
+	}
+
+	n, err := appInst.NoteService.RepairFolderRefs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to repair folder references: %w", err)
+	}
+
+	printInfo("✅ Moved %d note(s) to the top level\n", n)
+	return nil
+}
+
+func runDBStorage(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+	cfg := appInst.Config
+	current := cfg.Database.ContentStorage
+	if current == "" {
+		current = config.ContentStorageSQLite
+	}
+
+	if len(args) == 0 {
+		if current == config.ContentStorageFiles {
+			fmt.Printf("📁 Note content is stored in files under %s\n", cfg.Database.NotesDir)
+		} else {
+			fmt.Println("🗄️  Note content is stored in SQLite")
+		}
+		return nil
+	}
+
+	switch mode := args[0]; {
+	case mode == config.ContentStorageFiles && current == config.ContentStorageFiles:
+		n, err := appInst.NoteService.ImportContent(ctx, cfg.Database.NotesDir)
+		if err != nil {
+			return fmt.Errorf("failed to refresh search copy: %w", err)
+		}
+		printInfo("🔄 Refreshed the search copy of %d changed note(s)\n", n)
+		return nil
+
+	case mode == config.ContentStorageFiles:
+		n, err := appInst.NoteService.ExportContent(ctx, cfg.Database.NotesDir)
+		if err != nil {
+			return fmt.Errorf("failed to write note files: %w", err)
+		}
+		cfg.Database.ContentStorage = config.ContentStorageFiles
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		printInfo("📁 Wrote %d note(s) to %s; content is now stored in files\n", n, cfg.Database.NotesDir)
+		return nil
+
+	case mode == config.ContentStorageSQLite && current == config.ContentStorageSQLite:
+		printInfo("🗄️  Note content is already stored in SQLite\n")
+		return nil
+
+	case mode == config.ContentStorageSQLite:
+		n, err := appInst.NoteService.ImportContent(ctx, cfg.Database.NotesDir)
+		if err != nil {
+			return fmt.Errorf("failed to copy note files: %w", err)
+		}
+		cfg.Database.ContentStorage = config.ContentStorageSQLite
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		printInfo("🗄️  Copied %d changed note(s) into SQLite; content is now stored there\n", n)
+		printInfo("   The files in %s were left in place\n", cfg.Database.NotesDir)
+		return nil
+
+	default:
+		return fmt.Errorf("%w: unknown storage %q (want %s or %s)",
+			models.ErrValidation, mode, config.ContentStorageSQLite, config.ContentStorageFiles)
+	}
+}
+
+func runDBReindex(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	tokenizer, _ := cmd.Flags().GetString("tokenizer")
+	if tokenizer == "" {
+		tokenizer = cfg.Database.FTSTokenizer
+	}
+	if !database.IsTokenizer(tokenizer) {
+		return fmt.Errorf("%w: unknown tokenizer %q (want %s)",
+			models.ErrValidation, tokenizer, strings.Join(database.Tokenizers, " or "))
+	}
+
+	db, err := database.New(cfg.Database.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	if err := db.Migrate(); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	if err := db.Reindex(tokenizer); err != nil {
+		return fmt.Errorf("failed to reindex: %w", err)
+	}
+
+	if cfg.Database.FTSTokenizer != tokenizer {
+		cfg.Database.FTSTokenizer = tokenizer
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+	}
+
+	printInfo("🔎 Rebuilt the search index with the %s tokenizer\n", tokenizer)
+	return nil
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tranducquang/kiroku/internal/config"
+	"github.com/tranducquang/kiroku/internal/database"
+	"github.com/tranducquang/kiroku/internal/logging"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the installation for problems",
+	Long: `Check the config file, data directory, database, search index,
+editor and log directory, and suggest a fix for anything that fails.
+
+Exits non-zero when a critical check fails.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+// doctorCheck is the outcome of one diagnostic
+type doctorCheck struct {
+	name     string
+	detail   string
+	hint     string
+	ok       bool
+	critical bool
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	checks := []doctorCheck{checkConfigFile()}
+
+	cfg, err := config.Load()
+	if err != nil {
+		checks = append(checks, doctorCheck{
+			name:     "Config loads",
+			detail:   err.Error(),
+			hint:     "fix or remove " + configFilePath(),
+			critical: true,
+		})
+	} else {
+		checks = append(checks, checkWritableDir("Data directory", filepath.Dir(cfg.Database.Path), true))
+		checks = append(checks, checkDatabase(cfg.Database.Path)...)
+		checks = append(checks, checkEditor(cfg.Editor.Command))
+	}
+	checks = append(checks, checkWritableDir("Log directory", logging.DefaultConfig().LogDir, false))
+
+	failed := 0
+	for _, c := range checks {
+		mark := "✓"
+		if !c.ok {
+			mark = "✗"
+			if c.critical {
+				failed++
+			}
+		}
+		fmt.Printf("%s %s: %s\n", mark, c.name, c.detail)
+		if !c.ok && c.hint != "" {
+			fmt.Printf("    → %s\n", c.hint)
+		}
+	}
+
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d critical check(s) failed", failed)
+	}
+	printInfo("\n🩺 Everything looks good\n")
+	return nil
+}
+
+// configFilePath returns where the config file is read from
+func configFilePath() string {
+	return filepath.Join(config.GetConfigDir(), "config.yaml")
+}
+
+func checkConfigFile() doctorCheck {
+	path := configFilePath()
+	c := doctorCheck{name: "Config file", critical: true}
+	_, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		c.ok, c.detail = true, path
+	case os.IsNotExist(err):
+		c.ok, c.critical, c.detail = false, false, path+" not found, using defaults"
+		c.hint = "run kiroku once to write a default config"
+	default:
+		c.detail = err.Error()
+		c.hint = "check the permissions on " + path
+	}
+	return c
+}
+
+// checkWritableDir verifies that a file can be created in dir
+func checkWritableDir(name, dir string, critical bool) doctorCheck {
+	c := doctorCheck{name: name, detail: dir, critical: critical}
+	f, err := os.CreateTemp(dir, ".kiroku-doctor-*")
+	if err != nil {
+		c.detail = err.Error()
+		c.hint = "create " + dir + " and make it writable"
+		return c
+	}
+	f.Close()
+	os.Remove(f.Name())
+	c.ok = true
+	return c
+}
+
+// checkDatabase opens the database and reports the schema state without
+// applying pending migrations.
+func checkDatabase(path string) []doctorCheck {
+	db, err := database.New(path)
+	if err != nil {
+		return []doctorCheck{{
+			name:     "Database",
+			detail:   err.Error(),
+			hint:     "check that " + path + " is a kiroku database and not locked",
+			critical: true,
+		}}
+	}
+	defer db.Close()
+
+	schema := doctorCheck{name: "Database schema", critical: true}
+	version, pending, err := db.SchemaVersion()
+	switch {
+	case err != nil:
+		schema.detail = err.Error()
+		schema.hint = "the database may be corrupt; restore it from a backup"
+	case len(pending) > 0:
+		schema.detail = fmt.Sprintf("version %s, %d migration(s) pending", orNone(version), len(pending))
+		schema.hint = "run any kiroku command (e.g. kiroku list) to apply them"
+	default:
+		schema.ok, schema.detail = true, "version "+version
+	}
+
+	fts := doctorCheck{name: "Search index", critical: true}
+	found, err := db.HasTable("notes_fts")
+	switch {
+	case err != nil:
+		fts.detail = err.Error()
+	case !found && len(pending) > 0:
+		fts.detail = "notes_fts table missing"
+		fts.hint = "apply the pending migrations first"
+	case !found:
+		fts.detail = "notes_fts table missing"
+		fts.hint = "the initial migration did not complete; restore from a backup or start a new database"
+	default:
+		fts.ok, fts.detail = true, "notes_fts present"
+		if missing, err := db.MissingFTSTriggers(); err != nil {
+			fts.ok, fts.detail = false, err.Error()
+		} else if len(missing) > 0 {
+			fts.ok, fts.detail = false, "sync triggers missing: "+strings.Join(missing, ", ")
+			fts.hint = "run kiroku db reindex to restore them and catch up the index"
+		}
+	}
+
+	return []doctorCheck{{name: "Database", detail: path, ok: true}, schema, fts}
+}
+
+func checkEditor(command string) doctorCheck {
+	c := doctorCheck{name: "Editor"}
+	path, err := exec.LookPath(command)
+	if err != nil {
+		c.detail = fmt.Sprintf("%q not found in PATH", command)
+		c.hint = "set editor.command in " + configFilePath() + " or export EDITOR"
+		return c
+	}
+	c.ok, c.detail = true, path
+	return c
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+var doneCmd = &cobra.Command{
+	Use:   "done [id]",
+	Short: "Mark todos done",
+	Long: `Mark a todo done, or with --all every open todo in a set.
+
+With --all the set is the note IDs piped on stdin, or else every open
+todo, narrowed by --folder. The change is applied in one transaction
+and asks for confirmation unless --force is given. Locked notes and
+notes that are not todos are skipped.
+
+cli.confirm, or KIROKU_CONFIRM for one run, sets when it asks: tty (only
+from a terminal), always (on the terminal even when IDs are piped) or
+never (--force is always needed).
+
+Examples:
+  kiroku done 12
+  kiroku done --all --folder work
+  echo 3 7 12 | kiroku done --all --force`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDone,
+}
+
+var (
+	doneAll    bool
+	doneFolder string
+	doneForce  bool
+)
+
+func init() {
+	doneCmd.Flags().BoolVarP(&doneAll, "all", "a", false, "mark every todo in the set done")
+	doneCmd.Flags().StringVarP(&doneFolder, "folder", "f", "", "with --all, only todos in this folder")
+	doneCmd.Flags().BoolVar(&doneForce, "force", false, "with --all, skip the confirmation")
+}
+
+func runDone(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	if !doneAll {
+		if len(args) == 0 {
+			return fmt.Errorf("%w: give a note ID or --all", models.ErrValidation)
+		}
+		return markOneDone(ctx, args[0])
+	}
+	if len(args) > 0 {
+		return fmt.Errorf("%w: --all takes no note ID", models.ErrValidation)
+	}
+
+	isTodo, isDone := true, false
+	ids, err := bulkTargets(ctx, doneFolder, models.ListOptions{IsTodo: &isTodo, IsDone: &isDone})
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		printInfo("No open todos found.\n")
+		return nil
+	}
+
+	ok, err := confirm(doneForce, fmt.Sprintf("Mark %d todo(s) done?", len(ids)))
+	if err != nil || !ok {
+		return err
+	}
+	// The prompt may have waited on the user for a while
+	ctx, cancel = queryContext()
+	defer cancel()
+
+	changed, err := appInst.NoteService.MarkDone(ctx, ids, true)
+	if err != nil {
+		return fmt.Errorf("failed to mark todos done: %w", err)
+	}
+	printInfo("☑ Marked %d todo(s) done\n", changed)
+	return nil
+}
+
+// markOneDone marks a single todo done, reporting why it could not be
+func markOneDone(ctx context.Context, arg string) error {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid note ID %q", models.ErrValidation, arg)
+	}
+
+	note, err := appInst.NoteService.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get note: %w", err)
+	}
+	switch {
+	case !note.IsTodo:
+		return fmt.Errorf("%w: note %d is not a todo", models.ErrValidation, id)
+	case note.Locked:
+		return models.ErrLocked
+	case note.IsDone:
+		printInfo("☑ Already done: %s\n", note.Title)
+		return nil
+	}
+
+	if _, err := appInst.NoteService.MarkDone(ctx, []int64{id}, true); err != nil {
+		return fmt.Errorf("failed to mark todo done: %w", err)
+	}
+	printInfo("☑ Done: %s\n", note.Title)
+	return nil
+}
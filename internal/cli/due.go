@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+var dueCmd = &cobra.Command{
+	Use:   "due [id] [date|clear]",
+	Short: "Set or clear a todo's due date",
+	Long: `Set, change or clear the due date of a note.
+
+Dates accept the same formats as todo --due: absolute dates such as
+2024-03-01 or "Mar 1", and phrases such as today, tomorrow, friday,
+"next week", "in 3 days" or +2w. Use "clear" to remove the date.
+
+Examples:
+  kiroku due 12 tomorrow
+  kiroku due 12 "next friday"
+  kiroku due 12 clear`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runDue,
+}
+
+func runDue(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid note ID %q", models.ErrValidation, args[0])
+	}
+
+	input := strings.Join(args[1:], " ")
+	var due *time.Time
+	if !strings.EqualFold(input, "clear") {
+		due, err = models.ParseDueDate(input, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := appInst.NoteService.SetDueDate(ctx, id, due); err != nil {
+		return fmt.Errorf("failed to set due date: %w", err)
+	}
+
+	if due == nil {
+		printInfo("📅 Cleared due date of note %d\n", id)
+		return nil
+	}
+	printInfo("📅 Note %d due %s\n", id, due.Format("Mon, Jan 2 2006"))
+	return nil
+}
@@ -2,49 +2,244 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/service"
 )
 
 var editCmd = &cobra.Command{
 	Use:   "edit [id]",
 	Short: "Edit a note",
 	Long: `Open a note in your configured editor.
+With --new, write a fresh note in the editor instead. The title comes from
+the first "# " heading, and nothing is created if the file is left unchanged.
+With --from-stdin, replace the note's content with stdin instead of opening
+an editor, keeping the title unless --title is given.
+With --apply, start from a template with {{title}}, {{date}} and
+{{week_number}} filled in: a new note begins with it, and an existing note
+gets it appended, or prepended with --prepend, before the editor opens.
 
 Examples:
   kiroku edit 1
-  kiroku edit 42`,
-	Args: cobra.ExactArgs(1),
+  kiroku edit 42
+  kiroku edit 42 --editor "code --wait"
+  kiroku edit --new
+  kiroku edit --new --template meeting-notes --folder work
+  kiroku edit --new --apply "Meeting Notes"
+  kiroku edit 42 --apply standup --prepend
+  generate | kiroku edit 5 --from-stdin
+  kiroku edit 5 --from-stdin --title "Weekly report" < report.md`,
+	Args: cobra.RangeArgs(0, 1),
 	RunE: runEdit,
 }
 
+var (
+	editEditor   string
+	editNew      bool
+	editTemplate string
+	editFolder   string
+	editStdin    bool
+	editTitle    string
+	editEmpty    bool
+	editApply    string
+	editPrepend  bool
+)
+
+func init() {
+	editCmd.Flags().StringVar(&editEditor, "editor", "", "editor to use for this edit (overrides config)")
+	editCmd.Flags().BoolVar(&editNew, "new", false, "create a new note in the editor")
+	editCmd.Flags().StringVarP(&editTemplate, "template", "t", "", "template name or ID to start from (with --new)")
+	editCmd.Flags().StringVarP(&editFolder, "folder", "f", "", "folder name or ID for the new note (with --new)")
+	editCmd.Flags().BoolVar(&editStdin, "from-stdin", false, "replace the content with stdin instead of opening an editor")
+	editCmd.Flags().StringVar(&editTitle, "title", "", "new title (with --from-stdin)")
+	editCmd.Flags().BoolVar(&editEmpty, "allow-empty", false, "allow empty content (with --from-stdin)")
+	editCmd.Flags().StringVar(&editApply, "apply", "", "template name or ID to render into the editor")
+	editCmd.Flags().BoolVar(&editPrepend, "prepend", false, "put the applied template before the content (with --apply)")
+}
+
 func runEdit(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, cancel := queryContext()
+	defer cancel()
 
+	if editPrepend && editApply == "" {
+		return fmt.Errorf("%w: --prepend needs --apply", models.ErrValidation)
+	}
+	if editNew {
+		if editStdin {
+			return fmt.Errorf("%w: --new and --from-stdin cannot be combined", models.ErrValidation)
+		}
+		if len(args) > 0 {
+			return fmt.Errorf("%w: --new does not take a note ID", models.ErrValidation)
+		}
+		if editApply != "" && editTemplate != "" {
+			return fmt.Errorf("%w: --template and --apply cannot be combined", models.ErrValidation)
+		}
+		if editApply != "" {
+			return createInEditor(ctx, editApply, editFolder)
+		}
+		return createInEditor(ctx, editTemplate, editFolder)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("%w: a note ID is required (or use --new)", models.ErrValidation)
+	}
+	if !editStdin && (editTitle != "" || editEmpty) {
+		return fmt.Errorf("%w: --title and --allow-empty need --from-stdin", models.ErrValidation)
+	}
+	if editStdin && editApply != "" {
+		return fmt.Errorf("%w: --apply and --from-stdin cannot be combined", models.ErrValidation)
+	}
 	id, err := strconv.ParseInt(args[0], 10, 64)
 	if err != nil {
-		return fmt.Errorf("invalid note ID: %w", err)
+		return fmt.Errorf("%w: invalid note ID %q", models.ErrValidation, args[0])
 	}
 
 	note, err := appInst.NoteService.GetByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("note not found: %w", err)
 	}
+	if note.Locked {
+		return fmt.Errorf("cannot edit %q: %w", note.Title, models.ErrLocked)
+	}
 
-	newTitle, newContent, err := appInst.EditorService.EditNote(note.Title, note.Content)
+	// An encrypted note is edited as plain text and sealed again on save
+	var passphrase string
+	if note.Encrypted {
+		if editStdin {
+			return fmt.Errorf("cannot replace %q from stdin: %w", note.Title, models.ErrEncrypted)
+		}
+		if passphrase, err = askPassphrase("Passphrase: "); err != nil {
+			return err
+		}
+		if note.Content, err = appInst.NoteService.Reveal(note, passphrase); err != nil {
+			return err
+		}
+	}
+
+	// The note's own content stays the baseline, so an applied template is
+	// saved even when the editor leaves it as it is
+	seed := note.Content
+	if editApply != "" {
+		template, err := findTemplate(ctx, editApply)
+		if err != nil {
+			return fmt.Errorf("failed to find template: %w", err)
+		}
+		seed = joinTemplate(note.Content, template.Render(note.Title, time.Now()), editPrepend)
+	}
+
+	var newTitle, newContent string
+	if editStdin {
+		newTitle, newContent, err = readEditFromStdin(note.Title)
+	} else {
+		newTitle, newContent, err = appInst.EditorService.EditNoteWith(editEditor, note.Title, seed)
+		if err != nil {
+			err = fmt.Errorf("editor error: %w", err)
+		}
+	}
 	if err != nil {
-		return fmt.Errorf("editor error: %w", err)
+		return err
+	}
+
+	if !note.HasChanges(newTitle, newContent) {
+		printInfo("No changes to note: %s\n", note.Title)
+		return nil
 	}
 
 	note.Title = newTitle
 	note.Content = newContent
 
-	if err := appInst.NoteService.Update(ctx, note); err != nil {
+	// The editor may have been open for a while
+	ctx, cancel = queryContext()
+	defer cancel()
+	if err := appInst.NoteService.UpdateEncrypted(ctx, note, passphrase); err != nil {
 		return fmt.Errorf("failed to update note: %w", err)
 	}
 
-	fmt.Printf("✨ Updated note: %s\n", newTitle)
+	printInfo("✨ Updated note: %s\n", newTitle)
+	return nil
+}
+
+// joinTemplate adds a rendered template after a note's content, or before
+// it when prepend is set
+func joinTemplate(content, rendered string, prepend bool) string {
+	content = strings.TrimSpace(content)
+	rendered = strings.TrimSpace(rendered)
+	switch {
+	case content == "":
+		return rendered
+	case prepend:
+		return rendered + "\n\n" + content
+	default:
+		return content + "\n\n" + rendered
+	}
+}
+
+// readEditFromStdin reads a note's new content from stdin. The title stays
+// unless --title replaces it.
+func readEditFromStdin(title string) (string, string, error) {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read stdin: %w", err)
+	}
+	content := string(data)
+	if strings.TrimSpace(content) == "" && !editEmpty {
+		return "", "", fmt.Errorf("%w: stdin is empty (use --allow-empty to clear the note)", models.ErrValidation)
+	}
+
+	if editTitle != "" {
+		title = strings.TrimSpace(editTitle)
+	}
+	return title, content, nil
+}
+
+// createInEditor opens the editor on a new note, seeded with the rendered
+// template when templateName is set, and creates the note from the result.
+// The note has no title yet, so {{title}} renders as a placeholder the
+// user can overwrite.
+func createInEditor(ctx context.Context, templateName, folderName string) error {
+	note := &models.Note{}
+	var seed string
+	if templateName != "" {
+		template, err := findTemplate(ctx, templateName)
+		if err != nil {
+			return fmt.Errorf("failed to find template: %w", err)
+		}
+		note.TemplateID = &template.ID
+		seed = template.Render(service.UntitledNoteTitle, time.Now())
+	}
+	if folderName != "" {
+		folder, err := findFolder(ctx, folderName)
+		if err != nil {
+			return fmt.Errorf("failed to find folder: %w", err)
+		}
+		note.FolderID = &folder.ID
+	}
+
+	title, content, err := appInst.EditorService.CreateNote(seed)
+	if errors.Is(err, service.ErrNothingWritten) {
+		printInfo("Nothing written, no note created\n")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("editor error: %w", err)
+	}
+
+	note.Title = title
+	note.Content = content
+	// The editor may have been open for a while
+	ctx, cancel := queryContext()
+	defer cancel()
+	if err := appInst.NoteService.Create(ctx, note); err != nil {
+		return fmt.Errorf("failed to create note: %w", err)
+	}
+
+	printInfo("✨ Created note #%d: %s\n", note.ID, note.Title)
 	return nil
 }
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useTestEditor points the editor at a script that runs the shell command
+// in $KIROKU_TEST_EDIT, with the file being edited as $1
+func useTestEditor(t *testing.T) {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), "editor.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\neval \"$KIROKU_TEST_EDIT\"\n"), 0755); err != nil {
+		t.Fatalf("write editor script: %v", err)
+	}
+	t.Setenv("EDITOR", script)
+}
+
+// runEditWith runs kiroku with args, editing with the shell command edit.
+// The edit flags keep their value between runs in one process, so they
+// are reset first.
+func runEditWith(t *testing.T, edit string, args ...string) (string, error) {
+	t.Helper()
+	editEditor, editNew, editTemplate, editFolder = "", false, "", ""
+	editStdin, editTitle, editEmpty, editApply, editPrepend = false, "", false, "", false
+	t.Setenv("KIROKU_TEST_EDIT", edit)
+	return runCLI(t, args...)
+}
+
+// showNote returns the title and content of a note
+func showNote(t *testing.T, id string) (string, string) {
+	t.Helper()
+	out, err := runCLI(t, "show", id, "-o", "json")
+	if err != nil {
+		t.Fatalf("show %s: %v", id, err)
+	}
+	var note struct {
+		Title   string `json:"title"`
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal([]byte(out), &note); err != nil {
+		t.Fatalf("decode note: %v (%q)", err, out)
+	}
+	return note.Title, note.Content
+}
+
+func TestEditApply(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		edit       string
+		wantErr    bool
+		wantTitle  string
+		wantPrefix string
+		wantSuffix string
+	}{
+		{
+			name:       "new note keeping the heading",
+			args:       []string{"edit", "--new", "--apply", "Meeting Notes"},
+			edit:       `printf 'met\n' >> "$1"`,
+			wantTitle:  "Untitled",
+			wantPrefix: "**Date:**",
+			wantSuffix: "met",
+		},
+		{
+			name:       "new note with a heading written",
+			args:       []string{"edit", "--new", "--apply", "Meeting Notes"},
+			edit:       `{ echo '# Kickoff'; tail -n +2 "$1"; } > "$1.tmp" && mv "$1.tmp" "$1"`,
+			wantTitle:  "Kickoff",
+			wantPrefix: "**Date:**",
+		},
+		{
+			name:    "new note prepending without a template",
+			args:    []string{"edit", "--new", "--prepend"},
+			wantErr: true,
+		},
+		{
+			name:       "existing note appended to",
+			args:       []string{"edit", "1", "--apply", "Daily Standup"},
+			wantTitle:  "first",
+			wantPrefix: "original body",
+			wantSuffix: "- None",
+		},
+		{
+			name:       "existing note prepended to",
+			args:       []string{"edit", "1", "--apply", "Daily Standup", "--prepend"},
+			wantTitle:  "first",
+			wantPrefix: "# Standup - ",
+			wantSuffix: "original body",
+		},
+		{
+			name:    "existing note prepending without a template",
+			args:    []string{"edit", "1", "--prepend"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			useTestEditor(t)
+			if _, err := runCLI(t, "add", "first"); err != nil {
+				t.Fatalf("add: %v", err)
+			}
+			if _, err := runEditWith(t, `printf '# first\n\noriginal body\n' > "$1"`, "edit", "1"); err != nil {
+				t.Fatalf("edit: %v", err)
+			}
+
+			_, err := runEditWith(t, tt.edit, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			id := "1"
+			if editNew {
+				id = "2"
+			}
+			title, content := showNote(t, id)
+			if title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", title, tt.wantTitle)
+			}
+			if strings.Contains(title+content, "{{") {
+				t.Errorf("placeholder left in note %q: %q", title, content)
+			}
+			if !strings.HasPrefix(content, tt.wantPrefix) {
+				t.Errorf("content = %q, want it to start with %q", content, tt.wantPrefix)
+			}
+			if !strings.HasSuffix(strings.TrimSpace(content), tt.wantSuffix) {
+				t.Errorf("content = %q, want it to end with %q", content, tt.wantSuffix)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+var encryptCmd = &cobra.Command{
+	Use:   "encrypt <id>",
+	Short: "Encrypt a note's content with a passphrase",
+	Long: `Encrypt a note's content with a passphrase (AES-256-GCM). The title,
+tags and other fields stay readable so the note can still be listed and
+found, but its content is not searchable, exported or shown until it is
+unlocked with the passphrase.
+
+The passphrase is asked for twice on the terminal, or read as a line from
+stdin when it is piped. It is not stored anywhere: a lost passphrase means
+a lost note.
+
+Read an encrypted note with "kiroku decrypt <id> --print", edit it with
+"kiroku edit <id>", or press U in the TUI to unlock it for the session.
+
+Examples:
+  kiroku encrypt 42`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEncrypt,
+}
+
+var decryptCmd = &cobra.Command{
+	Use:   "decrypt <id>",
+	Short: "Remove the encryption from a note",
+	Long: `Decrypt an encrypted note and store its content as plain text again.
+With --print, print the content instead and leave the note encrypted.
+
+Examples:
+  kiroku decrypt 42
+  kiroku decrypt 42 --print`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDecrypt,
+}
+
+var decryptPrint bool
+
+func init() {
+	decryptCmd.Flags().BoolVar(&decryptPrint, "print", false, "print the content and keep the note encrypted")
+}
+
+func runEncrypt(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	note, err := noteArg(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if note.Encrypted {
+		return fmt.Errorf("%w: %q is already encrypted", models.ErrValidation, note.Title)
+	}
+
+	passphrase, err := readPassphrase("New passphrase: ")
+	if err != nil {
+		return err
+	}
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		again, err := readPassphrase("Repeat passphrase: ")
+		if err != nil {
+			return err
+		}
+		if again != passphrase {
+			return fmt.Errorf("%w: passphrases do not match", models.ErrValidation)
+		}
+	}
+
+	// The prompt may have waited on the user for a while
+	ctx, cancel = queryContext()
+	defer cancel()
+	if err := appInst.NoteService.Encrypt(ctx, note.ID, passphrase); err != nil {
+		return fmt.Errorf("failed to encrypt note: %w", err)
+	}
+	printInfo("🔐 Encrypted note: %s\n", note.Title)
+	return nil
+}
+
+func runDecrypt(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	note, err := noteArg(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if !note.Encrypted {
+		return fmt.Errorf("%w: %q is not encrypted", models.ErrValidation, note.Title)
+	}
+
+	passphrase, err := askPassphrase("Passphrase: ")
+	if err != nil {
+		return err
+	}
+
+	if decryptPrint {
+		content, err := appInst.NoteService.Reveal(note, passphrase)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt note: %w", err)
+		}
+		fmt.Println(strings.TrimRight(content, "\n"))
+		return nil
+	}
+
+	// The prompt may have waited on the user for a while
+	ctx, cancel = queryContext()
+	defer cancel()
+	if err := appInst.NoteService.Decrypt(ctx, note.ID, passphrase); err != nil {
+		return fmt.Errorf("failed to decrypt note: %w", err)
+	}
+	printInfo("🔓 Decrypted note: %s\n", note.Title)
+	return nil
+}
+
+// noteArg looks up the note whose ID is arg
+func noteArg(ctx context.Context, arg string) (*models.Note, error) {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid note ID %q", models.ErrValidation, arg)
+	}
+	note, err := appInst.NoteService.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("note not found: %w", err)
+	}
+	return note, nil
+}
+
+// askPassphrase reads the passphrase that opens encrypted notes. Without a
+// terminal it has to be piped on stdin, so nothing is ever revealed to a
+// script that did not supply it.
+func askPassphrase(prompt string) (string, error) {
+	passphrase, err := readPassphrase(prompt)
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("%w: a passphrase is required; type it at the prompt or pipe it on stdin", models.ErrValidation)
+	}
+	return passphrase, nil
+}
+
+// readPassphrase asks for a passphrase without echoing it, or reads it as
+// the first line of stdin when stdin is not a terminal
+func readPassphrase(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return string(passphrase), nil
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"errors"
+	"io/fs"
+
+	"github.com/tranducquang/kiroku/internal/database"
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/repository"
+)
+
+// Exit codes returned by the CLI so scripts can branch on failure reasons.
+const (
+	ExitGeneric    = 1
+	ExitNotFound   = 2
+	ExitValidation = 3
+	ExitStorage    = 4
+)
+
+// exitCodeHelp documents the exit codes in the root command help.
+const exitCodeHelp = `
+Exit codes:
+  0  success
+  1  generic error
+  2  not found (e.g. unknown note ID)
+  3  invalid input
+  4  database or file system error`
+
+// exitCode maps an error to the process exit code for its category.
+func exitCode(err error) int {
+	var pathErr *fs.PathError
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, models.ErrValidation):
+		return ExitValidation
+	case errors.As(err, &pathErr), database.IsDatabaseError(err):
+		return ExitStorage
+	default:
+		return ExitGeneric
+	}
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/tranducquang/kiroku/internal/service"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export <dir>",
+	Short: "Export notes as markdown files",
+	Long: `Export every note to dir as a markdown file named by its title, in
+directories mirroring its folder path. Names are made safe for any OS;
+when two notes would share a file, the newer one gets its ID appended,
+e.g. "Standup (42).md".
+
+Each file carries its note ID in frontmatter, and manifest.json maps
+every ID to its file, so an export can be read back with
+"kiroku import --on-conflict overwrite". Existing files are overwritten.
+Encrypted notes are skipped unless --decrypt is given. It asks for their
+passphrase, which must be piped on stdin when there is no terminal, and
+writes them in plain text; nothing is written if it does not open them all.
+
+Examples:
+  kiroku export ~/notes-backup
+  kiroku export ./out -o json
+  kiroku export ./out --decrypt`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExport,
+}
+
+var exportDecrypt bool
+
+func init() {
+	exportCmd.Flags().BoolVar(&exportDecrypt, "decrypt", false, "include encrypted notes in plain text (asks for the passphrase)")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	dir := args[0]
+
+	var passphrase string
+	if exportDecrypt {
+		var err error
+		if passphrase, err = askPassphrase("Passphrase for encrypted notes: "); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := queryContext()
+	defer cancel()
+	exported, err := appInst.NoteService.Export(ctx, dir, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to export notes: %w", err)
+	}
+
+	if jsonOutput() {
+		return printJSON(exported)
+	}
+	printInfo("📦 Exported %d note(s) to %s\n", len(exported), dir)
+	printInfo("   Manifest: %s\n", filepath.Join(dir, service.ExportManifestName))
+	return nil
+}
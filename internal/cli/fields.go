@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+// fieldTimeFormat is how list --fields prints created and updated times
+const fieldTimeFormat = "2006-01-02 15:04"
+
+// noteField renders one column of list --fields output. folders maps
+// folder IDs to their full paths.
+type noteField func(note *models.Note, folders map[int64]string) string
+
+// noteFields maps the names --fields accepts to their renderers
+var noteFields = map[string]noteField{
+	"id": func(n *models.Note, _ map[int64]string) string {
+		return strconv.FormatInt(n.ID, 10)
+	},
+	"title": func(n *models.Note, _ map[int64]string) string {
+		return n.Title
+	},
+	"folder": func(n *models.Note, folders map[int64]string) string {
+		if n.FolderID == nil {
+			return ""
+		}
+		return folders[*n.FolderID]
+	},
+	"status": func(n *models.Note, _ map[int64]string) string {
+		switch {
+		case !n.IsTodo:
+			return "note"
+		case n.IsDone:
+			return "done"
+		default:
+			return "todo"
+		}
+	},
+	"due": func(n *models.Note, _ map[int64]string) string {
+		if n.DueDate == nil {
+			return ""
+		}
+		return n.DueDate.Format(models.DueDateFormat)
+	},
+	"priority": func(n *models.Note, _ map[int64]string) string {
+		if n.Priority == models.PriorityNone {
+			return ""
+		}
+		return strings.ToLower(n.PriorityString())
+	},
+	"label": func(n *models.Note, _ map[int64]string) string {
+		return n.Label
+	},
+	"tags": func(n *models.Note, _ map[int64]string) string {
+		return strings.Join(n.TagList(), ",")
+	},
+	"starred": func(n *models.Note, _ map[int64]string) string {
+		return strconv.FormatBool(n.Starred)
+	},
+	"created": func(n *models.Note, _ map[int64]string) string {
+		return n.CreatedAt.Format(fieldTimeFormat)
+	},
+	"updated": func(n *models.Note, _ map[int64]string) string {
+		return n.UpdatedAt.Format(fieldTimeFormat)
+	},
+}
+
+// noteFieldNames lists the fields in the order the help and errors show them
+var noteFieldNames = []string{"id", "title", "folder", "status", "due", "priority", "label", "tags", "starred", "created", "updated"}
+
+// parseFields validates a --fields list, keeping the order given
+func parseFields(names []string) ([]noteField, error) {
+	fields := make([]noteField, 0, len(names))
+	for _, name := range names {
+		field, ok := noteFields[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q (use %s)", models.ErrValidation, name, strings.Join(noteFieldNames, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// folderPaths maps every folder ID to its full path
+func folderPaths(ctx context.Context) (map[int64]string, error) {
+	folders, err := appInst.FolderService.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load folders: %w", err)
+	}
+
+	byID := make(map[int64]*models.Folder, len(folders))
+	for _, folder := range folders {
+		byID[folder.ID] = folder
+	}
+	paths := make(map[int64]string, len(folders))
+	for _, folder := range folders {
+		names := []string{folder.Name}
+		// The depth bound guards against a corrupt parent cycle
+		for parent := folder.ParentID; parent != nil && len(names) <= len(folders); {
+			p, ok := byID[*parent]
+			if !ok {
+				break
+			}
+			names = append([]string{p.Name}, names...)
+			parent = p.ParentID
+		}
+		paths[folder.ID] = strings.Join(names, models.FolderPathSeparator)
+	}
+	return paths, nil
+}
+
+// printFields prints one tab-separated row per note. Tabs and newlines in
+// values become spaces so every note stays on one line.
+func printFields(notes []*models.Note, fields []noteField, folders map[int64]string) {
+	clean := strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+	values := make([]string, len(fields))
+	for _, note := range notes {
+		for i, field := range fields {
+			values[i] = clean.Replace(field(note, folders))
+		}
+		fmt.Println(strings.Join(values, "\t"))
+	}
+}
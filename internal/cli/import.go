@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/service"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file|dir>...",
+	Short: "Import markdown files as notes",
+	Long: `Import markdown files as notes, one note per file. Directories are
+searched recursively for .md files.
+
+The title comes from a "title:" frontmatter key, a leading "# " heading,
+the first line, or the file name. A file matches an existing note by an
+"id:" frontmatter key, or else by exact title. --on-conflict decides what
+happens to a match:
+
+  duplicate  always create a new note (default; never loses data)
+  skip       leave the existing note and ignore the file
+  overwrite  replace the existing note's title and content
+
+Examples:
+  kiroku import meeting.md
+  kiroku import ~/old-notes --folder archive
+  kiroku import ~/notes --on-conflict overwrite`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runImport,
+}
+
+var (
+	importOnConflict string
+	importFolder     string
+)
+
+func init() {
+	importCmd.Flags().StringVar(&importOnConflict, "on-conflict", service.ImportDuplicate, "what to do with a matching note: "+strings.Join(service.ImportPolicies, ", "))
+	importCmd.Flags().StringVarP(&importFolder, "folder", "f", "", "folder for new notes")
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	if !slices.Contains(service.ImportPolicies, importOnConflict) {
+		return fmt.Errorf("%w: unknown conflict policy %q (use %s)",
+			models.ErrValidation, importOnConflict, strings.Join(service.ImportPolicies, ", "))
+	}
+
+	var folderID *int64
+	if importFolder != "" {
+		folder, err := findFolder(ctx, importFolder)
+		if err != nil {
+			return err
+		}
+		folderID = &folder.ID
+	}
+
+	files, err := markdownFiles(args)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		printInfo("No markdown files found.\n")
+		return nil
+	}
+
+	counts := make(map[string]int)
+	failed := 0
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %s: %v\n", path, err)
+			failed++
+			continue
+		}
+
+		fallback := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		// Each file gets the full timeout, however many there are
+		fileCtx, cancel := queryContext()
+		result, err := appInst.NoteService.Import(fileCtx, string(data), fallback, folderID, importOnConflict)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %s: %v\n", path, err)
+			failed++
+			continue
+		}
+		counts[result.Action]++
+		if result.TitleTruncated {
+			fmt.Fprintf(os.Stderr, "✂️  %s: title truncated to %d characters\n", path, appInst.Config.Database.TitleMaxLength)
+		}
+		printInfo("%s %s: %s #%d %s\n", importIcon(result.Action), path, result.Action, result.Note.ID, result.Note.Title)
+	}
+
+	printInfo("\n📥 Imported %d file(s): %d created, %d skipped, %d overwritten\n",
+		len(files)-failed, counts[service.ImportCreated], counts[service.ImportSkipped], counts[service.ImportOverwritten])
+	if failed > 0 {
+		return fmt.Errorf("failed to import %d file(s)", failed)
+	}
+	return nil
+}
+
+// importIcon returns the prefix for a file's import result
+func importIcon(action string) string {
+	switch action {
+	case service.ImportSkipped:
+		return "⏭️ "
+	case service.ImportOverwritten:
+		return "✏️ "
+	default:
+		return "✨"
+	}
+}
+
+// markdownFiles expands the arguments into files, walking directories for
+// .md files in name order
+func markdownFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".md") {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+var linksCmd = &cobra.Command{
+	Use:   "links",
+	Short: "Maintain [[Title]] links between notes",
+	Long:  `Check the [[Title]] links between notes and repair the broken ones.`,
+}
+
+var linksCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Report dangling links and orphan notes",
+	Long: `Resolve every [[Title]] link, ignoring case, and report the links to
+notes that do not exist and the notes no other note links to. Links inside
+fenced code blocks are skipped, and links inside encrypted notes are not
+seen.
+
+With --fix, offer to create an empty note for each missing title.
+Exits non-zero while dangling links remain.
+
+Examples:
+  kiroku links check
+  kiroku links check -o json
+  kiroku links check --fix`,
+	Args: cobra.NoArgs,
+	RunE: runLinksCheck,
+}
+
+var (
+	linksFix   bool
+	linksForce bool
+)
+
+func init() {
+	linksCheckCmd.Flags().BoolVar(&linksFix, "fix", false, "offer to create a note for each missing link target")
+	linksCheckCmd.Flags().BoolVar(&linksForce, "force", false, "with --fix, create them without asking")
+	linksCmd.AddCommand(linksCheckCmd)
+}
+
+func runLinksCheck(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	if linksFix && jsonOutput() {
+		return fmt.Errorf("%w: --fix cannot be combined with -o json", models.ErrValidation)
+	}
+
+	report, err := appInst.NoteService.CheckLinks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check links: %w", err)
+	}
+
+	if jsonOutput() {
+		if err := printJSON(report); err != nil {
+			return err
+		}
+		return danglingError(len(report.Dangling))
+	}
+
+	if len(report.Dangling) == 0 {
+		printInfo("✅ No dangling links\n")
+	} else {
+		printInfo("🔗 %d dangling link(s):\n", len(report.Dangling))
+		for _, link := range report.Dangling {
+			fmt.Printf("[%d] %s → [[%s]]\n", link.NoteID, link.NoteTitle, link.Target)
+		}
+	}
+	if len(report.Orphans) > 0 {
+		printInfo("\n🌱 %d note(s) nothing links to:\n", len(report.Orphans))
+		for _, orphan := range report.Orphans {
+			fmt.Printf("[%d] %s\n", orphan.ID, orphan.Title)
+		}
+	}
+
+	if !linksFix || len(report.Dangling) == 0 {
+		return danglingError(len(report.Dangling))
+	}
+
+	// Several notes may link to the same missing title; each is asked once
+	counts := make(map[string]int)
+	var targets []string
+	for _, link := range report.Dangling {
+		key := strings.ToLower(link.Target)
+		if counts[key] == 0 {
+			targets = append(targets, link.Target)
+		}
+		counts[key]++
+	}
+
+	printInfo("\n")
+	remaining := 0
+	for _, target := range targets {
+		ok, err := confirm(linksForce, fmt.Sprintf("Create note %q?", target))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			remaining += counts[strings.ToLower(target)]
+			continue
+		}
+
+		// The prompt may have waited on the user for a while
+		createCtx, cancel := queryContext()
+		note := &models.Note{Title: target}
+		err = appInst.NoteService.Create(createCtx, note)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to create note %q: %w", target, err)
+		}
+		printInfo("✨ Created note #%d: %s\n", note.ID, note.Title)
+	}
+	return danglingError(remaining)
+}
+
+// danglingError fails the command while dangling links remain
+func danglingError(count int) error {
+	if count == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d dangling link(s)", count)
+}
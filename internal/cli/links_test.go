@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLinksCheck(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if _, err := runCLI(t, "add", "lonely"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"text", []string{"links", "check"}, "[1] lonely\n"},
+		{"json", []string{"links", "check", "-o", "json"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runCLI(t, tt.args...)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if tt.want != "" {
+				if !strings.Contains(out, tt.want) {
+					t.Errorf("stdout = %q, want it to contain %q", out, tt.want)
+				}
+				return
+			}
+
+			var report struct {
+				Dangling []map[string]any `json:"dangling"`
+				Orphans  []map[string]any `json:"orphans"`
+			}
+			if err := json.Unmarshal([]byte(out), &report); err != nil {
+				t.Fatalf("decode report: %v (%q)", err, out)
+			}
+			want := []map[string]any{{"id": float64(1), "title": "lonely"}}
+			if !reflect.DeepEqual(report.Orphans, want) {
+				t.Errorf("orphans = %v, want %v", report.Orphans, want)
+			}
+			if report.Dangling == nil || len(report.Dangling) != 0 {
+				t.Errorf("dangling = %v, want an empty list", report.Dangling)
+			}
+		})
+	}
+}
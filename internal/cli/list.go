@@ -3,9 +3,13 @@ package cli
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/repository"
 )
 
 var listCmd = &cobra.Command{
@@ -17,31 +21,87 @@ Examples:
   kiroku list
   kiroku list --todos
   kiroku list --starred
-  kiroku list --folder work`,
+  kiroku list --recent -n 5
+  kiroku list --folder work
+  kiroku list --tags go,cli
+  kiroku list --tags urgent,blocked --tags-match any --todos
+  kiroku list --label red
+  kiroku list --sort title
+  kiroku list --todos --sort due --reverse
+  kiroku list --due overdue
+  kiroku list --due week -o json
+  kiroku list --older-than 6mo --sort updated --reverse
+  kiroku list --newer-than 2w --by created
+  kiroku list --fields id,title,folder,due | cut -f2`,
 	RunE: runList,
 }
 
+// Values accepted by --tags-match
+const (
+	tagsMatchAll = "all"
+	tagsMatchAny = "any"
+)
+
+// Values accepted by --by
+const (
+	ageByUpdated = "updated"
+	ageByCreated = "created"
+)
+
 var (
-	listTodos   bool
-	listStarred bool
-	listFolder  string
-	listLimit   int
+	listTodos     bool
+	listStarred   bool
+	listFolder    string
+	listLimit     int
+	listTags      []string
+	listTagsMatch string
+	listLabel     string
+	listSort      string
+	listReverse   bool
+	listFields    []string
+	listDue       string
+	listRecent    bool
+	listOlderThan string
+	listNewerThan string
+	listAgeBy     string
 )
 
 func init() {
 	listCmd.Flags().BoolVarP(&listTodos, "todos", "t", false, "list only todos")
 	listCmd.Flags().BoolVarP(&listStarred, "starred", "s", false, "list only starred")
+	listCmd.Flags().BoolVar(&listRecent, "recent", false, "list the most recently updated notes first")
 	listCmd.Flags().StringVarP(&listFolder, "folder", "f", "", "filter by folder")
 	listCmd.Flags().IntVarP(&listLimit, "limit", "n", 20, "max number of items")
+	listCmd.Flags().StringSliceVar(&listTags, "tags", nil, "filter by comma-separated tags")
+	listCmd.Flags().StringVar(&listTagsMatch, "tags-match", tagsMatchAll, "tag matching: all or any")
+	listCmd.Flags().StringVar(&listLabel, "label", "", "filter by label color")
+	listCmd.Flags().StringVar(&listSort, "sort", "", "sort by created, updated, title, priority, due or manual")
+	listCmd.Flags().BoolVarP(&listReverse, "reverse", "r", false, "reverse the sort order")
+	listCmd.Flags().StringVar(&listDue, "due", "", "list open todos due today, this week (next 7 days), overdue or none")
+	listCmd.Flags().StringVar(&listOlderThan, "older-than", "", "list notes last changed longer ago than an age such as 7d, 2w or 1mo")
+	listCmd.Flags().StringVar(&listNewerThan, "newer-than", "", "list notes changed within an age such as 7d, 2w or 1mo")
+	listCmd.Flags().StringVar(&listAgeBy, "by", ageByUpdated, "time --older-than and --newer-than compare: updated or created")
+	listCmd.Flags().StringSliceVar(&listFields, "fields", nil, "print only these fields, tab-separated (e.g. id,title,due)")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	fields, err := parseFields(listFields)
+	if err != nil {
+		return err
+	}
 
 	var notes []*models.Note
-	var err error
 
+	filtered := len(listTags) > 0 || listFolder != "" || listLabel != "" || listDue != "" || listSort != "" || listReverse ||
+		listOlderThan != "" || listNewerThan != ""
 	switch {
+	case listRecent && !filtered && !listTodos && !listStarred:
+		notes, err = appInst.NoteService.GetRecent(ctx, listLimit)
+	case filtered || listRecent:
+		notes, err = listFiltered(ctx)
 	case listTodos:
 		notes, err = appInst.NoteService.GetTodos(ctx, true)
 	case listStarred:
@@ -54,8 +114,24 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list notes: %w", err)
 	}
 
+	if jsonOutput() {
+		if notes == nil {
+			notes = []*models.Note{}
+		}
+		return printJSON(notes)
+	}
+
 	if len(notes) == 0 {
-		fmt.Println("No notes found.")
+		printInfo("No notes found.\n")
+		return nil
+	}
+
+	if len(fields) > 0 {
+		folders, err := folderPaths(ctx)
+		if err != nil {
+			return err
+		}
+		printFields(notes, fields, folders)
 		return nil
 	}
 
@@ -77,3 +153,120 @@ func runList(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// listFiltered lists notes by folder, tags, label and due range, combined
+// with the --todos and --starred flags, in the --sort order.
+func listFiltered(ctx context.Context) ([]*models.Note, error) {
+	if listRecent && (listSort != "" || listReverse) {
+		return nil, fmt.Errorf("%w: --recent always sorts by update time", models.ErrValidation)
+	}
+
+	sortKey := listSort
+	switch {
+	case sortKey != "":
+	case listRecent:
+		sortKey = "updated"
+	case listDue != "":
+		sortKey = "due"
+	default:
+		sortKey = "updated"
+	}
+	order, err := models.ParseNoteSort(sortKey)
+	if err != nil {
+		return nil, err
+	}
+	opts := models.ListOptions{
+		OrderBy:   order.Column,
+		OrderDesc: order.Desc != listReverse,
+		Limit:     listLimit,
+	}
+	if listTodos {
+		isTodo := true
+		opts.IsTodo = &isTodo
+	}
+	if listStarred {
+		starred := true
+		opts.Starred = &starred
+	}
+	if listDue != "" {
+		if err := opts.ApplyDueRange(listDue, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyAge(&opts, time.Now()); err != nil {
+		return nil, err
+	}
+	if listLabel != "" {
+		label, err := models.ParseLabel(listLabel)
+		if err != nil {
+			return nil, err
+		}
+		opts.Label = label
+	}
+	if listFolder != "" {
+		folder, err := findFolder(ctx, listFolder)
+		if err != nil {
+			return nil, err
+		}
+		opts.FolderID = &folder.ID
+	}
+
+	if len(listTags) == 0 {
+		return appInst.NoteService.List(ctx, opts)
+	}
+
+	switch listTagsMatch {
+	case tagsMatchAll, tagsMatchAny:
+	default:
+		return nil, fmt.Errorf("%w: unknown tag match %q (use all or any)", models.ErrValidation, listTagsMatch)
+	}
+	return appInst.NoteService.ListByTags(ctx, opts, listTags, listTagsMatch == tagsMatchAll)
+}
+
+// applyAge narrows opts to notes updated, or created with --by created,
+// before --older-than and after --newer-than
+func applyAge(opts *models.ListOptions, now time.Time) error {
+	var until, since *time.Time
+	if listOlderThan != "" {
+		t, err := models.ParseAge(listOlderThan, now)
+		if err != nil {
+			return err
+		}
+		until = &t
+	}
+	if listNewerThan != "" {
+		t, err := models.ParseAge(listNewerThan, now)
+		if err != nil {
+			return err
+		}
+		since = &t
+	}
+
+	switch listAgeBy {
+	case ageByUpdated:
+		opts.UpdatedUntil, opts.UpdatedSince = until, since
+	case ageByCreated:
+		opts.CreatedUntil, opts.CreatedSince = until, since
+	default:
+		return fmt.Errorf("%w: unknown --by %q (use updated or created)", models.ErrValidation, listAgeBy)
+	}
+	return nil
+}
+
+// findFolder resolves a folder by ID or case-insensitive name.
+func findFolder(ctx context.Context, nameOrID string) (*models.Folder, error) {
+	if id, err := strconv.ParseInt(nameOrID, 10, 64); err == nil {
+		return appInst.FolderService.GetByID(ctx, id)
+	}
+
+	folders, err := appInst.FolderService.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, folder := range folders {
+		if strings.EqualFold(folder.Name, nameOrID) {
+			return folder, nil
+		}
+	}
+	return nil, fmt.Errorf("folder %q: %w", nameOrID, repository.ErrNotFound)
+}
@@ -5,12 +5,12 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
 	"sort"
 
 	"github.com/spf13/cobra"
 
 	"github.com/tranducquang/kiroku/internal/logging"
+	"github.com/tranducquang/kiroku/internal/platform"
 )
 
 var logsCmd = &cobra.Command{
@@ -49,7 +49,7 @@ var logsTailCmd = &cobra.Command{
 			return err
 		}
 
-		fmt.Printf("📋 Tailing: %s\n\n", latestLog)
+		printInfo("📋 Tailing: %s\n\n", latestLog)
 
 		// Use tail -f on Unix systems
 		tailCmd := exec.Command("tail", "-f", latestLog)
@@ -106,7 +106,7 @@ var logsClearCmd = &cobra.Command{
 			}
 		}
 
-		fmt.Printf("🗑️  Cleared %d log files\n", count)
+		printInfo("🗑️  Cleared %d log files\n", count)
 		return nil
 	},
 }
@@ -115,21 +115,7 @@ var logsOpenCmd = &cobra.Command{
 	Use:   "open",
 	Short: "Open the logs directory in file manager",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		logDir := logging.GetLogDir()
-
-		var openCmd *exec.Cmd
-		switch runtime.GOOS {
-		case "darwin":
-			openCmd = exec.Command("open", logDir)
-		case "linux":
-			openCmd = exec.Command("xdg-open", logDir)
-		case "windows":
-			openCmd = exec.Command("explorer", logDir)
-		default:
-			return fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
-		}
-
-		return openCmd.Start()
+		return platform.OpenPath(logging.GetLogDir())
 	},
 }
 
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <dst> <src...>",
+	Short: "Merge notes into another note",
+	Long: `Append the content of each source note to the destination note, each
+under a "## " heading with the source's title, in the order given. The
+destination also gets the sources' tags. The sources are then deleted,
+unless --keep-sources is given; either way it happens in one transaction.
+Deleting the sources asks for confirmation unless --force is given, as
+cli.confirm sets.
+
+Examples:
+  kiroku merge 12 14 15
+  kiroku merge 12 14 15 --force
+  kiroku merge 12 14 --keep-sources`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runMerge,
+}
+
+var (
+	mergeKeepSources bool
+	mergeForce       bool
+)
+
+func init() {
+	mergeCmd.Flags().BoolVar(&mergeKeepSources, "keep-sources", false, "keep the source notes after merging")
+	mergeCmd.Flags().BoolVar(&mergeForce, "force", false, "delete the source notes without asking")
+}
+
+func runMerge(cmd *cobra.Command, args []string) error {
+	ids := make([]int64, len(args))
+	for i, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: invalid note ID %q", models.ErrValidation, arg)
+		}
+		ids[i] = id
+	}
+	if slices.Contains(ids[1:], ids[0]) {
+		return fmt.Errorf("%w: cannot merge note %d into itself", models.ErrValidation, ids[0])
+	}
+
+	if !mergeKeepSources {
+		ok, err := confirm(mergeForce, fmt.Sprintf("Merge %d note(s) into #%d and delete them?", len(ids)-1, ids[0]))
+		if err != nil || !ok {
+			return err
+		}
+	}
+	// Taken after the prompt, which may have waited on the user for a while
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	note, err := appInst.NoteService.Merge(ctx, ids[0], ids[1:], mergeKeepSources)
+	if err != nil {
+		return fmt.Errorf("failed to merge notes: %w", err)
+	}
+
+	printInfo("🔗 Merged %d note(s) into [%d] %s\n", len(ids)-1, note.ID, note.Title)
+	return nil
+}
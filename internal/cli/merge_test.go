@@ -0,0 +1,38 @@
+package cli
+
+import "testing"
+
+func TestMergeConfirm(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantErr     bool
+		wantSources bool
+	}{
+		{"asks before deleting", []string{"merge", "1", "2"}, true, true},
+		{"force deletes", []string{"merge", "1", "2", "--force"}, false, false},
+		{"keeping sources needs no force", []string{"merge", "1", "2", "--keep-sources"}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			// Without a terminal to ask on, the command must be forced
+			t.Setenv(confirmEnv, "never")
+			for _, title := range []string{"target", "source"} {
+				if _, err := runCLI(t, "add", title); err != nil {
+					t.Fatalf("add: %v", err)
+				}
+			}
+
+			mergeKeepSources, mergeForce = false, false
+			_, err := runCLI(t, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			_, err = runCLI(t, "show", "2")
+			if exists := err == nil; exists != tt.wantSources {
+				t.Errorf("source exists = %v, want %v", exists, tt.wantSources)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/service"
+)
+
+var openCmd = &cobra.Command{
+	Use:   "open <id>",
+	Short: "Open a note in the system default app",
+	Long: `Write a note to a markdown file and open it in the system default app
+(open, xdg-open or explorer), for when a GUI editor suits better than the
+terminal one.
+
+The file is not read back by default. With --watch, kiroku keeps running
+and saves the file into the note each time it changes, until Ctrl+C.
+Otherwise "kiroku import <file> --on-conflict overwrite" brings edits back.
+
+Examples:
+  kiroku open 42
+  kiroku open 42 --watch`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOpen,
+}
+
+// openPollInterval is how often --watch checks the file for changes
+const openPollInterval = time.Second
+
+var openWatch bool
+
+func init() {
+	openCmd.Flags().BoolVarP(&openWatch, "watch", "w", false, "save changes to the file back into the note until Ctrl+C")
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid note ID %q", models.ErrValidation, args[0])
+	}
+
+	note, err := appInst.NoteService.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("note not found: %w", err)
+	}
+
+	path, err := appInst.EditorService.OpenExternal(note)
+	if err != nil {
+		return fmt.Errorf("failed to open note: %w", err)
+	}
+	printInfo("📂 Opened %s in the default app\n", path)
+	if !openWatch {
+		return nil
+	}
+
+	printInfo("👀 Watching for changes, Ctrl+C to stop\n")
+	// The watch runs until Ctrl+C; each save gets its own timeout
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return watchOpenedNote(ctx, note, path)
+}
+
+// watchOpenedNote saves the file into the note whenever its modification
+// time changes, until ctx is cancelled. A failed save is reported and
+// retried on the next change rather than ending the watch.
+func watchOpenedNote(ctx context.Context, note *models.Note, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to watch note file: %w", err)
+	}
+	modified := info.ModTime()
+
+	ticker := time.NewTicker(openPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil || info.ModTime().Equal(modified) {
+			continue
+		}
+		modified = info.ModTime()
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ %s: %v\n", path, err)
+			continue
+		}
+		saveCtx, cancel := queryContext()
+		result, err := appInst.NoteService.Import(saveCtx, string(data), note.Title, nil, service.ImportOverwrite)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ failed to save note: %v\n", err)
+			continue
+		}
+		if result.TitleTruncated {
+			fmt.Fprintf(os.Stderr, "✂️  title truncated to %d characters\n", appInst.Config.Database.TitleMaxLength)
+		}
+		printInfo("✏️  Saved #%d %s\n", result.Note.ID, result.Note.Title)
+	}
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+// Output formats accepted by --output
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+var (
+	outputFormat string
+	quiet        bool
+)
+
+// validateOutput rejects unknown --output values.
+func validateOutput() error {
+	switch outputFormat {
+	case outputText, outputJSON:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown output format %q (use text or json)", models.ErrValidation, outputFormat)
+	}
+}
+
+// jsonOutput reports whether commands should print JSON.
+func jsonOutput() bool {
+	return outputFormat == outputJSON
+}
+
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return nil
+}
+
+// printInfo prints a confirmation or status line unless --quiet is set.
+// What a command was asked for (listings, counts, JSON) goes to stdout
+// directly so it survives --quiet.
+func printInfo(format string, args ...any) {
+	if quiet {
+		return
+	}
+	fmt.Printf(format, args...)
+}
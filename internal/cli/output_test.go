@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// runCLI runs kiroku with args against the home directory set by the
+// test and returns what it printed to stdout
+func runCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	// Persistent flags keep their value between runs in one process, and
+	// viper the config file it last read
+	quiet, outputFormat = false, outputText
+	viper.Reset()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- string(out)
+	}()
+
+	rootCmd.SetArgs(args)
+	// Usage and errors are printed by cobra, not by the commands under test
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	runErr := rootCmd.Execute()
+	w.Close()
+	return <-done, runErr
+}
+
+func TestQuietOutput(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantErr  bool
+		wantOut  string // "" expects no output at all
+		wantJSON bool
+	}{
+		{name: "confirmation", args: []string{"add", "first"}, wantOut: "Created note: first"},
+		{name: "quiet confirmation", args: []string{"add", "second", "-q"}},
+		{name: "quiet listing", args: []string{"list", "-q"}, wantOut: "second"},
+		{name: "quiet JSON", args: []string{"list", "-q", "-o", "json"}, wantOut: `"title": "second"`, wantJSON: true},
+		{name: "quiet empty result", args: []string{"list", "--tags", "missing", "-q"}},
+		{name: "quiet error", args: []string{"show", "999", "-q"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runCLI(t, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantOut == "" {
+				if out != "" {
+					t.Errorf("stdout = %q, want nothing", out)
+				}
+				return
+			}
+			if !strings.Contains(out, tt.wantOut) {
+				t.Errorf("stdout = %q, want it to contain %q", out, tt.wantOut)
+			}
+			if tt.wantJSON && !json.Valid([]byte(out)) {
+				t.Errorf("stdout is not JSON: %q", out)
+			}
+		})
+	}
+}
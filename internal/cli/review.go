@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "List notes that have gone unchanged for a while",
+	Long: `List notes not updated within an age, oldest first, to revisit or prune
+them. The age defaults to ui.review_age (60d). Starred notes are kept on
+purpose and left out unless --starred is given.
+
+Act on what it lists with the usual commands, e.g. kiroku star to keep a
+note out of the review, or the Review view in the TUI, where d deletes,
+s stars and m moves the selected note.
+
+Examples:
+  kiroku review
+  kiroku review --older-than 6mo
+  kiroku review --fields id,updated,title`,
+	Args: cobra.NoArgs,
+	RunE: runReview,
+}
+
+var (
+	reviewOlderThan string
+	reviewStarred   bool
+	reviewLimit     int
+	reviewFields    []string
+)
+
+func init() {
+	reviewCmd.Flags().StringVar(&reviewOlderThan, "older-than", "", "age such as 60d, 8w or 3mo (default ui.review_age)")
+	reviewCmd.Flags().BoolVarP(&reviewStarred, "starred", "s", false, "include starred notes")
+	reviewCmd.Flags().IntVarP(&reviewLimit, "limit", "n", 0, "max number of notes (0 for all)")
+	reviewCmd.Flags().StringSliceVar(&reviewFields, "fields", nil, "print only these fields, tab-separated (e.g. id,title,updated)")
+}
+
+func runReview(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	fields, err := parseFields(reviewFields)
+	if err != nil {
+		return err
+	}
+
+	age := reviewOlderThan
+	if age == "" {
+		age = appInst.Config.UI.ReviewAge
+	}
+	now := time.Now()
+	cutoff, err := models.ParseAge(age, now)
+	if err != nil {
+		return err
+	}
+
+	notes, err := appInst.NoteService.GetStale(ctx, cutoff, reviewStarred)
+	if err != nil {
+		return fmt.Errorf("failed to list notes: %w", err)
+	}
+	if reviewLimit > 0 && len(notes) > reviewLimit {
+		notes = notes[:reviewLimit]
+	}
+
+	if jsonOutput() {
+		if notes == nil {
+			notes = []*models.Note{}
+		}
+		return printJSON(notes)
+	}
+
+	if len(notes) == 0 {
+		printInfo("🌱 Nothing unchanged for %s\n", age)
+		return nil
+	}
+
+	if len(fields) > 0 {
+		folders, err := folderPaths(ctx)
+		if err != nil {
+			return err
+		}
+		printFields(notes, fields, folders)
+		return nil
+	}
+
+	printInfo("🧹 %d note(s) unchanged for %s:\n\n", len(notes), age)
+	for _, note := range notes {
+		days := int(now.Sub(note.UpdatedAt).Hours() / 24)
+		fmt.Printf("[%d] %s (%d days)\n", note.ID, note.Title, days)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -17,6 +18,7 @@ var (
 	cfgFile  string
 	appInst  *app.App
 	logLevel string
+	inline   bool
 )
 
 // rootCmd represents the base command
@@ -26,7 +28,8 @@ var rootCmd = &cobra.Command{
 	Long: `記録 Kiroku - A beautiful terminal-based note-taking application.
 
 Kiroku helps you capture and organize your thoughts, notes, and todos
-with a clean terminal user interface.`,
+with a clean terminal user interface.
+` + exitCodeHelp,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Initialize logging first
 		logCfg := logging.DefaultConfig()
@@ -37,8 +40,13 @@ with a clean terminal user interface.`,
 			return fmt.Errorf("failed to initialize logging: %w", err)
 		}
 
-		// Skip initialization for help and version commands
-		if cmd.Name() == "help" || cmd.Name() == "version" {
+		if err := validateOutput(); err != nil {
+			return err
+		}
+
+		// Skip initialization for commands that must not touch the
+		// database through the app, which would apply pending migrations
+		if skipsAppInit(cmd) {
 			return nil
 		}
 
@@ -80,8 +88,20 @@ with a clean terminal user interface.`,
 			appInst.Config,
 		)
 
-		p := tea.NewProgram(tuiApp, tea.WithAltScreen())
-		if _, err := p.Run(); err != nil {
+		p := tea.NewProgram(tuiApp, programOptions()...)
+		_, err := p.Run()
+		tuiApp.Stop()
+		if crash := tuiApp.Crashed(); crash != nil || errors.Is(err, tea.ErrProgramPanic) {
+			// PersistentPostRun is skipped when RunE fails, so close here
+			appInst.Close()
+			appInst = nil
+			fmt.Fprintf(os.Stderr, "💥 Kiroku crashed — log written to %s\n", logging.LogPath())
+			if crash == nil {
+				crash = err
+			}
+			return fmt.Errorf("TUI crashed: %w", crash)
+		}
+		if err != nil {
 			return fmt.Errorf("failed to run TUI: %w", err)
 		}
 
@@ -89,6 +109,30 @@ with a clean terminal user interface.`,
 	},
 }
 
+// skipsAppInit reports whether cmd runs without the initialized app: help
+// and version need nothing, doctor diagnoses the setup initialization
+// depends on, data only locates files, and the db commands that only
+// inspect the schema run without migrating it.
+func skipsAppInit(cmd *cobra.Command) bool {
+	switch cmd.Name() {
+	case "help", "version", "doctor":
+		return true
+	}
+	if cmd.Parent() == dataCmd {
+		return true
+	}
+	return cmd.Parent() == dbCmd && cmd != dbRepairCmd && cmd != dbStorageCmd
+}
+
+// programOptions builds the BubbleTea options for the TUI. The alternate
+// screen is skipped in inline mode so output stays in the scrollback.
+func programOptions() []tea.ProgramOption {
+	if inline || !appInst.Config.UI.AltScreen {
+		return nil
+	}
+	return []tea.ProgramOption{tea.WithAltScreen()}
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
 	// Recover from panics and log them
@@ -97,23 +141,45 @@ func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		logging.Error().Err(err).Msg("Command execution failed")
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.config/kiroku/config.yaml)")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "log level (debug, info, warn, error)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText, "output format for listings (text, json)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "print only requested data and errors, no confirmations")
+	rootCmd.Flags().BoolVar(&inline, "inline", false, "run the TUI without the alternate screen")
 
 	// Add subcommands
 	rootCmd.AddCommand(addCmd)
 	rootCmd.AddCommand(todoCmd)
+	rootCmd.AddCommand(dueCmd)
+	rootCmd.AddCommand(doneCmd)
+	rootCmd.AddCommand(starCmd)
+	rootCmd.AddCommand(captureCmd)
+	rootCmd.AddCommand(scratchCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(exportCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(reviewCmd)
+	rootCmd.AddCommand(countCmd)
 	rootCmd.AddCommand(searchCmd)
 	rootCmd.AddCommand(editCmd)
+	rootCmd.AddCommand(mergeCmd)
+	rootCmd.AddCommand(encryptCmd)
+	rootCmd.AddCommand(decryptCmd)
+	rootCmd.AddCommand(openCmd)
+	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(linksCmd)
 	rootCmd.AddCommand(templatesCmd)
+	rootCmd.AddCommand(tagCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(dataCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(dbCmd)
 }
 
 var versionCmd = &cobra.Command{
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/service"
+)
+
+var scratchCmd = &cobra.Command{
+	Use:   "scratch [text]",
+	Short: "Append to or edit the scratch note",
+	Long: `Append a line to the scratch note, or open it in your editor when
+no text is given. The scratch note is created on first use and comes
+back empty if it is deleted. In the TUI, S jumps to it.
+
+Examples:
+  kiroku scratch "ffmpeg -ss 00:01:00 -i in.mp4"
+  kiroku scratch
+  kiroku scratch --print`,
+	RunE: runScratch,
+}
+
+var scratchPrint bool
+
+func init() {
+	scratchCmd.Flags().BoolVarP(&scratchPrint, "print", "p", false, "print the scratch note instead of editing it")
+}
+
+func runScratch(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	note, err := appInst.NoteService.Scratch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open scratch note: %w", err)
+	}
+
+	if scratchPrint {
+		fmt.Println(note.Content)
+		return nil
+	}
+
+	if len(args) > 0 {
+		if err := appInst.NoteService.AppendContent(ctx, note.ID, strings.Join(args, " ")); err != nil {
+			return fmt.Errorf("failed to append to scratch note: %w", err)
+		}
+		printInfo("📝 Added to %s\n", note.Title)
+		return nil
+	}
+
+	if note.Locked {
+		return fmt.Errorf("cannot edit %q: %w", note.Title, models.ErrLocked)
+	}
+	_, newContent, err := appInst.EditorService.EditNote(note.Title, note.Content)
+	if err != nil {
+		return fmt.Errorf("editor error: %w", err)
+	}
+	// The title is how the scratch note is found, so an edited heading is ignored
+	if !note.HasChanges(service.ScratchNoteTitle, newContent) {
+		printInfo("No changes to %s\n", note.Title)
+		return nil
+	}
+
+	note.Title = service.ScratchNoteTitle
+	note.Content = newContent
+	// The editor may have been open for a while
+	ctx, cancel = queryContext()
+	defer cancel()
+	if err := appInst.NoteService.Update(ctx, note); err != nil {
+		return fmt.Errorf("failed to update scratch note: %w", err)
+	}
+	printInfo("✨ Updated %s\n", note.Title)
+	return nil
+}
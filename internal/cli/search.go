@@ -1,7 +1,6 @@
 package cli
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -11,10 +10,12 @@ import (
 var searchCmd = &cobra.Command{
 	Use:   "search [query]",
 	Short: "Search notes",
-	Long: `Search notes using full-text search.
+	Long: `Search notes using full-text search. Prefix a term with tag: to match
+it against tags only. Encrypted notes match on their title and tags only.
 
 Examples:
   kiroku search "meeting notes"
+  kiroku search "tag:work meeting"
   kiroku search "golang" --limit 10`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSearch,
@@ -27,7 +28,8 @@ func init() {
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, cancel := queryContext()
+	defer cancel()
 	query := args[0]
 
 	results, err := appInst.SearchService.Search(ctx, query, models.ListOptions{
@@ -38,11 +40,11 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(results) == 0 {
-		fmt.Println("No results found.")
+		printInfo("No results found.\n")
 		return nil
 	}
 
-	fmt.Printf("Found %d results:\n\n", len(results))
+	printInfo("Found %d results:\n\n", len(results))
 	for _, r := range results {
 		fmt.Printf("📝 [%d] %s\n", r.Note.ID, r.Note.Title)
 		if r.Snippet != "" {
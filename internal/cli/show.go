@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a note with its folder, tags and links",
+	Long: `Print a note's content followed by its folder path, tags, the notes it
+links to with [[Title]], and how many notes link back to it.
+
+The content of an encrypted note is only printed with --decrypt, which
+asks for its passphrase, or reads it from stdin when there is no terminal.
+
+Examples:
+  kiroku show 42
+  kiroku show 42 -o json
+  kiroku show 42 --decrypt`,
+	Args: cobra.ExactArgs(1),
+	RunE: runShow,
+}
+
+var showDecrypt bool
+
+func init() {
+	showCmd.Flags().BoolVar(&showDecrypt, "decrypt", false, "print the content of an encrypted note (asks for the passphrase)")
+}
+
+// noteDetails is a note with the metadata show computes for it
+type noteDetails struct {
+	*models.Note
+	FolderPath string   `json:"folder_path"`
+	TagList    []string `json:"tags"`
+	Links      []string `json:"links"`
+	Backlinks  int      `json:"backlinks"`
+
+	// sealed reports the content is still encrypted and not to be printed
+	sealed bool
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid note ID %q", models.ErrValidation, args[0])
+	}
+
+	note, err := appInst.NoteService.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("note not found: %w", err)
+	}
+	sealed := note.Encrypted
+	if showDecrypt && note.Encrypted {
+		passphrase, err := askPassphrase(fmt.Sprintf("Passphrase for %q: ", note.Title))
+		if err != nil {
+			return err
+		}
+		content, err := appInst.NoteService.Reveal(note, passphrase)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt note: %w", err)
+		}
+		note.Content = content
+		sealed = false
+
+		// The prompt may have waited on the user for a while
+		ctx, cancel = queryContext()
+		defer cancel()
+	}
+
+	details := noteDetails{
+		Note:    note,
+		TagList: note.TagList(),
+		Links:   note.Links(),
+		sealed:  sealed,
+	}
+	if note.FolderID != nil {
+		details.FolderPath, err = appInst.FolderService.Path(ctx, *note.FolderID)
+		if err != nil {
+			return fmt.Errorf("failed to resolve folder: %w", err)
+		}
+	}
+	backlinks, err := appInst.NoteService.Backlinks(ctx, note)
+	if err != nil {
+		return fmt.Errorf("failed to find backlinks: %w", err)
+	}
+	details.Backlinks = len(backlinks)
+
+	if jsonOutput() {
+		return printJSON(details)
+	}
+	printNoteDetails(details)
+	return nil
+}
+
+func printNoteDetails(d noteDetails) {
+	fmt.Printf("%s %s (#%d)\n", d.StatusIcon(), d.Title, d.ID)
+
+	var meta []string
+	if d.Priority > models.PriorityNone {
+		meta = append(meta, "Priority: "+d.PriorityString())
+	}
+	if d.DueDate != nil {
+		meta = append(meta, "Due: "+d.DueDate.Format(models.DueDateFormat))
+	}
+	meta = append(meta, "Updated: "+d.UpdatedAt.Local().Format("2006-01-02 15:04"))
+	if d.EditedExternally {
+		meta = append(meta, "Edited outside kiroku")
+	}
+	fmt.Println(strings.Join(meta, " • "))
+
+	if d.sealed {
+		fmt.Printf("\n🔐 Encrypted (kiroku show %d --decrypt to read it)\n", d.ID)
+	} else if content := strings.TrimSpace(d.Content); content != "" {
+		fmt.Printf("\n%s\n", content)
+	}
+
+	fmt.Println("\n" + strings.Repeat("─", 40))
+	fmt.Printf("📁 Folder:    %s\n", orDash(d.FolderPath))
+	fmt.Printf("🏷️  Tags:      %s\n", orDash(strings.Join(d.TagList, ", ")))
+	fmt.Printf("🔗 Links:     %s\n", orDash(strings.Join(d.Links, ", ")))
+	fmt.Printf("↩️  Backlinks: %d\n", d.Backlinks)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return s
+}
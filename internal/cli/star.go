@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+var starCmd = &cobra.Command{
+	Use:   "star [id]",
+	Short: "Star notes",
+	Long: `Star a note, or with --all every note in a set.
+
+With --all the set is the note IDs piped on stdin, or else every note,
+narrowed by --folder and --todos. The change is applied in one
+transaction and asks for confirmation unless --force is given.
+
+Examples:
+  kiroku star 12
+  kiroku star --all --folder projects --todos
+  echo 3 7 12 | kiroku star --all --force
+  echo 3 7 12 | KIROKU_CONFIRM=always kiroku star --all`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runStar,
+}
+
+var (
+	starAll    bool
+	starFolder string
+	starTodos  bool
+	starForce  bool
+)
+
+func init() {
+	starCmd.Flags().BoolVarP(&starAll, "all", "a", false, "star every note in the set")
+	starCmd.Flags().StringVarP(&starFolder, "folder", "f", "", "with --all, only notes in this folder")
+	starCmd.Flags().BoolVarP(&starTodos, "todos", "t", false, "with --all, only todos")
+	starCmd.Flags().BoolVar(&starForce, "force", false, "with --all, skip the confirmation")
+}
+
+func runStar(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	if !starAll {
+		if len(args) == 0 {
+			return fmt.Errorf("%w: give a note ID or --all", models.ErrValidation)
+		}
+		return starOne(ctx, args[0])
+	}
+	if len(args) > 0 {
+		return fmt.Errorf("%w: --all takes no note ID", models.ErrValidation)
+	}
+
+	starred := false
+	opts := models.ListOptions{Starred: &starred}
+	if starTodos {
+		isTodo := true
+		opts.IsTodo = &isTodo
+	}
+	ids, err := bulkTargets(ctx, starFolder, opts)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		printInfo("No unstarred notes found.\n")
+		return nil
+	}
+
+	ok, err := confirm(starForce, fmt.Sprintf("Star %d note(s)?", len(ids)))
+	if err != nil || !ok {
+		return err
+	}
+	// The prompt may have waited on the user for a while
+	ctx, cancel = queryContext()
+	defer cancel()
+
+	changed, err := appInst.NoteService.SetStarred(ctx, ids, true)
+	if err != nil {
+		return fmt.Errorf("failed to star notes: %w", err)
+	}
+	printInfo("⭐ Starred %d note(s)\n", changed)
+	return nil
+}
+
+// starOne stars a single note
+func starOne(ctx context.Context, arg string) error {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid note ID %q", models.ErrValidation, arg)
+	}
+
+	note, err := appInst.NoteService.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get note: %w", err)
+	}
+	if note.Starred {
+		printInfo("⭐ Already starred: %s\n", note.Title)
+		return nil
+	}
+
+	if _, err := appInst.NoteService.SetStarred(ctx, []int64{id}, true); err != nil {
+		return fmt.Errorf("failed to star note: %w", err)
+	}
+	printInfo("⭐ Starred: %s\n", note.Title)
+	return nil
+}
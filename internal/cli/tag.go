@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Manage tags",
+	Long:  `List, rename and delete tags across all notes.`,
+}
+
+var tagRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a tag on every note",
+	Long: `Rename a tag on every note that carries it.
+Tags are matched case-insensitively. Notes that already have the new
+tag keep a single copy of it, and locked notes are left unchanged. Asks
+for confirmation unless --force is given, as cli.confirm sets.
+
+Examples:
+  kiroku tag rename wrok work
+  kiroku tag rename Meetings meeting --force`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTagRename,
+}
+
+var tagListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tags with note counts",
+	Args:  cobra.NoArgs,
+	RunE:  runTagList,
+}
+
+var tagDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Remove a tag from every note",
+	Long: `Remove a tag from every note that carries it.
+Locked notes are left unchanged. Asks for confirmation unless --force
+is given, as cli.confirm sets.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTagDelete,
+}
+
+var tagForce bool
+
+func init() {
+	tagRenameCmd.Flags().BoolVar(&tagForce, "force", false, "skip the confirmation")
+	tagDeleteCmd.Flags().BoolVar(&tagForce, "force", false, "skip the confirmation")
+	tagCmd.AddCommand(tagListCmd)
+	tagCmd.AddCommand(tagRenameCmd)
+	tagCmd.AddCommand(tagDeleteCmd)
+}
+
+func runTagList(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	tags, err := appInst.NoteService.ListTags(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	if jsonOutput() {
+		return printJSON(tags)
+	}
+
+	if len(tags) == 0 {
+		printInfo("No tags found.\n")
+		return nil
+	}
+
+	for _, t := range tags {
+		fmt.Printf("🏷️  %s (%d)\n", t.Name, t.Count)
+	}
+	return nil
+}
+
+func runTagDelete(cmd *cobra.Command, args []string) error {
+	ok, err := confirmTagChange(args[0], func(tag string, count int) string {
+		return fmt.Sprintf("Remove tag %q from %d note(s)?", tag, count)
+	})
+	if err != nil || !ok {
+		return err
+	}
+	// The prompt may have waited on the user for a while
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	affected, err := appInst.NoteService.DeleteTag(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	printInfo("🗑️  Removed tag from %d note(s)\n", affected)
+	return nil
+}
+
+func runTagRename(cmd *cobra.Command, args []string) error {
+	ok, err := confirmTagChange(args[0], func(tag string, count int) string {
+		return fmt.Sprintf("Rename tag %q to %q on %d note(s)?", tag, models.NormalizeTag(args[1]), count)
+	})
+	if err != nil || !ok {
+		return err
+	}
+	// The prompt may have waited on the user for a while
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	affected, err := appInst.NoteService.RenameTag(ctx, args[0], args[1])
+	if err != nil {
+		return fmt.Errorf("failed to rename tag: %w", err)
+	}
+
+	printInfo("🏷️  Renamed tag on %d note(s)\n", affected)
+	return nil
+}
+
+// confirmTagChange asks before rewriting every note carrying tag, with the
+// question prompt builds from the tag and how many notes carry it.
+// Nothing is asked when no note carries it.
+func confirmTagChange(tag string, prompt func(tag string, count int) string) (bool, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+	count, err := tagCount(ctx, tag)
+	if err != nil {
+		return false, err
+	}
+	if count == 0 {
+		return true, nil
+	}
+	return confirm(tagForce, prompt(models.NormalizeTag(tag), count))
+}
+
+// tagCount returns how many notes carry tag
+func tagCount(ctx context.Context, tag string) (int, error) {
+	tags, err := appInst.NoteService.ListTags(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list tags: %w", err)
+	}
+	tag = models.NormalizeTag(tag)
+	for _, t := range tags {
+		if t.Name == tag {
+			return t.Count, nil
+		}
+	}
+	return 0, nil
+}
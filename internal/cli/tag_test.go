@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tranducquang/kiroku/internal/database"
+)
+
+func TestTagConfirm(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantErr  bool
+		wantTags string
+	}{
+		{"delete asks", []string{"tag", "delete", "work"}, true, "work"},
+		{"delete forced", []string{"tag", "delete", "work", "--force"}, false, ""},
+		{"rename asks", []string{"tag", "rename", "work", "job"}, true, "work"},
+		{"rename forced", []string{"tag", "rename", "work", "job", "--force"}, false, "job"},
+		{"missing tag needs no force", []string{"tag", "delete", "absent"}, false, "work"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			// Without a terminal to ask on, the command must be forced
+			t.Setenv(confirmEnv, "never")
+			if _, err := runCLI(t, "add", "tagged"); err != nil {
+				t.Fatalf("add: %v", err)
+			}
+			setTags(t, filepath.Join(home, ".local", "share", "kiroku", "kiroku.db"), "work")
+
+			tagForce = false
+			_, err := runCLI(t, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			out, err := runCLI(t, "tag", "list", "-q")
+			if err != nil {
+				t.Fatalf("tag list: %v", err)
+			}
+			got := ""
+			if fields := strings.Fields(out); len(fields) > 1 {
+				got = fields[1]
+			}
+			if got != tt.wantTags {
+				t.Errorf("tags = %q (%q), want %q", got, out, tt.wantTags)
+			}
+		})
+	}
+}
+
+// setTags tags every note in the database at path
+func setTags(t *testing.T, path, tags string) {
+	t.Helper()
+	db, err := database.New(path)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("UPDATE notes SET tags = ?", tags); err != nil {
+		t.Fatalf("set tags: %v", err)
+	}
+}
@@ -3,8 +3,12 @@ package cli
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/repository"
 )
 
 var templatesCmd = &cobra.Command{
@@ -15,7 +19,8 @@ var templatesCmd = &cobra.Command{
 }
 
 func runTemplates(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, cancel := queryContext()
+	defer cancel()
 
 	templates, err := appInst.TemplateService.List(ctx)
 	if err != nil {
@@ -23,17 +28,20 @@ func runTemplates(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(templates) == 0 {
-		fmt.Println("No templates found.")
+		printInfo("No templates found.\n")
 		return nil
 	}
 
-	fmt.Println("Available templates:")
+	printInfo("Available templates:\n")
 	for _, t := range templates {
-		defaultMark := ""
+		marks := ""
 		if t.IsDefault {
-			defaultMark = " (default)"
+			marks += " (default)"
 		}
-		fmt.Printf("  %s [%d] %s%s\n", t.Icon, t.ID, t.Name, defaultMark)
+		if t.AutoStar {
+			marks += " ⭐"
+		}
+		fmt.Printf("  %s [%d] %s%s\n", t.Icon, t.ID, t.Name, marks)
 		if t.Description != "" {
 			fmt.Printf("      %s\n", t.Description)
 		}
@@ -41,3 +49,77 @@ func runTemplates(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+var templatesSetCmd = &cobra.Command{
+	Use:   "set [name|id]",
+	Short: "Set the defaults a template applies to new notes",
+	Long: `Set the defaults applied to notes created from a template.
+
+Examples:
+  kiroku templates set "Project Note" --auto-star
+  kiroku templates set "Bug Report" --priority high
+  kiroku templates set 3 --auto-star=false --priority none`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTemplatesSet,
+}
+
+var (
+	templateAutoStar bool
+	templatePriority string
+)
+
+func init() {
+	templatesSetCmd.Flags().BoolVar(&templateAutoStar, "auto-star", false, "star notes created from the template")
+	templatesSetCmd.Flags().StringVarP(&templatePriority, "priority", "p", "", "default priority (none, low, medium, high)")
+	templatesCmd.AddCommand(templatesSetCmd)
+}
+
+func runTemplatesSet(cmd *cobra.Command, args []string) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+
+	template, err := findTemplate(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to find template: %w", err)
+	}
+
+	if cmd.Flags().Changed("auto-star") {
+		template.AutoStar = templateAutoStar
+	}
+	if cmd.Flags().Changed("priority") {
+		priority, err := parsePriority(templatePriority)
+		if err != nil {
+			return err
+		}
+		template.DefaultPriority = priority
+	}
+
+	if err := appInst.TemplateService.Update(ctx, template); err != nil {
+		return fmt.Errorf("failed to update template: %w", err)
+	}
+
+	printInfo("✅ Updated template: %s\n", template.Name)
+	return nil
+}
+
+// findTemplate resolves a template by ID or name. Names match ignoring
+// case, with dashes standing in for spaces ("meeting-notes").
+func findTemplate(ctx context.Context, nameOrID string) (*models.Template, error) {
+	if id, err := strconv.ParseInt(nameOrID, 10, 64); err == nil {
+		return appInst.TemplateService.GetByID(ctx, id)
+	}
+
+	templates, err := appInst.TemplateService.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	want := strings.ReplaceAll(nameOrID, "-", " ")
+	names := make([]string, 0, len(templates))
+	for i := range templates {
+		if strings.EqualFold(templates[i].Name, nameOrID) || strings.EqualFold(templates[i].Name, want) {
+			return &templates[i], nil
+		}
+		names = append(names, templates[i].Name)
+	}
+	return nil, fmt.Errorf("template %q: %w (available: %s)", nameOrID, repository.ErrNotFound, strings.Join(names, ", "))
+}
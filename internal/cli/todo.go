@@ -1,8 +1,8 @@
 package cli
 
 import (
-	"context"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/tranducquang/kiroku/internal/models"
@@ -32,29 +32,48 @@ func init() {
 }
 
 func runTodo(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	ctx, cancel := queryContext()
+	defer cancel()
 	title := args[0]
 
-	priority := models.PriorityNone
-	switch todoPriority {
-	case "low", "l":
-		priority = models.PriorityLow
-	case "medium", "m":
-		priority = models.PriorityMedium
-	case "high", "h":
-		priority = models.PriorityHigh
+	priority, err := parsePriority(todoPriority)
+	if err != nil {
+		return err
+	}
+
+	due, err := models.ParseDueDate(todoDue, time.Now())
+	if err != nil {
+		return err
 	}
 
 	note := &models.Note{
 		Title:    title,
 		IsTodo:   true,
 		Priority: priority,
+		DueDate:  due,
 	}
 
 	if err := appInst.NoteService.Create(ctx, note); err != nil {
 		return fmt.Errorf("failed to create todo: %w", err)
 	}
 
-	fmt.Printf("☐ Created todo: %s\n", title)
+	printInfo("☐ Created todo: %s\n", title)
 	return nil
 }
+
+// parsePriority maps a priority flag value to a priority level. An empty
+// value means no priority.
+func parsePriority(value string) (int, error) {
+	switch value {
+	case "", "none", "n":
+		return models.PriorityNone, nil
+	case "low", "l":
+		return models.PriorityLow, nil
+	case "medium", "m":
+		return models.PriorityMedium, nil
+	case "high", "h":
+		return models.PriorityHigh, nil
+	default:
+		return 0, fmt.Errorf("%w: unknown priority %q (use low, medium or high)", models.ErrValidation, value)
+	}
+}
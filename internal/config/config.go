@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -13,32 +14,140 @@ type Config struct {
 	Editor   EditorConfig   `mapstructure:"editor"`
 	UI       UIConfig       `mapstructure:"ui"`
 	Todos    TodoConfig     `mapstructure:"todos"`
+	CLI      CLIConfig      `mapstructure:"cli"`
 }
 
 // DatabaseConfig represents database configuration
 type DatabaseConfig struct {
 	Path string `mapstructure:"path"`
+	// ContentMaxBytes caps the size of a note's content; 0 disables it.
+	ContentMaxBytes int `mapstructure:"content_max_bytes"`
+	// TitleMaxLength caps a note title, in characters; 0 disables it.
+	TitleMaxLength int `mapstructure:"title_max_length"`
+	// ContentStorage is where note content lives: ContentStorageSQLite or
+	// ContentStorageFiles. In files mode SQLite keeps a copy for search.
+	ContentStorage string `mapstructure:"content_storage"`
+	// NotesDir holds the markdown files in files mode.
+	NotesDir string `mapstructure:"notes_dir"`
+	// FTSTokenizer is the search index tokenizer: unicode61 or trigram.
+	// The index is rebuilt on startup when it was built with another one.
+	FTSTokenizer string `mapstructure:"fts_tokenizer"`
+	// QueryTimeout bounds each command's database work, and each TUI
+	// operation's, so a wedged query fails instead of hanging. 0 disables it.
+	QueryTimeout time.Duration `mapstructure:"query_timeout"`
 }
 
 // EditorConfig represents editor configuration
 type EditorConfig struct {
 	Command string   `mapstructure:"command"`
 	Args    []string `mapstructure:"args"`
+	// ConfirmChanges shows a diff to confirm before saving a TUI edit.
+	ConfirmChanges bool `mapstructure:"confirm_changes"`
 }
 
 // UIConfig represents UI configuration
 type UIConfig struct {
 	Theme        string `mapstructure:"theme"`
-	SidebarWidth int    `mapstructure:"sidebar_width"`
+	SidebarWidth int    `mapstructure:"sidebar_width"` // columns; 0 uses a screen percentage
 	DateFormat   string `mapstructure:"date_format"`
+	AltScreen    bool   `mapstructure:"altscreen"`
+	MaxDepth     int    `mapstructure:"max_folder_depth"` // 0 disables the limit
+
+	// PreviewRender renders markdown in the preview; false shows raw text.
+	PreviewRender bool `mapstructure:"preview_render"`
+	// PreviewLargeLines is the line count above which the preview shows a
+	// note as plain text and renders only the visible lines. 0 disables it.
+	PreviewLargeLines int `mapstructure:"preview_large_lines"`
+	// PreviewPosition places the preview below the note list
+	// (PreviewBottom) or to its right (PreviewRight).
+	PreviewPosition string `mapstructure:"preview_position"`
+	// ListSnippet shows a line of content under each note in the list.
+	ListSnippet bool `mapstructure:"list_snippet"`
+	// RestoreSession reopens the last filter or folder and note on launch.
+	RestoreSession bool `mapstructure:"restore_session"`
+	// OpenLastNote starts on the most recently updated note under All
+	// notes instead of the restored view; ResumeEditing also opens it in
+	// the editor.
+	OpenLastNote  bool `mapstructure:"open_last_note"`
+	ResumeEditing bool `mapstructure:"resume_editing"`
+	// HideDoneInFolders leaves completed todos out of folder note lists.
+	HideDoneInFolders bool `mapstructure:"hide_done_in_folders"`
+	// DefaultFolder names the folder that receives notes created outside a
+	// folder view. It is created on first use; empty leaves them unfiled.
+	DefaultFolder string `mapstructure:"default_folder"`
+	// InheritFilterOnCreate files notes created in the TUI into the open
+	// folder and tags them with the active tag filter. When false they go
+	// to DefaultFolder untagged.
+	InheritFilterOnCreate bool `mapstructure:"inherit_filter_on_create"`
+	// ASCIIIcons replaces emoji indicators with plain ASCII for terminals
+	// that draw emoji as boxes or at the wrong width.
+	ASCIIIcons bool `mapstructure:"ascii_icons"`
+	// FocusIndicator marks the focused panel: "color" colours its border,
+	// "title" marks its title without relying on colour, "both" does both.
+	FocusIndicator string `mapstructure:"focus_indicator"`
+	// AutosaveDelay is how many milliseconds label, priority and due date
+	// changes wait before they are saved, so quick repeated edits make one
+	// write. 0 saves each change immediately.
+	AutosaveDelay int `mapstructure:"autosave_delay"`
+	// RecentLimit is how many recently updated notes the Recent view lists.
+	RecentLimit int `mapstructure:"recent_limit"`
+	// ReviewAge is how long a note goes without changes before the Review
+	// view and kiroku review list it, e.g. "60d" or "3mo".
+	ReviewAge string `mapstructure:"review_age"`
+
+	// ConfirmDeleteThreshold is the number of affected notes above which
+	// deleting a folder requires typing its name.
+	ConfirmDeleteThreshold int  `mapstructure:"confirm_delete_threshold"`
+	ConfirmNoteDelete      bool `mapstructure:"confirm_note_delete"`
 }
 
 // TodoConfig represents todo configuration
 type TodoConfig struct {
 	ShowCompleted bool `mapstructure:"show_completed"`
 	SortByDue     bool `mapstructure:"sort_by_due"`
+	// ManualOrder sorts the Todos view by the order set with Shift+Up/Down
+	// instead of by priority
+	ManualOrder bool `mapstructure:"manual_order"`
+}
+
+// CLIConfig represents command line configuration
+type CLIConfig struct {
+	// Confirm controls when sweeping commands ask before acting: ConfirmTTY,
+	// ConfirmAlways or ConfirmNever. KIROKU_CONFIRM overrides it.
+	Confirm string `mapstructure:"confirm"`
 }
 
+// DefaultContentMaxBytes is the default note content size limit (4 MiB)
+const DefaultContentMaxBytes = 4 << 20
+
+// DefaultTitleMaxLength is the default note title length limit
+const DefaultTitleMaxLength = 200
+
+// DefaultQueryTimeout is the default deadline for database work
+const DefaultQueryTimeout = 30 * time.Second
+
+// Preview positions, relative to the note list
+const (
+	PreviewBottom = "bottom"
+	PreviewRight  = "right"
+)
+
+// Confirmation modes for CLI commands
+const (
+	// ConfirmTTY asks when stdin is a terminal and needs --force otherwise
+	ConfirmTTY = "tty"
+	// ConfirmAlways asks on the terminal even when stdin is piped
+	ConfirmAlways = "always"
+	// ConfirmNever never asks, so --force is always needed
+	ConfirmNever = "never"
+)
+
+// Content storage modes
+const (
+	ContentStorageSQLite = "sqlite"
+	ContentStorageFiles  = "files"
+)
+
 // Default paths
 func getDefaultPaths() (configDir, dataDir string) {
 	homeDir, _ := os.UserHomeDir()
@@ -61,13 +170,41 @@ func Load() (*Config, error) {
 
 	// Set defaults
 	viper.SetDefault("database.path", filepath.Join(dataDir, "kiroku.db"))
+	viper.SetDefault("database.content_max_bytes", DefaultContentMaxBytes)
+	viper.SetDefault("database.title_max_length", DefaultTitleMaxLength)
+	viper.SetDefault("database.content_storage", ContentStorageSQLite)
+	viper.SetDefault("database.notes_dir", filepath.Join(dataDir, "notes"))
+	viper.SetDefault("database.fts_tokenizer", "unicode61")
+	viper.SetDefault("database.query_timeout", DefaultQueryTimeout.String())
 	viper.SetDefault("editor.command", getDefaultEditor())
 	viper.SetDefault("editor.args", []string{})
+	viper.SetDefault("editor.confirm_changes", false)
 	viper.SetDefault("ui.theme", "default")
-	viper.SetDefault("ui.sidebar_width", 25)
+	viper.SetDefault("ui.sidebar_width", 0)
 	viper.SetDefault("ui.date_format", "2006-01-02 15:04")
+	viper.SetDefault("ui.altscreen", true)
+	viper.SetDefault("ui.max_folder_depth", 8)
+	viper.SetDefault("ui.preview_render", true)
+	viper.SetDefault("ui.preview_large_lines", 2000)
+	viper.SetDefault("ui.preview_position", PreviewBottom)
+	viper.SetDefault("ui.list_snippet", false)
+	viper.SetDefault("ui.restore_session", true)
+	viper.SetDefault("ui.open_last_note", false)
+	viper.SetDefault("ui.resume_editing", false)
+	viper.SetDefault("ui.hide_done_in_folders", false)
+	viper.SetDefault("ui.default_folder", "")
+	viper.SetDefault("ui.inherit_filter_on_create", true)
+	viper.SetDefault("ui.ascii_icons", false)
+	viper.SetDefault("ui.focus_indicator", "color")
+	viper.SetDefault("ui.autosave_delay", 800)
+	viper.SetDefault("ui.recent_limit", 20)
+	viper.SetDefault("ui.review_age", "60d")
+	viper.SetDefault("ui.confirm_delete_threshold", 10)
+	viper.SetDefault("ui.confirm_note_delete", true)
 	viper.SetDefault("todos.show_completed", true)
 	viper.SetDefault("todos.sort_by_due", true)
+	viper.SetDefault("todos.manual_order", false)
+	viper.SetDefault("cli.confirm", ConfirmTTY)
 
 	// Set config file
 	viper.SetConfigName("config")
@@ -135,13 +272,41 @@ func (c *Config) Save() error {
 	configPath := filepath.Join(configDir, "config.yaml")
 
 	viper.Set("database.path", c.Database.Path)
+	viper.Set("database.content_max_bytes", c.Database.ContentMaxBytes)
+	viper.Set("database.title_max_length", c.Database.TitleMaxLength)
+	viper.Set("database.content_storage", c.Database.ContentStorage)
+	viper.Set("database.notes_dir", c.Database.NotesDir)
+	viper.Set("database.fts_tokenizer", c.Database.FTSTokenizer)
+	viper.Set("database.query_timeout", c.Database.QueryTimeout.String())
 	viper.Set("editor.command", c.Editor.Command)
 	viper.Set("editor.args", c.Editor.Args)
+	viper.Set("editor.confirm_changes", c.Editor.ConfirmChanges)
 	viper.Set("ui.theme", c.UI.Theme)
 	viper.Set("ui.sidebar_width", c.UI.SidebarWidth)
 	viper.Set("ui.date_format", c.UI.DateFormat)
+	viper.Set("ui.altscreen", c.UI.AltScreen)
+	viper.Set("ui.max_folder_depth", c.UI.MaxDepth)
+	viper.Set("ui.preview_render", c.UI.PreviewRender)
+	viper.Set("ui.preview_large_lines", c.UI.PreviewLargeLines)
+	viper.Set("ui.preview_position", c.UI.PreviewPosition)
+	viper.Set("ui.list_snippet", c.UI.ListSnippet)
+	viper.Set("ui.restore_session", c.UI.RestoreSession)
+	viper.Set("ui.open_last_note", c.UI.OpenLastNote)
+	viper.Set("ui.resume_editing", c.UI.ResumeEditing)
+	viper.Set("ui.hide_done_in_folders", c.UI.HideDoneInFolders)
+	viper.Set("ui.default_folder", c.UI.DefaultFolder)
+	viper.Set("ui.inherit_filter_on_create", c.UI.InheritFilterOnCreate)
+	viper.Set("ui.ascii_icons", c.UI.ASCIIIcons)
+	viper.Set("ui.focus_indicator", c.UI.FocusIndicator)
+	viper.Set("ui.autosave_delay", c.UI.AutosaveDelay)
+	viper.Set("ui.recent_limit", c.UI.RecentLimit)
+	viper.Set("ui.review_age", c.UI.ReviewAge)
+	viper.Set("ui.confirm_delete_threshold", c.UI.ConfirmDeleteThreshold)
+	viper.Set("ui.confirm_note_delete", c.UI.ConfirmNoteDelete)
 	viper.Set("todos.show_completed", c.Todos.ShowCompleted)
 	viper.Set("todos.sort_by_due", c.Todos.SortByDue)
+	viper.Set("todos.manual_order", c.Todos.ManualOrder)
+	viper.Set("cli.confirm", c.CLI.Confirm)
 
 	return viper.WriteConfigAs(configPath)
 }
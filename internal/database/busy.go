@@ -0,0 +1,44 @@
+package database
+
+import (
+	"errors"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// ErrBusy is returned by writes that could not take the database lock
+// within the busy timeout, usually because another process is writing
+var ErrBusy = errors.New("database is locked: another kiroku instance may be running")
+
+// busyError reads as ErrBusy while keeping the SQLite error in the chain,
+// so IsDatabaseError still recognises it
+type busyError struct {
+	err error
+}
+
+func (e *busyError) Error() string   { return ErrBusy.Error() }
+func (e *busyError) Unwrap() []error { return []error{ErrBusy, e.err} }
+
+// IsBusy reports whether err is SQLite's busy or locked error, including
+// their extended codes
+func IsBusy(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	switch sqliteErr.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return true
+	}
+	return false
+}
+
+// MarkBusy replaces *err with ErrBusy when it is a busy error. Writes defer
+// it so a lock held elsewhere is reported plainly rather than as
+// "database is locked (5) (SQLITE_BUSY)".
+func MarkBusy(err *error) {
+	if *err != nil && !errors.Is(*err, ErrBusy) && IsBusy(*err) {
+		*err = &busyError{err: *err}
+	}
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// holdWriteLock opens a second connection to path, the way another kiroku
+// process would, and keeps a write transaction open until the test ends
+func holdWriteLock(t *testing.T, path string) {
+	t.Helper()
+	other, err := sql.Open("sqlite", path+"?_txlock=immediate")
+	if err != nil {
+		t.Fatalf("open second connection: %v", err)
+	}
+	tx, err := other.Begin()
+	if err != nil {
+		t.Fatalf("begin write transaction: %v", err)
+	}
+	t.Cleanup(func() {
+		tx.Rollback()
+		other.Close()
+	})
+}
+
+func TestMarkBusy_LockedDatabase(t *testing.T) {
+	db, path := newTestDB(t)
+	// Fail fast instead of waiting out the configured timeout; the pool
+	// holds a single connection, so the pragma applies to the write below
+	if _, err := db.Exec(`PRAGMA busy_timeout = 50`); err != nil {
+		t.Fatalf("set busy timeout: %v", err)
+	}
+	holdWriteLock(t, path)
+
+	write := func() (err error) {
+		defer MarkBusy(&err)
+		_, err = db.Exec(`INSERT INTO folders (name) VALUES ('blocked')`)
+		return err
+	}
+	err := write()
+	if !errors.Is(err, ErrBusy) {
+		t.Fatalf("write error = %v, want ErrBusy", err)
+	}
+	if err.Error() != ErrBusy.Error() {
+		t.Errorf("message = %q, want %q", err, ErrBusy)
+	}
+	if !IsBusy(err) || !IsDatabaseError(err) {
+		t.Error("the SQLite error is no longer in the chain")
+	}
+}
+
+func TestMarkBusy_LeavesOtherErrors(t *testing.T) {
+	busy := error(&busyError{err: errors.New("database is locked")})
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"nil", nil},
+		{"other error", errors.New("disk on fire")},
+		{"already busy", busy},
+		{"wrapped busy", fmt.Errorf("create note: %w", busy)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.err
+			MarkBusy(&err)
+			if err != tt.err {
+				t.Errorf("MarkBusy() = %v, want %v unchanged", err, tt.err)
+			}
+		})
+	}
+}
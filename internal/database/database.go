@@ -3,19 +3,25 @@ package database
 import (
 	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
-	_ "modernc.org/sqlite"
+	"modernc.org/sqlite"
 )
 
 //go:embed migrations/*.sql
 var migrationsFS embed.FS
 
+// busyTimeout is how long a statement waits, retrying with backoff, for a
+// lock held by another connection before failing with ErrBusy
+const busyTimeout = 5 * time.Second
+
 // DB wraps a SQL database connection
 type DB struct {
 	*sql.DB
@@ -29,8 +35,12 @@ func New(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	// Open database with WAL mode
-	dsn := fmt.Sprintf("%s?_journal_mode=WAL&_busy_timeout=5000&_foreign_keys=on", dbPath)
+	// Open database with WAL mode. The driver only applies settings passed
+	// as a _pragma, so journal mode, foreign keys and the busy timeout all
+	// go that way. Transactions take the write lock when they begin, so
+	// they wait for it rather than fail partway through.
+	dsn := fmt.Sprintf("%s?_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_pragma=busy_timeout(%d)&_txlock=immediate",
+		dbPath, busyTimeout.Milliseconds())
 	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
@@ -61,41 +71,20 @@ func (db *DB) Migrate() error {
 		return fmt.Errorf("failed to create migrations table: %w", err)
 	}
 
-	// Get applied migrations
-	applied := make(map[string]bool)
-	rows, err := db.Query("SELECT version FROM schema_migrations")
+	applied, err := db.appliedVersions()
 	if err != nil {
-		return fmt.Errorf("failed to query migrations: %w", err)
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var version string
-		if err := rows.Scan(&version); err != nil {
-			return fmt.Errorf("failed to scan migration version: %w", err)
-		}
-		applied[version] = true
+		return err
 	}
 
-	// Get migration files
-	entries, err := fs.ReadDir(migrationsFS, "migrations")
+	migrations, err := migrationFiles()
 	if err != nil {
-		return fmt.Errorf("failed to read migrations directory: %w", err)
+		return err
 	}
 
-	// Sort migrations by name
-	var migrations []string
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
-			migrations = append(migrations, entry.Name())
-		}
-	}
-	sort.Strings(migrations)
-
 	// Apply pending migrations
 	for _, migration := range migrations {
 		version := strings.TrimSuffix(migration, ".sql")
-		if applied[version] {
+		if _, ok := applied[version]; ok {
 			continue
 		}
 
@@ -128,7 +117,150 @@ func (db *DB) Migrate() error {
 	return nil
 }
 
+// Migration describes one schema migration and whether it has been applied
+type Migration struct {
+	Version   string     `json:"version"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	// Embedded is false for versions recorded in the database that this
+	// build does not ship, e.g. after downgrading kiroku.
+	Embedded bool `json:"embedded"`
+}
+
+// Applied reports whether the migration has been applied
+func (m Migration) Applied() bool {
+	return m.AppliedAt != nil
+}
+
+// MigrationStatus lists the embedded migrations in apply order with their
+// applied time, followed by any applied versions this build does not know.
+// It never applies anything.
+func (db *DB) MigrationStatus() ([]Migration, error) {
+	exists, err := db.HasTable("schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	applied := map[string]time.Time{}
+	if exists {
+		if applied, err = db.appliedVersions(); err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := migrationFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var status []Migration
+	for _, file := range files {
+		m := Migration{Version: strings.TrimSuffix(file, ".sql"), Embedded: true}
+		if at, ok := applied[m.Version]; ok {
+			m.AppliedAt = &at
+			delete(applied, m.Version)
+		}
+		status = append(status, m)
+	}
+
+	unknown := make([]string, 0, len(applied))
+	for version := range applied {
+		unknown = append(unknown, version)
+	}
+	sort.Strings(unknown)
+	for _, version := range unknown {
+		at := applied[version]
+		status = append(status, Migration{Version: version, AppliedAt: &at})
+	}
+	return status, nil
+}
+
+// SchemaVersion returns the latest applied migration version and the
+// embedded migrations that have not been applied yet. The version is empty
+// when nothing has been applied.
+func (db *DB) SchemaVersion() (version string, pending []string, err error) {
+	status, err := db.MigrationStatus()
+	if err != nil {
+		return "", nil, err
+	}
+	for _, m := range status {
+		switch {
+		case !m.Embedded:
+		case m.Applied():
+			version = m.Version
+		default:
+			pending = append(pending, m.Version)
+		}
+	}
+	return version, pending, nil
+}
+
+// appliedVersions returns the versions recorded in schema_migrations with
+// the time each was applied
+func (db *DB) appliedVersions() (map[string]time.Time, error) {
+	applied := make(map[string]time.Time)
+	rows, err := db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query migrations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version string
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan migration version: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// migrationFiles returns the embedded migration file names in apply order
+func migrationFiles() ([]string, error) {
+	entries, err := fs.ReadDir(migrationsFS, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	var migrations []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
+			migrations = append(migrations, entry.Name())
+		}
+	}
+	sort.Strings(migrations)
+	return migrations, nil
+}
+
+// HasTable reports whether a table (including virtual tables) exists
+func (db *DB) HasTable(name string) (bool, error) {
+	var n int
+	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up table %s: %w", name, err)
+	}
+	return n > 0, nil
+}
+
+// Checkpoint copies the WAL back into the database file and truncates
+// it, so it does not linger, or keep growing, between sessions. Pages other
+// connections are still reading stay in the WAL until their next checkpoint.
+func (db *DB) Checkpoint() error {
+	if _, err := db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("failed to checkpoint WAL: %w", err)
+	}
+	return nil
+}
+
 // Close closes the database connection
 func (db *DB) Close() error {
 	return db.DB.Close()
 }
+
+// IsDatabaseError reports whether err originates from SQLite or the sql package.
+func IsDatabaseError(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) ||
+		errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, sql.ErrTxDone)
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestDB opens a migrated database in a temporary directory
+func newTestDB(t *testing.T) (*DB, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "kiroku.db")
+	db, err := New(path)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	return db, path
+}
+
+// walSize returns the size of the database's WAL file, 0 when it is gone
+func walSize(t *testing.T, path string) int64 {
+	t.Helper()
+	info, err := os.Stat(path + "-wal")
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0
+	}
+	if err != nil {
+		t.Fatalf("stat WAL: %v", err)
+	}
+	return info.Size()
+}
+
+func TestNew_Pragmas(t *testing.T) {
+	db, _ := newTestDB(t)
+
+	tests := []struct {
+		pragma string
+		want   string
+	}{
+		{pragma: "journal_mode", want: "wal"},
+		{pragma: "foreign_keys", want: "1"},
+		{pragma: "busy_timeout", want: fmt.Sprint(busyTimeout.Milliseconds())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pragma, func(t *testing.T) {
+			var got string
+			if err := db.QueryRow("PRAGMA " + tt.pragma).Scan(&got); err != nil {
+				t.Fatalf("PRAGMA %s: %v", tt.pragma, err)
+			}
+			if got != tt.want {
+				t.Errorf("PRAGMA %s = %q, want %q", tt.pragma, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckpoint_TruncatesWAL(t *testing.T) {
+	db, path := newTestDB(t)
+
+	for i := 0; i < 50; i++ {
+		if _, err := db.Exec("INSERT INTO notes (title, content) VALUES (?, ?)", fmt.Sprintf("note %d", i), "body"); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+	if walSize(t, path) == 0 {
+		t.Fatal("WAL is empty after writes, want it to hold them")
+	}
+
+	if err := db.Checkpoint(); err != nil {
+		t.Fatalf("Checkpoint() error = %v", err)
+	}
+	if size := walSize(t, path); size != 0 {
+		t.Errorf("WAL size after Checkpoint() = %d, want 0", size)
+	}
+}
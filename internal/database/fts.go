@@ -0,0 +1,169 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Tokenizers notes_fts can be built with
+const (
+	// TokenizerUnicode61 splits on whitespace and punctuation. It is the
+	// SQLite default and keeps the index small, but treats a run of CJK
+	// text without spaces as a single word.
+	TokenizerUnicode61 = "unicode61"
+	// TokenizerTrigram indexes every three-character sequence, so any
+	// substring of three or more characters matches, including CJK text.
+	// The index is several times larger and shorter queries match nothing.
+	TokenizerTrigram = "trigram"
+)
+
+// Tokenizers lists the supported tokenizers
+var Tokenizers = []string{TokenizerUnicode61, TokenizerTrigram}
+
+// IsTokenizer reports whether name is a supported tokenizer
+func IsTokenizer(name string) bool {
+	for _, t := range Tokenizers {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ftsTriggers keep notes_fts in sync with every insert, update and
+// delete on notes. Reindex recreates any that are missing. Encrypted notes
+// are indexed by title and tags only.
+var ftsTriggers = map[string]string{
+	"notes_ai": `CREATE TRIGGER notes_ai AFTER INSERT ON notes BEGIN
+		INSERT INTO notes_fts(rowid, title, content, tags)
+		VALUES (new.id, new.title, CASE WHEN new.is_encrypted THEN '' ELSE new.content END, new.tags);
+	END`,
+	"notes_ad": `CREATE TRIGGER notes_ad AFTER DELETE ON notes BEGIN
+		INSERT INTO notes_fts(notes_fts, rowid, title, content, tags)
+		VALUES ('delete', old.id, old.title, CASE WHEN old.is_encrypted THEN '' ELSE old.content END, old.tags);
+	END`,
+	"notes_au": `CREATE TRIGGER notes_au AFTER UPDATE OF title, content, tags, is_encrypted ON notes BEGIN
+		INSERT INTO notes_fts(notes_fts, rowid, title, content, tags)
+		VALUES ('delete', old.id, old.title, CASE WHEN old.is_encrypted THEN '' ELSE old.content END, old.tags);
+		INSERT INTO notes_fts(rowid, title, content, tags)
+		VALUES (new.id, new.title, CASE WHEN new.is_encrypted THEN '' ELSE new.content END, new.tags);
+	END`,
+}
+
+// ftsUnindexEncrypted replaces the entries a rebuild made for encrypted
+// notes, which read their sealed content, with title-only ones
+var ftsUnindexEncrypted = []string{
+	`INSERT INTO notes_fts(notes_fts, rowid, title, content, tags)
+		SELECT 'delete', id, title, content, tags FROM notes WHERE is_encrypted = 1`,
+	`INSERT INTO notes_fts(rowid, title, content, tags)
+		SELECT id, title, '', tags FROM notes WHERE is_encrypted = 1`,
+}
+
+// MissingFTSTriggers returns the names of the search index sync triggers
+// that do not exist, sorted. Notes written without them are missing from
+// search results until the index is rebuilt.
+func (db *DB) MissingFTSTriggers() ([]string, error) {
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'trigger' AND tbl_name = 'notes'")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list triggers: %w", err)
+	}
+	defer rows.Close()
+
+	found := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan trigger: %w", err)
+		}
+		found[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list triggers: %w", err)
+	}
+
+	var missing []string
+	for name := range ftsTriggers {
+		if !found[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
+// tokenizeOption matches the tokenize option in a CREATE VIRTUAL TABLE
+var tokenizeOption = regexp.MustCompile(`(?i)tokenize\s*=\s*['"]([^'"]*)['"]`)
+
+// FTSTokenizer returns the tokenizer notes_fts was created with
+func (db *DB) FTSTokenizer() (string, error) {
+	var ddl string
+	err := db.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'notes_fts'").Scan(&ddl)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("search index not found; run kiroku once to create it")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read search index definition: %w", err)
+	}
+
+	m := tokenizeOption.FindStringSubmatch(ddl)
+	if m == nil {
+		return TokenizerUnicode61, nil
+	}
+	// The option may carry arguments, e.g. "unicode61 remove_diacritics 2"
+	fields := strings.Fields(m[1])
+	if len(fields) == 0 {
+		return TokenizerUnicode61, nil
+	}
+	return fields[0], nil
+}
+
+// Reindex recreates notes_fts with the given tokenizer and fills it from
+// the notes table. The sync triggers refer to the table by name, so they
+// keep working once it is recreated; any that are missing are restored.
+func (db *DB) Reindex(tokenizer string) error {
+	if !IsTokenizer(tokenizer) {
+		return fmt.Errorf("unknown tokenizer %q (want %s)", tokenizer, strings.Join(Tokenizers, " or "))
+	}
+
+	missing, err := db.MissingFTSTriggers()
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		"DROP TABLE IF EXISTS notes_fts",
+		fmt.Sprintf(`CREATE VIRTUAL TABLE notes_fts USING fts5(
+			title,
+			content,
+			tags,
+			content='notes',
+			content_rowid='id',
+			tokenize='%s'
+		)`, tokenizer),
+		"INSERT INTO notes_fts(notes_fts) VALUES ('rebuild')",
+	}
+	statements = append(statements, ftsUnindexEncrypted...)
+	for _, name := range missing {
+		statements = append(statements, ftsTriggers[name])
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to rebuild search index: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit search index: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package database
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ftsMatches returns the rowids the search index holds for query
+func ftsMatches(t *testing.T, db *DB, query string) []int64 {
+	t.Helper()
+	rows, err := db.Query("SELECT rowid FROM notes_fts WHERE notes_fts MATCH ? ORDER BY rowid", query)
+	if err != nil {
+		t.Fatalf("search %q: %v", query, err)
+	}
+	defer rows.Close()
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("search %q: %v", query, err)
+	}
+	return ids
+}
+
+func TestMigrate_AppliesEverythingOnce(t *testing.T) {
+	db, _ := newTestDB(t)
+	// A second run finds nothing left to do
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("second Migrate() error = %v", err)
+	}
+
+	files, err := migrationFiles()
+	if err != nil {
+		t.Fatalf("migrationFiles() error = %v", err)
+	}
+	version, pending, err := db.SchemaVersion()
+	if err != nil {
+		t.Fatalf("SchemaVersion() error = %v", err)
+	}
+	if want := strings.TrimSuffix(files[len(files)-1], ".sql"); version != want || len(pending) != 0 {
+		t.Errorf("SchemaVersion() = %q, pending %v; want %q, none pending", version, pending, want)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&count); err != nil {
+		t.Fatalf("count migrations: %v", err)
+	}
+	if count != len(files) {
+		t.Errorf("recorded %d migrations, want %d", count, len(files))
+	}
+
+	missing, err := db.MissingFTSTriggers()
+	if err != nil {
+		t.Fatalf("MissingFTSTriggers() error = %v", err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("missing search triggers %v after migrating", missing)
+	}
+}
+
+func TestMigrate_FTSTriggersFollowWrites(t *testing.T) {
+	db, _ := newTestDB(t)
+
+	steps := []struct {
+		name string
+		sql  string
+		want map[string][]int64
+	}{
+		{"insert", "INSERT INTO notes (id, title, content, tags) VALUES (1, 'draft', 'alpha', 'first')",
+			map[string][]int64{"draft": {1}, "alpha": {1}, "tags:first": {1}}},
+		{"update", "UPDATE notes SET title = 'final', content = 'beta', tags = 'second' WHERE id = 1",
+			map[string][]int64{"draft": {}, "alpha": {}, "tags:first": {}, "final": {1}, "beta": {1}, "tags:second": {1}}},
+		{"encrypt", "UPDATE notes SET is_encrypted = 1 WHERE id = 1",
+			map[string][]int64{"beta": {}, "final": {1}, "tags:second": {1}}},
+		{"delete", "DELETE FROM notes WHERE id = 1",
+			map[string][]int64{"final": {}, "tags:second": {}}},
+	}
+	for _, step := range steps {
+		if _, err := db.Exec(step.sql); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		for query, want := range step.want {
+			if got := ftsMatches(t, db, query); !reflect.DeepEqual(got, want) {
+				t.Errorf("after %s: %q matches %v, want %v", step.name, query, got, want)
+			}
+		}
+	}
+}
+
+func TestMigrate_RestoresLostTriggers(t *testing.T) {
+	db, _ := newTestDB(t)
+
+	// A database that lost its triggers, as 008 repairs
+	for _, name := range []string{"notes_ai", "notes_ad", "notes_au"} {
+		if _, err := db.Exec("DROP TRIGGER " + name); err != nil {
+			t.Fatalf("drop %s: %v", name, err)
+		}
+	}
+	// Both migrations that define the triggers run again; neither alters tables
+	if _, err := db.Exec("DELETE FROM schema_migrations WHERE version IN ('008_fts_sync_triggers', '011_fts_skip_encrypted')"); err != nil {
+		t.Fatalf("forget migrations: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO notes (id, title, content) VALUES (1, 'unindexed', 'written without triggers')"); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if got := ftsMatches(t, db, "unindexed"); len(got) != 0 {
+		t.Fatalf("note was indexed without triggers: %v", got)
+	}
+
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	missing, err := db.MissingFTSTriggers()
+	if err != nil {
+		t.Fatalf("MissingFTSTriggers() error = %v", err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("triggers %v still missing", missing)
+	}
+	if got := ftsMatches(t, db, "unindexed"); !reflect.DeepEqual(got, []int64{1}) {
+		t.Errorf("note written without triggers matches %v after migrating, want [1]", got)
+	}
+}
@@ -0,0 +1 @@
+ALTER TABLE notes ADD COLUMN is_locked BOOLEAN DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE templates ADD COLUMN auto_star BOOLEAN DEFAULT 0;
+ALTER TABLE templates ADD COLUMN default_priority INTEGER DEFAULT 0;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_notes_title ON notes(title);
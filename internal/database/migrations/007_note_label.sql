@@ -0,0 +1,2 @@
+-- Optional color label for grouping notes visually, e.g. 'red'; '' when unlabeled
+ALTER TABLE notes ADD COLUMN label TEXT NOT NULL DEFAULT '';
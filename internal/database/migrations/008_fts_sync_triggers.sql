@@ -0,0 +1,26 @@
+-- Recreate the search index sync triggers, in case a database lost them,
+-- and only reindex a note when a searched column changes rather than on
+-- every flag toggle
+DROP TRIGGER IF EXISTS notes_ai;
+DROP TRIGGER IF EXISTS notes_ad;
+DROP TRIGGER IF EXISTS notes_au;
+
+CREATE TRIGGER notes_ai AFTER INSERT ON notes BEGIN
+    INSERT INTO notes_fts(rowid, title, content, tags)
+    VALUES (new.id, new.title, new.content, new.tags);
+END;
+
+CREATE TRIGGER notes_ad AFTER DELETE ON notes BEGIN
+    INSERT INTO notes_fts(notes_fts, rowid, title, content, tags)
+    VALUES ('delete', old.id, old.title, old.content, old.tags);
+END;
+
+CREATE TRIGGER notes_au AFTER UPDATE OF title, content, tags ON notes BEGIN
+    INSERT INTO notes_fts(notes_fts, rowid, title, content, tags)
+    VALUES ('delete', old.id, old.title, old.content, old.tags);
+    INSERT INTO notes_fts(rowid, title, content, tags)
+    VALUES (new.id, new.title, new.content, new.tags);
+END;
+
+-- Notes written while a trigger was missing are stale in the index
+INSERT INTO notes_fts(notes_fts) VALUES ('rebuild');
@@ -0,0 +1,3 @@
+-- Manual order of todos, lowest first; starts out as creation order
+ALTER TABLE notes ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+UPDATE notes SET position = id;
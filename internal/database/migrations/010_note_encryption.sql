@@ -0,0 +1,2 @@
+-- Content of encrypted notes is sealed with a passphrase (see internal/secret)
+ALTER TABLE notes ADD COLUMN is_encrypted INTEGER NOT NULL DEFAULT 0;
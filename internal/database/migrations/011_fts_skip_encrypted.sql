@@ -0,0 +1,28 @@
+-- Index encrypted notes by title and tags only, so their sealed content
+-- never reaches the search index
+DROP TRIGGER IF EXISTS notes_ai;
+DROP TRIGGER IF EXISTS notes_ad;
+DROP TRIGGER IF EXISTS notes_au;
+
+CREATE TRIGGER notes_ai AFTER INSERT ON notes BEGIN
+    INSERT INTO notes_fts(rowid, title, content, tags)
+    VALUES (new.id, new.title, CASE WHEN new.is_encrypted THEN '' ELSE new.content END, new.tags);
+END;
+
+CREATE TRIGGER notes_ad AFTER DELETE ON notes BEGIN
+    INSERT INTO notes_fts(notes_fts, rowid, title, content, tags)
+    VALUES ('delete', old.id, old.title, CASE WHEN old.is_encrypted THEN '' ELSE old.content END, old.tags);
+END;
+
+CREATE TRIGGER notes_au AFTER UPDATE OF title, content, tags, is_encrypted ON notes BEGIN
+    INSERT INTO notes_fts(notes_fts, rowid, title, content, tags)
+    VALUES ('delete', old.id, old.title, CASE WHEN old.is_encrypted THEN '' ELSE old.content END, old.tags);
+    INSERT INTO notes_fts(rowid, title, content, tags)
+    VALUES (new.id, new.title, CASE WHEN new.is_encrypted THEN '' ELSE new.content END, new.tags);
+END;
+
+-- Notes encrypted before this were indexed with their sealed content
+INSERT INTO notes_fts(notes_fts, rowid, title, content, tags)
+    SELECT 'delete', id, title, content, tags FROM notes WHERE is_encrypted = 1;
+INSERT INTO notes_fts(rowid, title, content, tags)
+    SELECT id, title, '', tags FROM notes WHERE is_encrypted = 1;
@@ -121,6 +121,15 @@ func RecoverPanic() {
 	}
 }
 
+// LogPath returns the file this run logs to, or the log directory when
+// file logging is off
+func LogPath() string {
+	if logFile != nil {
+		return logFile.Name()
+	}
+	return GetLogDir()
+}
+
 // GetLogDir returns the log directory path
 func GetLogDir() string {
 	cfg := DefaultConfig()
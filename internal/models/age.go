@@ -0,0 +1,38 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidAge is returned when an age such as "7d" cannot be parsed
+var ErrInvalidAge = fmt.Errorf("%w: unrecognized age", ErrValidation)
+
+// ParseAge returns the time an age before now. An age is a count and a
+// unit: h (hours), d (days), w (weeks), mo or m (months) or y (years),
+// e.g. "36h", "7d", "2w" or "1mo". Longer unit names such as "days" work
+// too.
+func ParseAge(input string, now time.Time) (time.Time, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	digits := len(input) - len(strings.TrimLeft(input, "0123456789"))
+	n, err := strconv.Atoi(input[:digits])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w %q (use e.g. 7d, 2w or 1mo)", ErrInvalidAge, input)
+	}
+
+	unit := strings.TrimSpace(input[digits:])
+	switch unit {
+	case "h", "hour", "hours":
+		return now.Add(-time.Duration(n) * time.Hour), nil
+	case "mo":
+		unit = "m"
+	case "y", "year", "years":
+		return now.AddDate(-n, 0, 0), nil
+	}
+	if t, ok := addUnits(now, -n, unit); ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%w %q (use e.g. 7d, 2w or 1mo)", ErrInvalidAge, input)
+}
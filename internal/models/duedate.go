@@ -0,0 +1,191 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDate is returned when a due date cannot be parsed
+var ErrInvalidDate = fmt.Errorf("%w: unrecognized date", ErrValidation)
+
+// DueDateFormat is the layout used to display and prefill due dates
+const DueDateFormat = "2006-01-02"
+
+// dueDateLayouts are the absolute formats accepted by ParseDueDate.
+// Layouts without a year are taken to be in the current year.
+var dueDateLayouts = []struct {
+	layout  string
+	hasYear bool
+}{
+	{"2006-01-02", true},
+	{"2006/01/02", true},
+	{"Jan 2 2006", true},
+	{"Jan 2, 2006", true},
+	{"January 2 2006", true},
+	{"January 2, 2006", true},
+	{"2 Jan 2006", true},
+	{"Jan 2", false},
+	{"January 2", false},
+	{"2 Jan", false},
+}
+
+// ParseDueDate parses a due date relative to now. It accepts absolute
+// dates such as "2024-03-01" or "Mar 1", and natural phrases such as
+// "today", "tomorrow", "friday", "next week", "in 3 days" or "+2w".
+// Empty input clears the date and returns nil. Dates resolve to local
+// midnight of the day.
+func ParseDueDate(input string, now time.Time) (*time.Time, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "" {
+		return nil, nil
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	day, ok := parseRelativeDate(input, today)
+	if !ok {
+		day, ok = parseAbsoluteDate(input, today)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidDate, input)
+	}
+	return &day, nil
+}
+
+func parseRelativeDate(input string, today time.Time) (time.Time, bool) {
+	switch input {
+	case "today":
+		return today, true
+	case "tomorrow", "tmr":
+		return today.AddDate(0, 0, 1), true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "next week":
+		return today.AddDate(0, 0, 7), true
+	case "next month":
+		return today.AddDate(0, 1, 0), true
+	}
+
+	if weekday, ok := parseWeekday(strings.TrimPrefix(input, "next ")); ok {
+		days := (int(weekday) - int(today.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, days), true
+	}
+
+	// "+3d" / "+2w" / "+1m"
+	if strings.HasPrefix(input, "+") && len(input) > 2 {
+		n, err := strconv.Atoi(input[1 : len(input)-1])
+		if err != nil || n < 0 {
+			return time.Time{}, false
+		}
+		return addUnits(today, n, input[len(input)-1:])
+	}
+
+	// "in 3 days" / "in 2 weeks" / "in 1 month"
+	if fields := strings.Fields(input); len(fields) == 3 && fields[0] == "in" {
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n < 0 {
+			return time.Time{}, false
+		}
+		return addUnits(today, n, fields[2])
+	}
+
+	return time.Time{}, false
+}
+
+func addUnits(today time.Time, n int, unit string) (time.Time, bool) {
+	switch unit {
+	case "d", "day", "days":
+		return today.AddDate(0, 0, n), true
+	case "w", "week", "weeks":
+		return today.AddDate(0, 0, 7*n), true
+	case "m", "month", "months":
+		return today.AddDate(0, n, 0), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+func parseAbsoluteDate(input string, today time.Time) (time.Time, bool) {
+	for _, l := range dueDateLayouts {
+		t, err := time.ParseInLocation(l.layout, input, today.Location())
+		if err != nil {
+			continue
+		}
+		year := t.Year()
+		if !l.hasYear {
+			year = today.Year()
+		}
+		return time.Date(year, t.Month(), t.Day(), 0, 0, 0, 0, today.Location()), true
+	}
+	return time.Time{}, false
+}
+
+// Due ranges accepted by ApplyDueRange
+const (
+	DueToday   = "today"
+	DueWeek    = "week"
+	DueOverdue = "overdue"
+	DueNone    = "none"
+)
+
+// DueRanges lists the due ranges in the order they are documented
+var DueRanges = []string{DueToday, DueWeek, DueOverdue, DueNone}
+
+// ApplyDueRange narrows opts to open todos due today, within the seven
+// days starting today, before today, or with no due date at all, relative
+// to now. Today and overdue match the sections of the TUI's Today view.
+func (o *ListOptions) ApplyDueRange(name string, now time.Time) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	endOfDay := func(days int) *time.Time {
+		end := today.AddDate(0, 0, days).Add(-time.Second)
+		return &end
+	}
+
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case DueToday:
+		o.DueAfter, o.DueBefore = &today, endOfDay(1)
+	case DueWeek:
+		o.DueAfter, o.DueBefore = &today, endOfDay(7)
+	case DueOverdue:
+		o.DueBefore = endOfDay(0)
+	case DueNone:
+		o.NoDueDate = true
+	default:
+		return fmt.Errorf("%w: unknown due range %q (use %s)", ErrValidation, name, strings.Join(DueRanges, ", "))
+	}
+
+	isTodo, isDone := true, false
+	o.IsTodo, o.IsDone = &isTodo, &isDone
+	return nil
+}
+
+// IsOverdue reports whether the note is an open todo due before the day of now.
+func (n *Note) IsOverdue(now time.Time) bool {
+	if !n.IsTodo || n.IsDone || n.DueDate == nil {
+		return false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return n.DueDate.Before(today)
+}
+
+// SameDueDate reports whether two optional due dates are equal
+func SameDueDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -1,12 +1,21 @@
 package models
 
 import (
-	"errors"
+	"fmt"
 	"time"
 )
 
+// FolderPathSeparator joins folder names in a folder path
+const FolderPathSeparator = " / "
+
 // ErrEmptyFolderName is returned when folder name is empty
-var ErrEmptyFolderName = errors.New("folder name cannot be empty")
+var ErrEmptyFolderName = fmt.Errorf("%w: folder name cannot be empty", ErrValidation)
+
+// ErrFolderTooDeep is returned when a folder would exceed the maximum nesting depth
+var ErrFolderTooDeep = fmt.Errorf("%w: folder nesting too deep", ErrValidation)
+
+// ErrFolderCycle is returned when a folder would become its own ancestor
+var ErrFolderCycle = fmt.Errorf("%w: folder cannot be moved into itself", ErrValidation)
 
 // Folder represents a folder for organizing notes
 type Folder struct {
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidLabel is returned for a label color that is not in NoteLabels
+var ErrInvalidLabel = fmt.Errorf("%w: unknown label", ErrValidation)
+
+// NoteLabels are the label colors a note can carry, in cycle order
+var NoteLabels = []string{"red", "orange", "yellow", "green", "blue", "purple"}
+
+// ParseLabel normalizes a label name. "none", "clear" and the empty string
+// remove the label.
+func ParseLabel(s string) (string, error) {
+	label := strings.ToLower(strings.TrimSpace(s))
+	switch label {
+	case "", "none", "clear":
+		return "", nil
+	}
+	for _, l := range NoteLabels {
+		if l == label {
+			return label, nil
+		}
+	}
+	return "", fmt.Errorf("%w %q (use %s or none)", ErrInvalidLabel, s, strings.Join(NoteLabels, ", "))
+}
+
+// NextLabel returns the label after current in NoteLabels, going back to
+// no label after the last one.
+func NextLabel(current string) string {
+	for i, l := range NoteLabels {
+		if l == current {
+			if i == len(NoteLabels)-1 {
+				return ""
+			}
+			return NoteLabels[i+1]
+		}
+	}
+	return NoteLabels[0]
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// wikiLink matches [[Title]] and [[Title|label]] links between notes
+var wikiLink = regexp.MustCompile(`\[\[([^\[\]|]+)(?:\|[^\[\]]*)?\]\]`)
+
+// ParseLinks returns the note titles linked from content with [[Title]],
+// de-duplicated case-insensitively in first-seen order. Links inside fenced
+// code blocks are ignored.
+func ParseLinks(content string) []string {
+	matches := wikiLink.FindAllStringSubmatch(withoutCodeFences(content), -1)
+
+	links := make([]string, 0, len(matches))
+	seen := make(map[string]bool, len(matches))
+	for _, m := range matches {
+		title := strings.TrimSpace(m[1])
+		key := strings.ToLower(title)
+		if title == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		links = append(links, title)
+	}
+	return links
+}
+
+// withoutCodeFences drops the lines of fenced code blocks from content. As
+// in CommonMark, a block closes only at a bare fence of the same character
+// at least as long as the one that opened it, so shorter fences inside it
+// stay code, and a block that is never closed runs to the end.
+func withoutCodeFences(content string) string {
+	if !strings.Contains(content, "```") && !strings.Contains(content, "~~~") {
+		return content
+	}
+	var b strings.Builder
+	open := "" // the fence of the block being dropped
+	for _, line := range strings.Split(content, "\n") {
+		fence, info := codeFence(line)
+		if open == "" && fence != "" {
+			open = fence
+			continue
+		}
+		if open != "" {
+			if info == "" && fence != "" && fence[0] == open[0] && len(fence) >= len(open) {
+				open = ""
+			}
+			continue
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// codeFence returns the run of three or more backticks or tildes a line
+// starts with, if any, and the info string after it
+func codeFence(line string) (fence, info string) {
+	line = strings.TrimSpace(line)
+	for _, c := range []byte{'`', '~'} {
+		n := 0
+		for n < len(line) && line[n] == c {
+			n++
+		}
+		if n >= 3 {
+			return line[:n], strings.TrimSpace(line[n:])
+		}
+	}
+	return "", ""
+}
+
+// Links returns the titles of the notes this note links to.
+func (n *Note) Links() []string {
+	return ParseLinks(n.Content)
+}
+
+// LinksTo reports whether the note links to title, ignoring case.
+func (n *Note) LinksTo(title string) bool {
+	for _, link := range n.Links() {
+		if strings.EqualFold(link, title) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"plain", "see [[Alpha]] and [[Beta]]", []string{"Alpha", "Beta"}},
+		{"label and duplicates", "[[Alpha|the first]] then [[alpha]] and [[ Beta ]]", []string{"Alpha", "Beta"}},
+		{"empty target", "[[ ]] and [[|label]]", []string{}},
+		{"inside a fence", "[[Alpha]]\n```\n[[Code]]\n```\n[[Beta]]", []string{"Alpha", "Beta"}},
+		{"fence with info string", "```go\n[[Code]]\n```\n[[Beta]]", []string{"Beta"}},
+		{"indented fence", "  ```\n[[Code]]\n  ```\n[[Beta]]", []string{"Beta"}},
+		{"tilde fence", "~~~\n[[Code]]\n```\n[[Still]]\n~~~\n[[Beta]]", []string{"Beta"}},
+		{"nested shorter fence", "````md\n```\n[[Inner]]\n```\n[[Outer]]\n````\n[[Beta]]", []string{"Beta"}},
+		{"info string does not close", "```\n```go\n[[Code]]\n```\n[[Beta]]", []string{"Beta"}},
+		{"longer fence closes", "```\n[[Code]]\n`````\n[[Beta]]", []string{"Beta"}},
+		{"unterminated fence", "[[Alpha]]\n```\n[[Code]]\n[[More]]", []string{"Alpha"}},
+		{"inline backticks", "`[[Code]]` is not a fence, [[Beta]]", []string{"Code", "Beta"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseLinks(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLinks(%q) = %v, want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,11 @@ package models
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // Priority levels for todos
@@ -13,8 +17,25 @@ const (
 	PriorityHigh   = 3
 )
 
+// ErrValidation is wrapped by all errors caused by invalid user input,
+// so callers can detect them with errors.Is.
+var ErrValidation = errors.New("invalid input")
+
 // ErrEmptyTitle is returned when note title is empty
-var ErrEmptyTitle = errors.New("title cannot be empty")
+var ErrEmptyTitle = fmt.Errorf("%w: title cannot be empty", ErrValidation)
+
+// ErrLocked is returned when changing or deleting a locked note
+var ErrLocked = fmt.Errorf("%w: note is locked, unlock it first", ErrValidation)
+
+// ErrEncrypted is returned when an action needs the plain content of an
+// encrypted note
+var ErrEncrypted = fmt.Errorf("%w: note is encrypted", ErrValidation)
+
+// ErrContentTooLarge is returned when note content exceeds the size limit
+var ErrContentTooLarge = fmt.Errorf("%w: note content too large", ErrValidation)
+
+// ErrTitleTooLong is returned when a note title exceeds the length limit
+var ErrTitleTooLong = fmt.Errorf("%w: title too long", ErrValidation)
 
 // Note represents a note or todo item
 type Note struct {
@@ -29,8 +50,17 @@ type Note struct {
 	DueDate    *time.Time `json:"due_date,omitempty"`
 	Tags       string     `json:"tags"`
 	Starred    bool       `json:"starred"`
+	Locked     bool       `json:"locked"`
+	Label      string     `json:"label,omitempty"`
+	Position   int        `json:"position"`
+	Encrypted  bool       `json:"encrypted"`
 	CreatedAt  time.Time  `json:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// EditedExternally is set on load when content is stored in files and
+	// the note's file changed outside kiroku after kiroku last saved the
+	// note. It is not stored.
+	EditedExternally bool `json:"edited_externally,omitempty"`
 }
 
 // Validate validates the note fields
@@ -44,6 +74,115 @@ func (n *Note) Validate() error {
 	return nil
 }
 
+// CheckContentSize rejects content longer than maxBytes. A maxBytes of
+// zero or less disables the check.
+func (n *Note) CheckContentSize(maxBytes int) error {
+	if maxBytes > 0 && len(n.Content) > maxBytes {
+		return fmt.Errorf("%w: %d bytes, the limit is %d", ErrContentTooLarge, len(n.Content), maxBytes)
+	}
+	return nil
+}
+
+// CheckTitleLength rejects a title longer than maxLength characters. A
+// maxLength of zero or less disables the check.
+func (n *Note) CheckTitleLength(maxLength int) error {
+	if length := utf8.RuneCountInString(n.Title); maxLength > 0 && length > maxLength {
+		return fmt.Errorf("%w: %d characters, the limit is %d", ErrTitleTooLong, length, maxLength)
+	}
+	return nil
+}
+
+// TruncateTitle shortens a title to maxLength characters, ending it with
+// an ellipsis, and reports whether it had to. A maxLength of zero or less
+// leaves the title alone.
+func TruncateTitle(title string, maxLength int) (string, bool) {
+	if maxLength <= 0 || utf8.RuneCountInString(title) <= maxLength {
+		return title, false
+	}
+	runes := []rune(title)[:maxLength-1]
+	return strings.TrimRight(string(runes), " ") + "…", true
+}
+
+// HasChanges reports whether an edited title/content differs from the note.
+// Surrounding whitespace in content is ignored since the editor round trip trims it.
+func (n *Note) HasChanges(title, content string) bool {
+	return n.Title != title || strings.TrimSpace(n.Content) != strings.TrimSpace(content)
+}
+
+// Snippet returns the first line of content that is not blank or a
+// heading, with common markdown markers stripped. It is empty when the
+// note has no such line, and for encrypted notes.
+func (n *Note) Snippet() string {
+	if n.Encrypted {
+		return ""
+	}
+	for _, line := range strings.Split(n.Content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "```") {
+			continue
+		}
+
+		line = strings.TrimSpace(strings.TrimLeft(line, ">-*+ \t"))
+		for _, box := range []string{"[ ]", "[x]", "[X]"} {
+			line = strings.TrimSpace(strings.TrimPrefix(line, box))
+		}
+		line = markdownLink.ReplaceAllString(line, "$1")
+		line = markdownEmphasis.Replace(line)
+		if line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// ChecklistProgress counts the markdown checklist items ("- [ ]" and
+// "- [x]") in content and how many are checked. Items inside fenced code
+// blocks are ignored.
+func (n *Note) ChecklistProgress() (done, total int) {
+	fenced := false
+	for _, line := range strings.Split(n.Content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+
+		item, ok := listItemText(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(item, "[ ]"):
+			total++
+		case strings.HasPrefix(item, "[x]"), strings.HasPrefix(item, "[X]"):
+			done++
+			total++
+		}
+	}
+	return done, total
+}
+
+// listItemText strips a bullet ("-", "*", "+") or number ("1." or "1)")
+// marker from a trimmed line, reporting false when it is not a list item
+func listItemText(line string) (string, bool) {
+	if len(line) > 1 && strings.ContainsRune("-*+", rune(line[0])) && line[1] == ' ' {
+		return strings.TrimSpace(line[2:]), true
+	}
+	digits := len(line) - len(strings.TrimLeft(line, "0123456789"))
+	if digits > 0 && len(line) > digits+1 && strings.ContainsRune(".)", rune(line[digits])) && line[digits+1] == ' ' {
+		return strings.TrimSpace(line[digits+2:]), true
+	}
+	return "", false
+}
+
+var (
+	markdownLink     = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	markdownEmphasis = strings.NewReplacer("**", "", "__", "", "`", "", "~~", "")
+)
+
 // ToggleDone toggles the done status of a todo
 func (n *Note) ToggleDone() {
 	if n.IsTodo {
@@ -104,15 +243,25 @@ func (n *Note) StatusIcon() string {
 
 // ListOptions contains options for listing notes
 type ListOptions struct {
-	FolderID  *int64
-	IsTodo    *bool
-	IsDone    *bool
-	Starred   *bool
-	Priority  *int
-	OrderBy   string
-	OrderDesc bool
-	Limit     int
-	Offset    int
+	FolderID     *int64
+	IsTodo       *bool
+	IsDone       *bool
+	Starred      *bool
+	Priority     *int
+	Tag          string     // exact tag, matched case-insensitively
+	Label        string     // label color; empty matches any
+	DueBefore    *time.Time // due at or before
+	DueAfter     *time.Time // due at or after
+	NoDueDate    bool       // only notes without a due date
+	UpdatedSince *time.Time // updated at or after
+	UpdatedUntil *time.Time // updated at or before
+	CreatedSince *time.Time // created at or after
+	CreatedUntil *time.Time // created at or before
+	Unfiled      bool       // only notes without a folder or whose folder no longer exists
+	OrderBy      string
+	OrderDesc    bool
+	Limit        int
+	Offset       int
 }
 
 // SearchResult represents a search result with highlight info
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NoteSort describes how a user-facing sort key orders notes
+type NoteSort struct {
+	// Column is the notes column to order by
+	Column string
+	// Desc is the natural direction for the key, e.g. newest first
+	Desc bool
+}
+
+// NoteSorts maps the sort keys accepted from users to their ordering
+var NoteSorts = map[string]NoteSort{
+	"created":  {Column: "created_at", Desc: true},
+	"updated":  {Column: "updated_at", Desc: true},
+	"title":    {Column: "title"},
+	"priority": {Column: "priority", Desc: true},
+	"due":      {Column: "due_date"},
+	"manual":   {Column: "position"},
+}
+
+// ParseNoteSort looks up a sort key, ignoring case.
+func ParseNoteSort(key string) (NoteSort, error) {
+	s, ok := NoteSorts[strings.ToLower(strings.TrimSpace(key))]
+	if !ok {
+		keys := make([]string, 0, len(NoteSorts))
+		for k := range NoteSorts {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return NoteSort{}, fmt.Errorf("%w: unknown sort %q (use %s)", ErrValidation, key, strings.Join(keys, ", "))
+	}
+	return s, nil
+}
+
+// IsNoteOrderColumn reports whether column may be used in ListOptions.OrderBy.
+func IsNoteOrderColumn(column string) bool {
+	for _, s := range NoteSorts {
+		if s.Column == column {
+			return true
+		}
+	}
+	return false
+}
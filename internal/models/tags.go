@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrEmptyTag is returned when a tag is empty after normalization
+var ErrEmptyTag = fmt.Errorf("%w: tag cannot be empty", ErrValidation)
+
+// tagSeparator joins tags when they are stored in the notes.tags column.
+const tagSeparator = ","
+
+// TagCount is a tag with the number of notes that carry it
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// NormalizeTag lowercases a tag and strips surrounding space and a leading '#'.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// ParseTags splits a stored tag string on commas and whitespace into
+// normalized, de-duplicated tags, keeping their first-seen order.
+func ParseTags(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+
+	tags := make([]string, 0, len(fields))
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		tag := NormalizeTag(field)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// FormatTags joins tags into the stored tag string, normalizing and
+// de-duplicating them.
+func FormatTags(tags []string) string {
+	return strings.Join(ParseTags(strings.Join(tags, tagSeparator)), tagSeparator)
+}
+
+// TagList returns the note's tags as a normalized set.
+func (n *Note) TagList() []string {
+	return ParseTags(n.Tags)
+}
+
+// HasTag reports whether the note carries the tag, ignoring case.
+func (n *Note) HasTag(tag string) bool {
+	tag = NormalizeTag(tag)
+	for _, t := range n.TagList() {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTags replaces the note's tags.
+func (n *Note) SetTags(tags []string) {
+	n.Tags = FormatTags(tags)
+}
+
+// HasAllTags reports whether the note carries every one of tags.
+func (n *Note) HasAllTags(tags []string) bool {
+	for _, tag := range tags {
+		if !n.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasAnyTag reports whether the note carries at least one of tags.
+func (n *Note) HasAnyTag(tags []string) bool {
+	for _, tag := range tags {
+		if n.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,7 +2,9 @@ package models
 
 import (
 	"encoding/json"
-	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,7 +15,7 @@ const (
 )
 
 // ErrEmptyTemplateName is returned when template name is empty
-var ErrEmptyTemplateName = errors.New("template name cannot be empty")
+var ErrEmptyTemplateName = fmt.Errorf("%w: template name cannot be empty", ErrValidation)
 
 // TemplateVariable represents a template variable
 type TemplateVariable struct {
@@ -34,6 +36,10 @@ type Template struct {
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 
+	// Defaults applied to notes created from the template
+	AutoStar        bool `json:"auto_star"`
+	DefaultPriority int  `json:"default_priority"`
+
 	// Parsed variables (not stored in DB)
 	ParsedVariables []TemplateVariable `json:"-"`
 }
@@ -46,6 +52,9 @@ func (t *Template) Validate() error {
 	if t.Type != TemplateTypeNote && t.Type != TemplateTypeTodo {
 		t.Type = TemplateTypeNote
 	}
+	if t.DefaultPriority < PriorityNone || t.DefaultPriority > PriorityHigh {
+		t.DefaultPriority = PriorityNone
+	}
 	if t.Icon == "" {
 		if t.Type == TemplateTypeTodo {
 			t.Icon = "☐"
@@ -56,6 +65,35 @@ func (t *Template) Validate() error {
 	return nil
 }
 
+// ApplyDefaults seeds a new note with the template's content and flags.
+// Fields the note already sets are kept: existing content and priority
+// win, while starring and todo type are only ever switched on.
+func (t *Template) ApplyDefaults(note *Note) {
+	if note.Content == "" {
+		note.Content = t.Content
+	}
+	if t.AutoStar {
+		note.Starred = true
+	}
+	if t.Type == TemplateTypeTodo {
+		note.IsTodo = true
+	}
+	if note.Priority == PriorityNone {
+		note.Priority = t.DefaultPriority
+	}
+}
+
+// Render returns the template content with its placeholders filled in:
+// {{title}}, {{date}} and {{week_number}}.
+func (t *Template) Render(title string, now time.Time) string {
+	_, week := now.ISOWeek()
+	return strings.NewReplacer(
+		"{{title}}", title,
+		"{{date}}", now.Format(DueDateFormat),
+		"{{week_number}}", strconv.Itoa(week),
+	).Replace(t.Content)
+}
+
 // GetVariables parses and returns template variables
 func (t *Template) GetVariables() ([]TemplateVariable, error) {
 	if t.Variables == "" {
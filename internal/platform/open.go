@@ -0,0 +1,56 @@
+// Package platform wraps the operating system integrations kiroku uses
+// outside the terminal.
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// ErrUnsupported is returned when kiroku does not know how to reach the
+// desktop on the current operating system
+var ErrUnsupported = errors.New("not supported on this operating system")
+
+// startCommand launches name without waiting for it to finish. It is a
+// variable so the launcher can be swapped out without touching the OS.
+var startCommand = func(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Reap the launcher in the background so it does not linger as a zombie
+	go cmd.Wait()
+	return nil
+}
+
+// OpenPath opens a file or directory with the system default application
+// and returns without waiting for it to close
+func OpenPath(path string) error {
+	name, args, err := openCommand(runtime.GOOS, path)
+	if err != nil {
+		return err
+	}
+	if err := startCommand(name, args...); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return fmt.Errorf("open %s: %s not found in PATH", path, name)
+		}
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	return nil
+}
+
+// openCommand returns the launcher that opens path on goos
+func openCommand(goos, path string) (string, []string, error) {
+	switch goos {
+	case "darwin":
+		return "open", []string{path}, nil
+	case "linux", "freebsd", "openbsd", "netbsd", "dragonfly":
+		return "xdg-open", []string{path}, nil
+	case "windows":
+		return "explorer", []string{path}, nil
+	default:
+		return "", nil, fmt.Errorf("open %s: %w (%s)", path, ErrUnsupported, goos)
+	}
+}
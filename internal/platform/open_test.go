@@ -0,0 +1,92 @@
+package platform
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestOpenCommand(t *testing.T) {
+	tests := []struct {
+		goos     string
+		wantName string
+		wantErr  bool
+	}{
+		{"darwin", "open", false},
+		{"linux", "xdg-open", false},
+		{"freebsd", "xdg-open", false},
+		{"openbsd", "xdg-open", false},
+		{"windows", "explorer", false},
+		{"plan9", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			name, args, err := openCommand(tt.goos, "/tmp/notes dir")
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnsupported) || !strings.Contains(err.Error(), tt.goos) {
+					t.Errorf("error = %v, want ErrUnsupported naming %s", err, tt.goos)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("openCommand() error = %v", err)
+			}
+			if name != tt.wantName || !reflect.DeepEqual(args, []string{"/tmp/notes dir"}) {
+				t.Errorf("got %s %q, want %s with the path as one argument", name, args, tt.wantName)
+			}
+		})
+	}
+}
+
+// stubStart replaces the launcher for the test, recording what it was asked
+// to run and failing with err
+func stubStart(t *testing.T, err error) *[]string {
+	t.Helper()
+	var ran []string
+	saved := startCommand
+	startCommand = func(name string, args ...string) error {
+		ran = append([]string{name}, args...)
+		return err
+	}
+	t.Cleanup(func() { startCommand = saved })
+	return &ran
+}
+
+func TestOpenPath(t *testing.T) {
+	want, _, supportErr := openCommand(runtime.GOOS, "")
+	if supportErr != nil {
+		t.Skipf("no launcher on %s", runtime.GOOS)
+	}
+
+	tests := []struct {
+		name    string
+		err     error
+		wantErr string
+	}{
+		{"started", nil, ""},
+		{"launcher missing", &exec.Error{Name: want, Err: exec.ErrNotFound}, want + " not found in PATH"},
+		{"launcher failed", errors.New("permission denied"), "permission denied"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := stubStart(t, tt.err)
+
+			err := OpenPath("/tmp/note.md")
+			if !reflect.DeepEqual(*ran, []string{want, "/tmp/note.md"}) {
+				t.Errorf("ran %q, want %s /tmp/note.md", *ran, want)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("OpenPath() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("OpenPath() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// ContentFiles stores note content as markdown files named by note ID,
+// so notes can be edited, grepped and versioned outside kiroku
+type ContentFiles struct {
+	dir string
+}
+
+// NewContentFiles creates a content store rooted at dir
+func NewContentFiles(dir string) *ContentFiles {
+	return &ContentFiles{dir: dir}
+}
+
+// Dir returns the directory holding the files
+func (f *ContentFiles) Dir() string {
+	return f.dir
+}
+
+// Path returns the file that holds a note's content
+func (f *ContentFiles) Path(id int64) string {
+	return filepath.Join(f.dir, strconv.FormatInt(id, 10)+".md")
+}
+
+// Read returns a note's content. ok is false when the note has no file.
+func (f *ContentFiles) Read(id int64) (content string, ok bool, err error) {
+	data, err := os.ReadFile(f.Path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("read note file: %w", err)
+	}
+	return string(data), true, nil
+}
+
+// ModTime returns when a note's file was last modified
+func (f *ContentFiles) ModTime(id int64) (time.Time, error) {
+	info, err := os.Stat(f.Path(id))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("stat note file: %w", err)
+	}
+	return info.ModTime(), nil
+}
+
+// Write replaces a note's file and stamps it with modTime, the note's
+// update time, so a later change by another program shows as a newer
+// file. The content goes to a temporary file first so a crash never
+// leaves a half-written note.
+func (f *ContentFiles) Write(id int64, content string, modTime time.Time) error {
+	staged, err := f.Stage(id, content, modTime)
+	if err != nil {
+		return err
+	}
+	defer staged.Discard()
+	return staged.Commit()
+}
+
+// StagedFile is a note's new content written beside its file, waiting to
+// replace it. A nil StagedFile does nothing, for notes kept in SQLite.
+type StagedFile struct {
+	tmp     string
+	path    string
+	modTime time.Time
+}
+
+// Stage writes a note's new content to a temporary file without touching
+// the note's file, so a database change can be committed before the file
+// follows it. Commit moves the content into place; Discard drops it.
+func (f *ContentFiles) Stage(id int64, content string, modTime time.Time) (*StagedFile, error) {
+	if err := os.MkdirAll(f.dir, 0755); err != nil {
+		return nil, fmt.Errorf("create notes directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(f.dir, ".note-*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("create temp note file: %w", err)
+	}
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("write note file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("write note file: %w", err)
+	}
+	return &StagedFile{tmp: tmp.Name(), path: f.Path(id), modTime: modTime}, nil
+}
+
+// Commit replaces the note's file with the staged content
+func (s *StagedFile) Commit() error {
+	if s == nil {
+		return nil
+	}
+	if err := os.Rename(s.tmp, s.path); err != nil {
+		return fmt.Errorf("replace note file: %w", err)
+	}
+	if err := os.Chtimes(s.path, s.modTime, s.modTime); err != nil {
+		return fmt.Errorf("set note file time: %w", err)
+	}
+	return nil
+}
+
+// Discard removes the staged content if it was not committed
+func (s *StagedFile) Discard() {
+	if s == nil {
+		return
+	}
+	os.Remove(s.tmp)
+}
+
+// Remove deletes a note's file if it exists
+func (f *ContentFiles) Remove(id int64) error {
+	if err := os.Remove(f.Path(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove note file: %w", err)
+	}
+	return nil
+}
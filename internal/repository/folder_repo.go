@@ -22,7 +22,9 @@ func NewFolderRepository(db *database.DB) *FolderRepository {
 }
 
 // Create creates a new folder
-func (r *FolderRepository) Create(ctx context.Context, folder *models.Folder) error {
+func (r *FolderRepository) Create(ctx context.Context, folder *models.Folder) (err error) {
+	defer database.MarkBusy(&err)
+
 	if err := folder.Validate(); err != nil {
 		return err
 	}
@@ -88,7 +90,9 @@ func (r *FolderRepository) GetByID(ctx context.Context, id int64) (*models.Folde
 }
 
 // Update updates an existing folder
-func (r *FolderRepository) Update(ctx context.Context, folder *models.Folder) error {
+func (r *FolderRepository) Update(ctx context.Context, folder *models.Folder) (err error) {
+	defer database.MarkBusy(&err)
+
 	if err := folder.Validate(); err != nil {
 		return err
 	}
@@ -125,11 +129,32 @@ func (r *FolderRepository) Update(ctx context.Context, folder *models.Folder) er
 	return nil
 }
 
-// Delete deletes a folder by ID
-func (r *FolderRepository) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM folders WHERE id = ?`
+// Delete deletes a folder and its subfolders. Notes in any of the deleted
+// folders are moved out to the top level rather than left pointing at a
+// folder that no longer exists, whether or not SQLite enforces the
+// foreign keys.
+func (r *FolderRepository) Delete(ctx context.Context, id int64) (err error) {
+	defer database.MarkBusy(&err)
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	subtree := `
+		WITH RECURSIVE subtree(id) AS (
+			SELECT id FROM folders WHERE id = ?
+			UNION
+			SELECT f.id FROM folders f JOIN subtree s ON f.parent_id = s.id
+		)
+	`
+
+	if _, err := tx.ExecContext(ctx, subtree+`UPDATE notes SET folder_id = NULL WHERE folder_id IN (SELECT id FROM subtree)`, id); err != nil {
+		return fmt.Errorf("unfile notes: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, subtree+`DELETE FROM folders WHERE id IN (SELECT id FROM subtree)`, id)
 	if err != nil {
 		return fmt.Errorf("delete folder: %w", err)
 	}
@@ -142,6 +167,9 @@ func (r *FolderRepository) Delete(ctx context.Context, id int64) error {
 		return ErrNotFound
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
 	return nil
 }
 
@@ -12,7 +12,9 @@ import (
 // This enables dependency injection and easier testing.
 type NoteRepositoryInterface interface {
 	Create(ctx context.Context, note *models.Note) error
+	CreateIfTitleMissing(ctx context.Context, note *models.Note) (bool, error)
 	GetByID(ctx context.Context, id int64) (*models.Note, error)
+	GetByTitle(ctx context.Context, title string) (*models.Note, error)
 	Update(ctx context.Context, note *models.Note) error
 	Delete(ctx context.Context, id int64) error
 	List(ctx context.Context, opts models.ListOptions) ([]*models.Note, error)
@@ -21,6 +23,17 @@ type NoteRepositoryInterface interface {
 	GetTodos(ctx context.Context, done *bool) ([]*models.Note, error)
 	GetStarred(ctx context.Context) ([]*models.Note, error)
 	GetRecent(ctx context.Context, limit int) ([]*models.Note, error)
+	GetUnfiled(ctx context.Context) ([]*models.Note, error)
+	ClearDanglingFolders(ctx context.Context) (int, error)
+	ExportContent(ctx context.Context, files *ContentFiles) (int, error)
+	ImportContent(ctx context.Context, files *ContentFiles) (int, error)
+	UpdateTags(ctx context.Context, tags map[int64]string) error
+	SetDone(ctx context.Context, ids []int64, done bool) (int, error)
+	SetStarred(ctx context.Context, ids []int64, starred bool) (int, error)
+	SwapPositions(ctx context.Context, id, otherID int64) error
+	AppendContent(ctx context.Context, id int64, line string) error
+	Merge(ctx context.Context, dst *models.Note, deleteIDs []int64) error
+	ListTagStrings(ctx context.Context) ([]string, error)
 }
 
 // FolderRepositoryInterface defines the contract for folder data access.
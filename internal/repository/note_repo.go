@@ -12,6 +12,10 @@ import (
 	"github.com/tranducquang/kiroku/internal/models"
 )
 
+// storedTimeLayout matches the sortable prefix of timestamps as the
+// sqlite driver stores them, so range filters can compare text.
+const storedTimeLayout = "2006-01-02 15:04:05"
+
 // Common errors
 var (
 	ErrNotFound = errors.New("not found")
@@ -19,30 +23,58 @@ var (
 
 // NoteRepository handles note database operations
 type NoteRepository struct {
-	db *database.DB
+	db    *database.DB
+	files *ContentFiles
 }
 
-// NewNoteRepository creates a new note repository
-func NewNoteRepository(db *database.DB) *NoteRepository {
-	return &NoteRepository{db: db}
+// NewNoteRepository creates a new note repository. With files set, note
+// content is read from and written to those files, and SQLite keeps a
+// copy only for full-text search.
+func NewNoteRepository(db *database.DB, files *ContentFiles) *NoteRepository {
+	return &NoteRepository{db: db, files: files}
 }
 
 // Create creates a new note
 func (r *NoteRepository) Create(ctx context.Context, note *models.Note) error {
+	_, err := r.insert(ctx, note, false)
+	return err
+}
+
+// CreateIfTitleMissing creates note unless a note with its title already
+// exists, and reports whether it did. The check and the insert are one
+// statement, so two writers cannot both create the note.
+func (r *NoteRepository) CreateIfTitleMissing(ctx context.Context, note *models.Note) (bool, error) {
+	return r.insert(ctx, note, true)
+}
+
+// insert adds note, skipping it when titleMissing is set and its title is
+// taken
+func (r *NoteRepository) insert(ctx context.Context, note *models.Note, titleMissing bool) (created bool, err error) {
+	defer database.MarkBusy(&err)
+
 	if err := note.Validate(); err != nil {
-		return err
+		return false, err
 	}
 
 	query := `
-		INSERT INTO notes (title, content, folder_id, template_id, is_todo, is_done, priority, due_date, tags, starred, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO notes (title, content, folder_id, template_id, is_todo, is_done, priority, due_date, tags, starred, is_locked, label, position, is_encrypted, created_at, updated_at)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, (SELECT COALESCE(MAX(position), 0) + 1 FROM notes), ?, ?, ?
 	`
+	if titleMissing {
+		query += `WHERE NOT EXISTS (SELECT 1 FROM notes WHERE title = ?)`
+	}
 
 	now := time.Now()
 	note.CreatedAt = now
 	note.UpdatedAt = now
 
-	result, err := r.db.ExecContext(ctx, query,
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	args := []any{
 		note.Title,
 		note.Content,
 		note.FolderID,
@@ -53,26 +85,45 @@ func (r *NoteRepository) Create(ctx context.Context, note *models.Note) error {
 		note.DueDate,
 		note.Tags,
 		note.Starred,
+		note.Locked,
+		note.Label,
+		note.Encrypted,
 		note.CreatedAt,
 		note.UpdatedAt,
-	)
+	}
+	if titleMissing {
+		args = append(args, note.Title)
+	}
+	result, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("create note: %w", err)
+		return false, fmt.Errorf("create note: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		return false, err
 	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
-		return fmt.Errorf("get last insert id: %w", err)
+		return false, fmt.Errorf("get last insert id: %w", err)
+	}
+
+	staged, err := r.stageContent(id, note.Content, note.UpdatedAt)
+	if err != nil {
+		return false, err
+	}
+	defer staged.Discard()
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit transaction: %w", err)
 	}
 	note.ID = id
 
-	return nil
+	return true, staged.Commit()
 }
 
 // GetByID retrieves a note by ID
 func (r *NoteRepository) GetByID(ctx context.Context, id int64) (*models.Note, error) {
 	query := `
-		SELECT id, title, content, folder_id, template_id, is_todo, is_done, priority, due_date, tags, starred, created_at, updated_at
+		SELECT id, title, content, folder_id, template_id, is_todo, is_done, priority, due_date, tags, starred, is_locked, label, position, is_encrypted, created_at, updated_at
 		FROM notes
 		WHERE id = ?
 	`
@@ -90,6 +141,10 @@ func (r *NoteRepository) GetByID(ctx context.Context, id int64) (*models.Note, e
 		&note.DueDate,
 		&note.Tags,
 		&note.Starred,
+		&note.Locked,
+		&note.Label,
+		&note.Position,
+		&note.Encrypted,
 		&note.CreatedAt,
 		&note.UpdatedAt,
 	)
@@ -99,25 +154,81 @@ func (r *NoteRepository) GetByID(ctx context.Context, id int64) (*models.Note, e
 		}
 		return nil, fmt.Errorf("get note by id: %w", err)
 	}
+	if err := r.loadContent(note); err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// GetByTitle retrieves the note with exactly the given title. Titles are
+// not unique, so when several notes share it the most recently updated
+// one is returned.
+func (r *NoteRepository) GetByTitle(ctx context.Context, title string) (*models.Note, error) {
+	query := `
+		SELECT id, title, content, folder_id, template_id, is_todo, is_done, priority, due_date, tags, starred, is_locked, label, position, is_encrypted, created_at, updated_at
+		FROM notes
+		WHERE title = ?
+		ORDER BY updated_at DESC, id DESC
+		LIMIT 1
+	`
+
+	note := &models.Note{}
+	err := r.db.QueryRowContext(ctx, query, title).Scan(
+		&note.ID,
+		&note.Title,
+		&note.Content,
+		&note.FolderID,
+		&note.TemplateID,
+		&note.IsTodo,
+		&note.IsDone,
+		&note.Priority,
+		&note.DueDate,
+		&note.Tags,
+		&note.Starred,
+		&note.Locked,
+		&note.Label,
+		&note.Position,
+		&note.Encrypted,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get note by title: %w", err)
+	}
+	if err := r.loadContent(note); err != nil {
+		return nil, err
+	}
 
 	return note, nil
 }
 
 // Update updates an existing note
-func (r *NoteRepository) Update(ctx context.Context, note *models.Note) error {
+func (r *NoteRepository) Update(ctx context.Context, note *models.Note) (err error) {
+	defer database.MarkBusy(&err)
+
 	if err := note.Validate(); err != nil {
 		return err
 	}
 
 	query := `
 		UPDATE notes
-		SET title = ?, content = ?, folder_id = ?, template_id = ?, is_todo = ?, is_done = ?, priority = ?, due_date = ?, tags = ?, starred = ?, updated_at = ?
+		SET title = ?, content = ?, folder_id = ?, template_id = ?, is_todo = ?, is_done = ?, priority = ?, due_date = ?, tags = ?, starred = ?, is_locked = ?, label = ?, is_encrypted = ?, updated_at = ?
 		WHERE id = ?
 	`
 
 	note.UpdatedAt = time.Now()
 
-	result, err := r.db.ExecContext(ctx, query,
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query,
 		note.Title,
 		note.Content,
 		note.FolderID,
@@ -128,6 +239,9 @@ func (r *NoteRepository) Update(ctx context.Context, note *models.Note) error {
 		note.DueDate,
 		note.Tags,
 		note.Starred,
+		note.Locked,
+		note.Label,
+		note.Encrypted,
 		note.UpdatedAt,
 		note.ID,
 	)
@@ -143,14 +257,31 @@ func (r *NoteRepository) Update(ctx context.Context, note *models.Note) error {
 		return ErrNotFound
 	}
 
-	return nil
+	staged, err := r.stageContent(note.ID, note.Content, note.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	defer staged.Discard()
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return staged.Commit()
 }
 
 // Delete deletes a note by ID
-func (r *NoteRepository) Delete(ctx context.Context, id int64) error {
+func (r *NoteRepository) Delete(ctx context.Context, id int64) (err error) {
+	defer database.MarkBusy(&err)
+
 	query := `DELETE FROM notes WHERE id = ?`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("delete note: %w", err)
 	}
@@ -163,37 +294,393 @@ func (r *NoteRepository) Delete(ctx context.Context, id int64) error {
 		return ErrNotFound
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return r.removeFiles(id)
+}
+
+// AppendContent adds line to the end of a note's content on its own line,
+// collapsing trailing blank lines first. The content is extended in the
+// statement that saves it, so appends running at once never overwrite
+// each other.
+func (r *NoteRepository) AppendContent(ctx context.Context, id int64, line string) (err error) {
+	defer database.MarkBusy(&err)
+
+	query := `
+		UPDATE notes
+		SET content = CASE
+				WHEN rtrim(content, char(10)) = '' THEN ?1
+				ELSE rtrim(content, char(10)) || char(10) || ?1
+			END,
+			updated_at = ?2
+		WHERE id = ?3
+		RETURNING content
+	`
+
+	updatedAt := time.Now()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var content string
+	if err := tx.QueryRowContext(ctx, query, line, updatedAt, id).Scan(&content); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("append note content: %w", err)
+	}
+
+	staged, err := r.stageContent(id, content, updatedAt)
+	if err != nil {
+		return err
+	}
+	defer staged.Discard()
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return staged.Commit()
+}
+
+// Merge saves dst's content and tags and deletes the notes in deleteIDs
+// in one transaction, so a failed merge leaves every note as it was
+func (r *NoteRepository) Merge(ctx context.Context, dst *models.Note, deleteIDs []int64) (err error) {
+	defer database.MarkBusy(&err)
+
+	dst.UpdatedAt = time.Now()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "UPDATE notes SET content = ?, tags = ?, updated_at = ? WHERE id = ?",
+		dst.Content, dst.Tags, dst.UpdatedAt, dst.ID)
+	if err != nil {
+		return fmt.Errorf("update note: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	for _, id := range deleteIDs {
+		result, err := tx.ExecContext(ctx, "DELETE FROM notes WHERE id = ?", id)
+		if err != nil {
+			return fmt.Errorf("delete note: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+		if rows == 0 {
+			return ErrNotFound
+		}
+	}
+
+	staged, err := r.stageContent(dst.ID, dst.Content, dst.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	defer staged.Discard()
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	if err := staged.Commit(); err != nil {
+		return err
+	}
+	return r.removeFiles(deleteIDs...)
+}
+
+// stageContent writes a note's new content beside its file when content
+// is stored in files. The file follows only once the caller commits the
+// transaction and then the staged file, so a failed commit leaves it as
+// it was.
+func (r *NoteRepository) stageContent(id int64, content string, modTime time.Time) (*StagedFile, error) {
+	if r.files == nil {
+		return nil, nil
+	}
+	return r.files.Stage(id, content, modTime)
+}
+
+// removeFiles deletes the files of notes whose deletion was committed
+func (r *NoteRepository) removeFiles(ids ...int64) error {
+	if r.files == nil {
+		return nil
+	}
+	for _, id := range ids {
+		if err := r.files.Remove(id); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// List retrieves notes based on options
-func (r *NoteRepository) List(ctx context.Context, opts models.ListOptions) ([]*models.Note, error) {
+// loadContent replaces the SQLite copy of each note's content with its
+// file when content is stored in files. A note without a file keeps the
+// SQLite copy.
+func (r *NoteRepository) loadContent(notes ...*models.Note) error {
+	return loadContent(r.files, notes...)
+}
+
+// externalEditSlack is how much newer than the note a file must be to
+// count as edited outside kiroku, absorbing file system time precision
+const externalEditSlack = time.Second
+
+// loadContent reads each note's content from files, if set. Reading never
+// writes: a file edited outside kiroku reaches the SQLite copy, and so
+// search, only when the note is next saved or the copy is refreshed with
+// ImportContent. A file newer than its note marks the note
+// EditedExternally until kiroku next saves it.
+func loadContent(files *ContentFiles, notes ...*models.Note) error {
+	if files == nil {
+		return nil
+	}
+	for _, note := range notes {
+		content, ok, err := files.Read(note.ID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		note.Content = content
+		modTime, err := files.ModTime(note.ID)
+		if err != nil {
+			return err
+		}
+		note.EditedExternally = modTime.After(note.UpdatedAt.Add(externalEditSlack))
+	}
+	return nil
+}
+
+// listConditions builds the WHERE conditions for the filters in opts.
+// The prefix qualifies column names when notes is joined under an alias.
+func listConditions(opts models.ListOptions, prefix string) ([]string, []interface{}) {
 	var conditions []string
 	var args []interface{}
 
 	if opts.FolderID != nil {
-		conditions = append(conditions, "folder_id = ?")
+		conditions = append(conditions, prefix+"folder_id = ?")
 		args = append(args, *opts.FolderID)
 	}
 	if opts.IsTodo != nil {
-		conditions = append(conditions, "is_todo = ?")
+		conditions = append(conditions, prefix+"is_todo = ?")
 		args = append(args, *opts.IsTodo)
 	}
 	if opts.IsDone != nil {
-		conditions = append(conditions, "is_done = ?")
+		conditions = append(conditions, prefix+"is_done = ?")
 		args = append(args, *opts.IsDone)
 	}
 	if opts.Starred != nil {
-		conditions = append(conditions, "starred = ?")
+		conditions = append(conditions, prefix+"starred = ?")
 		args = append(args, *opts.Starred)
 	}
 	if opts.Priority != nil {
-		conditions = append(conditions, "priority = ?")
+		conditions = append(conditions, prefix+"priority = ?")
 		args = append(args, *opts.Priority)
 	}
+	if opts.Label != "" {
+		conditions = append(conditions, prefix+"label = ?")
+		args = append(args, opts.Label)
+	}
+	if opts.Tag != "" {
+		conditions = append(conditions, tagCondition(prefix))
+		args = append(args, tagPattern(opts.Tag))
+	}
+	if opts.Unfiled {
+		conditions = append(conditions, "("+prefix+"folder_id IS NULL OR "+prefix+"folder_id NOT IN (SELECT id FROM folders))")
+	}
+	if opts.DueBefore != nil {
+		conditions = append(conditions, "substr("+prefix+"due_date, 1, 19) <= ?")
+		args = append(args, opts.DueBefore.Format(storedTimeLayout))
+	}
+	if opts.DueAfter != nil {
+		conditions = append(conditions, "substr("+prefix+"due_date, 1, 19) >= ?")
+		args = append(args, opts.DueAfter.Format(storedTimeLayout))
+	}
+	if opts.NoDueDate {
+		conditions = append(conditions, prefix+"due_date IS NULL")
+	}
+	if opts.UpdatedSince != nil {
+		conditions = append(conditions, "substr("+prefix+"updated_at, 1, 19) >= ?")
+		args = append(args, opts.UpdatedSince.Format(storedTimeLayout))
+	}
+	if opts.UpdatedUntil != nil {
+		conditions = append(conditions, "substr("+prefix+"updated_at, 1, 19) <= ?")
+		args = append(args, opts.UpdatedUntil.Format(storedTimeLayout))
+	}
+	if opts.CreatedSince != nil {
+		conditions = append(conditions, "substr("+prefix+"created_at, 1, 19) >= ?")
+		args = append(args, opts.CreatedSince.Format(storedTimeLayout))
+	}
+	if opts.CreatedUntil != nil {
+		conditions = append(conditions, "substr("+prefix+"created_at, 1, 19) <= ?")
+		args = append(args, opts.CreatedUntil.Format(storedTimeLayout))
+	}
+
+	return conditions, args
+}
+
+// tagCondition matches a whole tag in the tags column, treating commas
+// and spaces as separators and ignoring a leading '#'.
+func tagCondition(prefix string) string {
+	return "REPLACE(',' || REPLACE(LOWER(" + prefix + "tags), ' ', ',') || ',', ',#', ',') LIKE ? ESCAPE '\\'"
+}
+
+// tagPattern builds the LIKE argument for tagCondition.
+func tagPattern(tag string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return "%," + escaper.Replace(models.NormalizeTag(tag)) + ",%"
+}
+
+// ListTagStrings returns the raw tags column of every tagged note
+func (r *NoteRepository) ListTagStrings(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT tags FROM notes WHERE tags != ''")
+	if err != nil {
+		return nil, fmt.Errorf("list tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			return nil, fmt.Errorf("scan tags: %w", err)
+		}
+		tags = append(tags, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list tags: %w", err)
+	}
+	return tags, nil
+}
+
+// UpdateTags rewrites the tags of several notes in one transaction.
+func (r *NoteRepository) UpdateTags(ctx context.Context, tags map[int64]string) (err error) {
+	defer database.MarkBusy(&err)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for id, t := range tags {
+		if _, err := tx.ExecContext(ctx, "UPDATE notes SET tags = ?, updated_at = ? WHERE id = ?", t, now, id); err != nil {
+			return fmt.Errorf("update tags: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SetDone marks several todos done or open in one transaction. Locked
+// notes and notes already in that state are skipped; it returns how many
+// notes changed.
+func (r *NoteRepository) SetDone(ctx context.Context, ids []int64, done bool) (int, error) {
+	return r.setFlag(ctx, "is_done", done, ids, "is_todo = 1 AND is_locked = 0")
+}
+
+// SetStarred stars or unstars several notes in one transaction and
+// returns how many notes changed.
+func (r *NoteRepository) SetStarred(ctx context.Context, ids []int64, starred bool) (int, error) {
+	return r.setFlag(ctx, "starred", starred, ids, "")
+}
+
+// SwapPositions swaps the manual order of two notes in one transaction.
+// It leaves updated_at alone, since reordering does not change a note.
+func (r *NoteRepository) SwapPositions(ctx context.Context, id, otherID int64) (err error) {
+	defer database.MarkBusy(&err)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var pos, otherPos int
+	if err := tx.QueryRowContext(ctx, "SELECT position FROM notes WHERE id = ?", id).Scan(&pos); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("get position: %w", err)
+	}
+	if err := tx.QueryRowContext(ctx, "SELECT position FROM notes WHERE id = ?", otherID).Scan(&otherPos); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("get position: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE notes SET position = ? WHERE id = ?", otherPos, id); err != nil {
+		return fmt.Errorf("update position: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE notes SET position = ? WHERE id = ?", pos, otherID); err != nil {
+		return fmt.Errorf("update position: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// setFlag sets a boolean column on the notes in ids that match where and
+// do not already hold value. The column is interpolated, so callers pass
+// only literals.
+func (r *NoteRepository) setFlag(ctx context.Context, column string, value bool, ids []int64, where string) (n int, err error) {
+	defer database.MarkBusy(&err)
+
+	query := fmt.Sprintf("UPDATE notes SET %s = ?, updated_at = ? WHERE id = ? AND %s != ?", column, column)
+	if where != "" {
+		query += " AND " + where
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	changed := 0
+	for _, id := range ids {
+		result, err := tx.ExecContext(ctx, query, value, now, id, value)
+		if err != nil {
+			return 0, fmt.Errorf("update %s: %w", column, err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("get rows affected: %w", err)
+		}
+		changed += int(n)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return changed, nil
+}
+
+// List retrieves notes based on options
+func (r *NoteRepository) List(ctx context.Context, opts models.ListOptions) ([]*models.Note, error) {
+	conditions, args := listConditions(opts, "")
 
 	query := `
-		SELECT id, title, content, folder_id, template_id, is_todo, is_done, priority, due_date, tags, starred, created_at, updated_at
+		SELECT id, title, content, folder_id, template_id, is_todo, is_done, priority, due_date, tags, starred, is_locked, label, position, is_encrypted, created_at, updated_at
 		FROM notes
 	`
 
@@ -201,16 +688,27 @@ func (r *NoteRepository) List(ctx context.Context, opts models.ListOptions) ([]*
 		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	// Order by
+	// Order by; the column is interpolated, so only known columns pass
 	orderBy := opts.OrderBy
 	if orderBy == "" {
 		orderBy = "created_at"
 	}
+	if !models.IsNoteOrderColumn(orderBy) {
+		return nil, fmt.Errorf("%w: cannot order notes by %q", models.ErrValidation, orderBy)
+	}
 	orderDir := "ASC"
 	if opts.OrderDesc {
 		orderDir = "DESC"
 	}
-	query += fmt.Sprintf(" ORDER BY %s %s", orderBy, orderDir)
+	switch orderBy {
+	case "title":
+		query += fmt.Sprintf(" ORDER BY title COLLATE NOCASE %s", orderDir)
+	case "due_date":
+		// Notes without a due date go last in either direction
+		query += fmt.Sprintf(" ORDER BY due_date IS NULL, due_date %s", orderDir)
+	default:
+		query += fmt.Sprintf(" ORDER BY %s %s", orderBy, orderDir)
+	}
 
 	// Limit and offset
 	if opts.Limit > 0 {
@@ -241,6 +739,10 @@ func (r *NoteRepository) List(ctx context.Context, opts models.ListOptions) ([]*
 			&note.DueDate,
 			&note.Tags,
 			&note.Starred,
+			&note.Locked,
+			&note.Label,
+			&note.Position,
+			&note.Encrypted,
 			&note.CreatedAt,
 			&note.UpdatedAt,
 		)
@@ -249,31 +751,19 @@ func (r *NoteRepository) List(ctx context.Context, opts models.ListOptions) ([]*
 		}
 		notes = append(notes, &note)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list notes: %w", err)
+	}
+	if err := r.loadContent(notes...); err != nil {
+		return nil, err
+	}
 
 	return notes, nil
 }
 
 // Count returns the total number of notes matching the options
 func (r *NoteRepository) Count(ctx context.Context, opts models.ListOptions) (int, error) {
-	var conditions []string
-	var args []interface{}
-
-	if opts.FolderID != nil {
-		conditions = append(conditions, "folder_id = ?")
-		args = append(args, *opts.FolderID)
-	}
-	if opts.IsTodo != nil {
-		conditions = append(conditions, "is_todo = ?")
-		args = append(args, *opts.IsTodo)
-	}
-	if opts.IsDone != nil {
-		conditions = append(conditions, "is_done = ?")
-		args = append(args, *opts.IsDone)
-	}
-	if opts.Starred != nil {
-		conditions = append(conditions, "starred = ?")
-		args = append(args, *opts.Starred)
-	}
+	conditions, args := listConditions(opts, "")
 
 	query := "SELECT COUNT(*) FROM notes"
 	if len(conditions) > 0 {
@@ -319,6 +809,112 @@ func (r *NoteRepository) GetStarred(ctx context.Context) ([]*models.Note, error)
 	})
 }
 
+// GetUnfiled retrieves notes outside any folder: those without one and
+// those whose folder_id points at a folder that no longer exists
+func (r *NoteRepository) GetUnfiled(ctx context.Context) ([]*models.Note, error) {
+	return r.List(ctx, models.ListOptions{
+		Unfiled:   true,
+		OrderBy:   "updated_at",
+		OrderDesc: true,
+	})
+}
+
+// ClearDanglingFolders sets folder_id to NULL on every note whose folder no
+// longer exists and returns how many notes were changed
+func (r *NoteRepository) ClearDanglingFolders(ctx context.Context) (n int, err error) {
+	defer database.MarkBusy(&err)
+
+	query := `
+		UPDATE notes SET folder_id = NULL
+		WHERE folder_id IS NOT NULL AND folder_id NOT IN (SELECT id FROM folders)
+	`
+
+	result, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("clear dangling folders: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get rows affected: %w", err)
+	}
+	return int(rows), nil
+}
+
+// ExportContent writes every note's content as stored in SQLite to files
+// and returns how many notes were written
+func (r *NoteRepository) ExportContent(ctx context.Context, files *ContentFiles) (int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, content, updated_at FROM notes`)
+	if err != nil {
+		return 0, fmt.Errorf("list note content: %w", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var id int64
+		var content string
+		var updatedAt time.Time
+		if err := rows.Scan(&id, &content, &updatedAt); err != nil {
+			return count, fmt.Errorf("scan note content: %w", err)
+		}
+		if err := files.Write(id, content, updatedAt); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+// ImportContent copies the content of every note file into SQLite and
+// returns how many notes changed. Notes without a file are left alone.
+func (r *NoteRepository) ImportContent(ctx context.Context, files *ContentFiles) (n int, err error) {
+	defer database.MarkBusy(&err)
+
+	rows, err := r.db.QueryContext(ctx, `SELECT id, content FROM notes`)
+	if err != nil {
+		return 0, fmt.Errorf("list note content: %w", err)
+	}
+
+	changed := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var content string
+		if err := rows.Scan(&id, &content); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan note content: %w", err)
+		}
+		file, ok, err := files.Read(id)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if ok && file != content {
+			changed[id] = file
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("list note content: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for id, content := range changed {
+		if _, err := tx.ExecContext(ctx, "UPDATE notes SET content = ? WHERE id = ?", content, id); err != nil {
+			return 0, fmt.Errorf("import note content: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return len(changed), nil
+}
+
 // GetRecent retrieves recent notes
 func (r *NoteRepository) GetRecent(ctx context.Context, limit int) ([]*models.Note, error) {
 	return r.List(ctx, models.ListOptions{
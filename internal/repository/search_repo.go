@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/tranducquang/kiroku/internal/database"
 	"github.com/tranducquang/kiroku/internal/models"
@@ -10,12 +11,14 @@ import (
 
 // SearchRepository handles full-text search operations
 type SearchRepository struct {
-	db *database.DB
+	db    *database.DB
+	files *ContentFiles
 }
 
-// NewSearchRepository creates a new search repository
-func NewSearchRepository(db *database.DB) *SearchRepository {
-	return &SearchRepository{db: db}
+// NewSearchRepository creates a new search repository. files is the note
+// content store, or nil when content lives in SQLite.
+func NewSearchRepository(db *database.DB, files *ContentFiles) *SearchRepository {
+	return &SearchRepository{db: db, files: files}
 }
 
 // SearchResult represents a search result
@@ -30,16 +33,23 @@ func (r *SearchRepository) Search(ctx context.Context, query string, opts models
 	sqlQuery := `
 		SELECT 
 			n.id, n.title, n.content, n.folder_id, n.template_id, 
-			n.is_todo, n.is_done, n.priority, n.due_date, n.tags, n.starred,
-			n.created_at, n.updated_at,
+			n.is_todo, n.is_done, n.priority, n.due_date, n.tags, n.starred, n.is_locked, n.label,
+			n.position, n.is_encrypted, n.created_at, n.updated_at,
 			snippet(notes_fts, 0, '<mark>', '</mark>', '...', 32) as snippet,
 			rank
 		FROM notes_fts
 		JOIN notes n ON notes_fts.rowid = n.id
 		WHERE notes_fts MATCH ?
-		ORDER BY rank
 	`
 
+	args := []interface{}{query}
+	conditions, filterArgs := listConditions(opts, "n.")
+	if len(conditions) > 0 {
+		sqlQuery += " AND " + strings.Join(conditions, " AND ")
+		args = append(args, filterArgs...)
+	}
+	sqlQuery += " ORDER BY rank"
+
 	if opts.Limit > 0 {
 		sqlQuery += fmt.Sprintf(" LIMIT %d", opts.Limit)
 	}
@@ -47,7 +57,7 @@ func (r *SearchRepository) Search(ctx context.Context, query string, opts models
 		sqlQuery += fmt.Sprintf(" OFFSET %d", opts.Offset)
 	}
 
-	rows, err := r.db.QueryContext(ctx, sqlQuery, query)
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("search notes: %w", err)
 	}
@@ -68,6 +78,10 @@ func (r *SearchRepository) Search(ctx context.Context, query string, opts models
 			&result.Note.DueDate,
 			&result.Note.Tags,
 			&result.Note.Starred,
+			&result.Note.Locked,
+			&result.Note.Label,
+			&result.Note.Position,
+			&result.Note.Encrypted,
 			&result.Note.CreatedAt,
 			&result.Note.UpdatedAt,
 			&result.Snippet,
@@ -78,6 +92,11 @@ func (r *SearchRepository) Search(ctx context.Context, query string, opts models
 		}
 		results = append(results, result)
 	}
+	for i := range results {
+		if err := loadContent(r.files, &results[i].Note); err != nil {
+			return nil, err
+		}
+	}
 
 	return results, nil
 }
@@ -85,7 +104,7 @@ func (r *SearchRepository) Search(ctx context.Context, query string, opts models
 // SearchByTag searches notes by tag
 func (r *SearchRepository) SearchByTag(ctx context.Context, tag string, opts models.ListOptions) ([]*models.Note, error) {
 	query := `
-		SELECT id, title, content, folder_id, template_id, is_todo, is_done, priority, due_date, tags, starred, created_at, updated_at
+		SELECT id, title, content, folder_id, template_id, is_todo, is_done, priority, due_date, tags, starred, is_locked, label, position, is_encrypted, created_at, updated_at
 		FROM notes
 		WHERE tags LIKE ?
 		ORDER BY updated_at DESC
@@ -120,6 +139,10 @@ func (r *SearchRepository) SearchByTag(ctx context.Context, tag string, opts mod
 			&note.DueDate,
 			&note.Tags,
 			&note.Starred,
+			&note.Locked,
+			&note.Label,
+			&note.Position,
+			&note.Encrypted,
 			&note.CreatedAt,
 			&note.UpdatedAt,
 		)
@@ -128,6 +151,9 @@ func (r *SearchRepository) SearchByTag(ctx context.Context, tag string, opts mod
 		}
 		notes = append(notes, &note)
 	}
+	if err := loadContent(r.files, notes...); err != nil {
+		return nil, err
+	}
 
 	return notes, nil
 }
@@ -22,14 +22,16 @@ func NewTemplateRepository(db *database.DB) *TemplateRepository {
 }
 
 // Create creates a new template
-func (r *TemplateRepository) Create(ctx context.Context, template *models.Template) error {
+func (r *TemplateRepository) Create(ctx context.Context, template *models.Template) (err error) {
+	defer database.MarkBusy(&err)
+
 	if err := template.Validate(); err != nil {
 		return err
 	}
 
 	query := `
-		INSERT INTO templates (name, content, description, is_default, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO templates (name, content, description, type, icon, is_default, auto_star, default_priority, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	now := time.Now()
@@ -40,7 +42,11 @@ func (r *TemplateRepository) Create(ctx context.Context, template *models.Templa
 		template.Name,
 		template.Content,
 		template.Description,
+		template.Type,
+		template.Icon,
 		template.IsDefault,
+		template.AutoStar,
+		template.DefaultPriority,
 		template.CreatedAt,
 		template.UpdatedAt,
 	)
@@ -60,7 +66,7 @@ func (r *TemplateRepository) Create(ctx context.Context, template *models.Templa
 // GetByID retrieves a template by ID
 func (r *TemplateRepository) GetByID(ctx context.Context, id int64) (*models.Template, error) {
 	query := `
-		SELECT id, name, content, description, is_default, created_at, updated_at
+		SELECT id, name, content, description, type, icon, is_default, auto_star, default_priority, created_at, updated_at
 		FROM templates
 		WHERE id = ?
 	`
@@ -71,7 +77,11 @@ func (r *TemplateRepository) GetByID(ctx context.Context, id int64) (*models.Tem
 		&template.Name,
 		&template.Content,
 		&template.Description,
+		&template.Type,
+		&template.Icon,
 		&template.IsDefault,
+		&template.AutoStar,
+		&template.DefaultPriority,
 		&template.CreatedAt,
 		&template.UpdatedAt,
 	)
@@ -88,7 +98,7 @@ func (r *TemplateRepository) GetByID(ctx context.Context, id int64) (*models.Tem
 // GetByName retrieves a template by name
 func (r *TemplateRepository) GetByName(ctx context.Context, name string) (*models.Template, error) {
 	query := `
-		SELECT id, name, content, description, is_default, created_at, updated_at
+		SELECT id, name, content, description, type, icon, is_default, auto_star, default_priority, created_at, updated_at
 		FROM templates
 		WHERE name = ?
 	`
@@ -99,7 +109,11 @@ func (r *TemplateRepository) GetByName(ctx context.Context, name string) (*model
 		&template.Name,
 		&template.Content,
 		&template.Description,
+		&template.Type,
+		&template.Icon,
 		&template.IsDefault,
+		&template.AutoStar,
+		&template.DefaultPriority,
 		&template.CreatedAt,
 		&template.UpdatedAt,
 	)
@@ -114,14 +128,16 @@ func (r *TemplateRepository) GetByName(ctx context.Context, name string) (*model
 }
 
 // Update updates an existing template
-func (r *TemplateRepository) Update(ctx context.Context, template *models.Template) error {
+func (r *TemplateRepository) Update(ctx context.Context, template *models.Template) (err error) {
+	defer database.MarkBusy(&err)
+
 	if err := template.Validate(); err != nil {
 		return err
 	}
 
 	query := `
 		UPDATE templates
-		SET name = ?, content = ?, description = ?, is_default = ?, updated_at = ?
+		SET name = ?, content = ?, description = ?, type = ?, icon = ?, is_default = ?, auto_star = ?, default_priority = ?, updated_at = ?
 		WHERE id = ?
 	`
 
@@ -131,7 +147,11 @@ func (r *TemplateRepository) Update(ctx context.Context, template *models.Templa
 		template.Name,
 		template.Content,
 		template.Description,
+		template.Type,
+		template.Icon,
 		template.IsDefault,
+		template.AutoStar,
+		template.DefaultPriority,
 		template.UpdatedAt,
 		template.ID,
 	)
@@ -151,7 +171,9 @@ func (r *TemplateRepository) Update(ctx context.Context, template *models.Templa
 }
 
 // Delete deletes a template by ID
-func (r *TemplateRepository) Delete(ctx context.Context, id int64) error {
+func (r *TemplateRepository) Delete(ctx context.Context, id int64) (err error) {
+	defer database.MarkBusy(&err)
+
 	query := `DELETE FROM templates WHERE id = ?`
 
 	result, err := r.db.ExecContext(ctx, query, id)
@@ -173,7 +195,7 @@ func (r *TemplateRepository) Delete(ctx context.Context, id int64) error {
 // List retrieves all templates
 func (r *TemplateRepository) List(ctx context.Context) ([]models.Template, error) {
 	query := `
-		SELECT id, name, content, description, is_default, created_at, updated_at
+		SELECT id, name, content, description, type, icon, is_default, auto_star, default_priority, created_at, updated_at
 		FROM templates
 		ORDER BY is_default DESC, name ASC
 	`
@@ -192,7 +214,11 @@ func (r *TemplateRepository) List(ctx context.Context) ([]models.Template, error
 			&template.Name,
 			&template.Content,
 			&template.Description,
+			&template.Type,
+			&template.Icon,
 			&template.IsDefault,
+			&template.AutoStar,
+			&template.DefaultPriority,
 			&template.CreatedAt,
 			&template.UpdatedAt,
 		)
@@ -208,7 +234,7 @@ func (r *TemplateRepository) List(ctx context.Context) ([]models.Template, error
 // GetDefault retrieves the default template
 func (r *TemplateRepository) GetDefault(ctx context.Context) (*models.Template, error) {
 	query := `
-		SELECT id, name, content, description, is_default, created_at, updated_at
+		SELECT id, name, content, description, type, icon, is_default, auto_star, default_priority, created_at, updated_at
 		FROM templates
 		WHERE is_default = 1
 		LIMIT 1
@@ -220,7 +246,11 @@ func (r *TemplateRepository) GetDefault(ctx context.Context) (*models.Template,
 		&template.Name,
 		&template.Content,
 		&template.Description,
+		&template.Type,
+		&template.Icon,
 		&template.IsDefault,
+		&template.AutoStar,
+		&template.DefaultPriority,
 		&template.CreatedAt,
 		&template.UpdatedAt,
 	)
@@ -0,0 +1,106 @@
+// Package secret seals note content with a passphrase.
+//
+// Sealed text is ASCII so it can live in the content column and content
+// files like any other note: a version prefix followed by the base64 of
+// the salt, the nonce and the AES-256-GCM ciphertext. The key is derived
+// from the passphrase with PBKDF2-SHA256 and a fresh salt on every seal.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix marks sealed text and the format version it was written in
+const prefix = "kiroku-sealed:v1:"
+
+const (
+	saltSize   = 16
+	keySize    = 32
+	iterations = 600_000
+)
+
+// ErrWrongPassphrase is returned when sealed text cannot be opened with
+// the passphrase, or has been tampered with
+var ErrWrongPassphrase = errors.New("wrong passphrase")
+
+// ErrEmptyPassphrase is returned when sealing with an empty passphrase
+var ErrEmptyPassphrase = errors.New("passphrase cannot be empty")
+
+// IsSealed reports whether text was produced by Seal
+func IsSealed(text string) bool {
+	return strings.HasPrefix(text, prefix)
+}
+
+// Seal encrypts plaintext with a key derived from passphrase
+func Seal(plaintext, passphrase string) (string, error) {
+	if passphrase == "" {
+		return "", ErrEmptyPassphrase
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+
+	data := append(salt, nonce...)
+	data = gcm.Seal(data, nonce, []byte(plaintext), nil)
+	return prefix + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// Open decrypts text sealed by Seal
+func Open(sealed, passphrase string) (string, error) {
+	encoded, ok := strings.CutPrefix(sealed, prefix)
+	if !ok {
+		return "", fmt.Errorf("not sealed text")
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("decode sealed text: %w", err)
+	}
+	if len(data) < saltSize {
+		return "", fmt.Errorf("sealed text too short")
+	}
+
+	gcm, err := newGCM(passphrase, data[:saltSize])
+	if err != nil {
+		return "", err
+	}
+	data = data[saltSize:]
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("sealed text too short")
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", ErrWrongPassphrase
+	}
+	return string(plaintext), nil
+}
+
+// newGCM derives the key for passphrase and salt and wraps it in AES-GCM
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+func TestNoteService_AppendContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		text    string
+		want    string
+	}{
+		{name: "empty content", content: "", text: "first", want: "first"},
+		{name: "no trailing newline", content: "line", text: "next", want: "line\nnext"},
+		{name: "trailing newline", content: "line\n", text: "next", want: "line\nnext"},
+		{name: "trailing blank lines collapse", content: "line\n\n\n", text: "next", want: "line\nnext"},
+		{name: "text newline is dropped", content: "line", text: "next\n", want: "line\nnext"},
+		{name: "multi-line text", content: "line", text: "a\nb", want: "line\na\nb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			note := s.createNote(t, &models.Note{Title: "log", Content: tt.content})
+			before := note.UpdatedAt
+
+			if err := s.notes.AppendContent(context.Background(), note.ID, tt.text); err != nil {
+				t.Fatalf("AppendContent() error = %v", err)
+			}
+			got := s.getNote(t, note.ID)
+			if got.Content != tt.want {
+				t.Errorf("content = %q, want %q", got.Content, tt.want)
+			}
+			if got.UpdatedAt.Before(before) {
+				t.Errorf("updated_at went back from %v to %v", before, got.UpdatedAt)
+			}
+		})
+	}
+}
+
+func TestNoteService_AppendContent_Refused(t *testing.T) {
+	tests := []struct {
+		name    string
+		note    *models.Note
+		text    string
+		wantErr error
+	}{
+		{name: "blank text", note: &models.Note{Title: "a"}, text: " \n"},
+		{name: "locked note", note: &models.Note{Title: "a", Locked: true}, text: "x", wantErr: models.ErrLocked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			note := s.createNote(t, tt.note)
+
+			err := s.notes.AppendContent(context.Background(), note.ID, tt.text)
+			if err == nil {
+				t.Fatal("AppendContent() succeeded, want an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("AppendContent() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNoteService_AppendContent_Concurrent(t *testing.T) {
+	s := newTestServices(t)
+	note := s.createNote(t, &models.Note{Title: "log"})
+
+	// Appends run through the single connection one at a time, so none of
+	// them is lost
+	const writers = 10
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.notes.AppendContent(context.Background(), note.ID, "line"); err != nil {
+				t.Errorf("AppendContent() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	got := s.getNote(t, note.ID)
+	if lines := len(splitLines(got.Content)); lines != writers {
+		t.Errorf("got %d lines, want %d", lines, writers)
+	}
+}
+
+// splitLines splits content into its non-empty lines
+func splitLines(content string) []string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/tranducquang/kiroku/internal/database"
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+func TestNoteService_WritesReportBusy(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(ctx context.Context, s *testServices, note *models.Note) error
+	}{
+		{"create", func(ctx context.Context, s *testServices, note *models.Note) error {
+			return s.notes.Create(ctx, &models.Note{Title: "blocked"})
+		}},
+		{"update", func(ctx context.Context, s *testServices, note *models.Note) error {
+			note.Content = "blocked"
+			return s.notes.Update(ctx, note)
+		}},
+		{"delete", func(ctx context.Context, s *testServices, note *models.Note) error {
+			return s.notes.Delete(ctx, note.ID)
+		}},
+		{"create folder", func(ctx context.Context, s *testServices, note *models.Note) error {
+			return s.folders.Create(ctx, &models.Folder{Name: "blocked"})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			note := s.createNote(t, &models.Note{Title: "existing"})
+			// Fail fast instead of waiting out the configured timeout
+			if _, err := s.db.Exec(`PRAGMA busy_timeout = 50`); err != nil {
+				t.Fatalf("set busy timeout: %v", err)
+			}
+
+			// A second connection, as another kiroku process would hold
+			other, err := sql.Open("sqlite", s.path+"?_txlock=immediate")
+			if err != nil {
+				t.Fatalf("open second connection: %v", err)
+			}
+			defer other.Close()
+			tx, err := other.Begin()
+			if err != nil {
+				t.Fatalf("begin write transaction: %v", err)
+			}
+			defer tx.Rollback()
+
+			if err := tt.write(context.Background(), s, note); !errors.Is(err, database.ErrBusy) {
+				t.Errorf("error = %v, want ErrBusy", err)
+			}
+		})
+	}
+}
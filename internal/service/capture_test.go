@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+func TestNoteService_Capture(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	today := time.Now().Format(DailyNoteTitleFormat)
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "creates the daily note", text: "first thought", want: "first thought"},
+		{name: "appends to the same note", text: "second thought", want: "second thought"},
+		{name: "folds whitespace", text: "  spread \n over\tlines ", want: "spread over lines"},
+	}
+	var dailyID int64
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note, err := s.notes.Capture(ctx, tt.text)
+			if err != nil {
+				t.Fatalf("Capture() error = %v", err)
+			}
+			if note.Title != today {
+				t.Errorf("title = %q, want %q", note.Title, today)
+			}
+			if dailyID == 0 {
+				dailyID = note.ID
+			} else if note.ID != dailyID {
+				t.Errorf("captured into note %d, want the daily note %d", note.ID, dailyID)
+			}
+			lines := strings.Split(note.Content, "\n")
+			if last := lines[len(lines)-1]; !strings.HasPrefix(last, "- ") || !strings.HasSuffix(last, " "+tt.want) {
+				t.Errorf("last line = %q, want a bullet ending in %q", last, tt.want)
+			}
+		})
+	}
+
+	notes, err := s.notes.List(ctx, models.ListOptions{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(notes) != 1 {
+		t.Errorf("got %d notes, want a single daily note", len(notes))
+	}
+}
+
+func TestNoteService_Capture_EmptyText(t *testing.T) {
+	s := newTestServices(t)
+	if _, err := s.notes.Capture(context.Background(), " \n\t"); err == nil {
+		t.Error("Capture() of blank text succeeded, want an error")
+	}
+}
+
+func TestNoteService_CreateOrGetByTitle_TitleTakenMeanwhile(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	existing := s.createNote(t, &models.Note{Title: "2024-03-01", Content: "kept"})
+
+	// A second writer that looked the title up before it existed must not
+	// create a duplicate
+	created, err := s.notes.noteRepo.CreateIfTitleMissing(ctx, &models.Note{Title: "2024-03-01"})
+	if err != nil {
+		t.Fatalf("CreateIfTitleMissing() error = %v", err)
+	}
+	if created {
+		t.Error("CreateIfTitleMissing() created a second note with a taken title")
+	}
+
+	got, err := s.notes.CreateOrGetByTitle(ctx, &models.Note{Title: "2024-03-01"})
+	if err != nil {
+		t.Fatalf("CreateOrGetByTitle() error = %v", err)
+	}
+	if got.ID != existing.ID || got.Content != "kept" {
+		t.Errorf("CreateOrGetByTitle() = note %d %q, want note %d %q", got.ID, got.Content, existing.ID, "kept")
+	}
+}
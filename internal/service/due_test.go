@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/repository"
+)
+
+func TestNoteService_SetDueDate(t *testing.T) {
+	first := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	second := time.Date(2026, 4, 15, 17, 30, 0, 0, time.Local)
+	tests := []struct {
+		name    string
+		initial *time.Time
+		due     *time.Time
+	}{
+		{"set", nil, &first},
+		{"change", &first, &second},
+		{"clear", &first, nil},
+		{"clear unset", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			note := s.createNote(t, &models.Note{Title: "todo", IsTodo: true, DueDate: tt.initial})
+
+			if err := s.notes.SetDueDate(context.Background(), note.ID, tt.due); err != nil {
+				t.Fatalf("SetDueDate() error = %v", err)
+			}
+
+			got := s.getNote(t, note.ID).DueDate
+			switch {
+			case tt.due == nil && got != nil:
+				t.Errorf("due date = %v, want none", got)
+			case tt.due != nil && (got == nil || !got.Equal(*tt.due)):
+				t.Errorf("due date = %v, want %v", got, tt.due)
+			}
+		})
+	}
+}
+
+func TestNoteService_SetDueDate_NotFound(t *testing.T) {
+	s := newTestServices(t)
+	due := time.Now()
+	if err := s.notes.SetDueDate(context.Background(), 999, &due); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("SetDueDate() error = %v, want ErrNotFound", err)
+	}
+}
@@ -1,14 +1,30 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/tranducquang/kiroku/internal/config"
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/platform"
 )
 
+const (
+	// UntitledNoteTitle is used when an edited note yields no title at all.
+	UntitledNoteTitle = "Untitled"
+	// DerivedTitleMaxLength caps titles derived from a note's first line.
+	DerivedTitleMaxLength = 60
+)
+
+// ErrNothingWritten is returned by CreateNote when the editor is closed
+// without changing the seeded file or leaves it blank.
+var ErrNothingWritten = errors.New("nothing written")
+
 // EditorService handles external editor integration
 type EditorService struct {
 	cfg *config.Config
@@ -19,8 +35,19 @@ func NewEditorService(cfg *config.Config) *EditorService {
 	return &EditorService{cfg: cfg}
 }
 
-// EditNote opens a note in the external editor and returns the updated content
+// EditNote opens a note in the configured external editor and returns the updated content
 func (s *EditorService) EditNote(title, content string) (newTitle, newContent string, err error) {
+	return s.EditNoteWith("", title, content)
+}
+
+// EditNoteWith opens a note in the given editor, falling back to the configured
+// editor when editor is empty. The editor may include arguments, e.g. "code --wait".
+func (s *EditorService) EditNoteWith(editor, title, content string) (newTitle, newContent string, err error) {
+	name, args, err := s.resolveEditor(editor)
+	if err != nil {
+		return "", "", err
+	}
+
 	// Create temporary file
 	tmpFile, err := os.CreateTemp("", "kiroku-*.md")
 	if err != nil {
@@ -36,7 +63,7 @@ func (s *EditorService) EditNote(title, content string) (newTitle, newContent st
 	tmpFile.Close()
 
 	// Open editor
-	cmd := exec.Command(s.cfg.Editor.Command, append(s.cfg.Editor.Args, tmpFile.Name())...)
+	cmd := exec.Command(name, append(args, tmpFile.Name())...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -51,27 +78,32 @@ func (s *EditorService) EditNote(title, content string) (newTitle, newContent st
 		return "", "", fmt.Errorf("read temp file: %w", err)
 	}
 
-	// Parse title and content
-	lines := strings.SplitN(string(data), "\n", 3)
-	if len(lines) == 0 {
-		return title, content, nil
-	}
+	newTitle, newContent = parseEditedNote(string(data), title)
+	return newTitle, newContent, nil
+}
 
-	// Extract title from first line (remove # prefix)
-	newTitle = strings.TrimPrefix(lines[0], "# ")
-	newTitle = strings.TrimSpace(newTitle)
-	if newTitle == "" {
-		newTitle = title
+// OpenExternal writes a note to a file and opens it in the system default
+// app for markdown, without waiting. The file is named by note ID in a
+// fixed temp directory, so opening the note again reuses it, and carries
+// the ID in frontmatter so changes can be imported back. Encrypted notes
+// are refused, since the file would hold their plain text.
+func (s *EditorService) OpenExternal(note *models.Note) (path string, err error) {
+	if note.Encrypted {
+		return "", models.ErrEncrypted
 	}
-
-	// Rest is content
-	if len(lines) > 2 {
-		newContent = strings.TrimSpace(lines[2])
-	} else if len(lines) > 1 {
-		newContent = strings.TrimSpace(lines[1])
+	dir := filepath.Join(os.TempDir(), "kiroku-open")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("create open directory: %w", err)
 	}
 
-	return newTitle, newContent, nil
+	path = filepath.Join(dir, strconv.FormatInt(note.ID, 10)+".md")
+	if err := os.WriteFile(path, []byte(exportNoteData(note)), 0600); err != nil {
+		return "", fmt.Errorf("write note file: %w", err)
+	}
+	if err := platform.OpenPath(path); err != nil {
+		return "", err
+	}
+	return path, nil
 }
 
 // PrepareEdit creates temp file and returns the editor command for use with tea.ExecProcess
@@ -107,30 +139,12 @@ func (s *EditorService) ReadEditedContent(tmpFilePath, originalTitle string) (ne
 		return "", "", fmt.Errorf("read temp file: %w", err)
 	}
 
-	// Parse title and content
-	lines := strings.SplitN(string(data), "\n", 3)
-	if len(lines) == 0 {
-		return originalTitle, "", nil
-	}
-
-	// Extract title from first line (remove # prefix)
-	newTitle = strings.TrimPrefix(lines[0], "# ")
-	newTitle = strings.TrimSpace(newTitle)
-	if newTitle == "" {
-		newTitle = originalTitle
-	}
-
-	// Rest is content
-	if len(lines) > 2 {
-		newContent = strings.TrimSpace(lines[2])
-	} else if len(lines) > 1 {
-		newContent = strings.TrimSpace(lines[1])
-	}
-
+	newTitle, newContent = parseEditedNote(string(data), originalTitle)
 	return newTitle, newContent, nil
 }
 
-// CreateNote opens an editor for a new note
+// CreateNote opens an editor for a new note seeded with templateContent.
+// It returns ErrNothingWritten when the user leaves the file as seeded.
 func (s *EditorService) CreateNote(templateContent string) (title, content string, err error) {
 	// Create temporary file
 	tmpFile, err := os.CreateTemp("", "kiroku-*.md")
@@ -140,14 +154,13 @@ func (s *EditorService) CreateNote(templateContent string) (title, content strin
 	defer os.Remove(tmpFile.Name())
 
 	// Write template content
-	if templateContent != "" {
-		if _, err := tmpFile.WriteString(templateContent); err != nil {
-			return "", "", fmt.Errorf("write temp file: %w", err)
-		}
-	} else {
-		if _, err := tmpFile.WriteString("# \n\n"); err != nil {
-			return "", "", fmt.Errorf("write temp file: %w", err)
-		}
+	seed := templateContent
+	if seed == "" {
+		seed = "# \n\n"
+	}
+	if _, err := tmpFile.WriteString(seed); err != nil {
+		tmpFile.Close()
+		return "", "", fmt.Errorf("write temp file: %w", err)
 	}
 	tmpFile.Close()
 
@@ -166,23 +179,113 @@ func (s *EditorService) CreateNote(templateContent string) (title, content strin
 	if err != nil {
 		return "", "", fmt.Errorf("read temp file: %w", err)
 	}
+	if string(data) == seed || strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(string(data)), "#")) == "" {
+		return "", "", ErrNothingWritten
+	}
+
+	title, content = parseEditedNote(string(data), "")
+	return title, content, nil
+}
+
+// parseEditedNote splits an edited file into title and content. The title
+// comes from, in order: a "title:" key in YAML frontmatter, a leading "# "
+// heading, the first non-empty content line, fallbackTitle, and finally
+// UntitledNoteTitle. Frontmatter and the heading are not part of the content;
+// a title derived from the first line leaves that line in the content.
+func parseEditedNote(data, fallbackTitle string) (title, content string) {
+	body := strings.TrimLeft(data, "\n")
+
+	if fm, rest, ok := splitFrontmatter(body); ok {
+		title = frontmatterTitle(fm)
+		body = strings.TrimLeft(rest, "\n")
+	}
 
-	// Parse title and content
-	lines := strings.SplitN(string(data), "\n", 3)
-	if len(lines) == 0 {
-		return "", "", nil
+	first, rest, _ := strings.Cut(body, "\n")
+	if heading := strings.TrimSpace(first); heading == "#" || strings.HasPrefix(heading, "# ") {
+		if title == "" {
+			title = strings.TrimSpace(strings.TrimPrefix(heading, "#"))
+		}
+		body = rest
 	}
 
-	// Extract title from first line
-	title = strings.TrimPrefix(lines[0], "# ")
-	title = strings.TrimSpace(title)
+	content = strings.TrimSpace(body)
+	if title == "" {
+		title = titleFromContent(content)
+	}
+	if title == "" {
+		title = strings.TrimSpace(fallbackTitle)
+	}
+	if title == "" {
+		title = UntitledNoteTitle
+	}
+	return title, content
+}
 
-	// Rest is content
-	if len(lines) > 2 {
-		content = strings.TrimSpace(lines[2])
-	} else if len(lines) > 1 {
-		content = strings.TrimSpace(lines[1])
+// splitFrontmatter separates a leading "---" delimited block from the rest of s.
+func splitFrontmatter(s string) (frontmatter, rest string, ok bool) {
+	if !strings.HasPrefix(s, "---\n") {
+		return "", s, false
+	}
+	frontmatter, rest, ok = strings.Cut(s[len("---\n"):], "\n---")
+	if !ok {
+		return "", s, false
 	}
+	// Drop the remainder of the closing delimiter line
+	_, rest, _ = strings.Cut(rest, "\n")
+	return frontmatter, rest, true
+}
 
-	return title, content, nil
+// frontmatterTitle returns the value of the "title:" key, if any.
+func frontmatterTitle(frontmatter string) string {
+	return frontmatterValue(frontmatter, "title")
+}
+
+// frontmatterValue returns the unquoted value of a top-level key, if any.
+func frontmatterValue(frontmatter, name string) string {
+	for _, line := range strings.Split(frontmatter, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok && strings.TrimSpace(key) == name {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// titleFromContent derives a title from the first non-empty line of content,
+// stripped of markdown markers and truncated at a word boundary.
+func titleFromContent(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(line, "#>-*+ \t"))
+		for _, box := range []string{"[ ]", "[x]", "[X]"} {
+			line = strings.TrimSpace(strings.TrimPrefix(line, box))
+		}
+		if line == "" {
+			continue
+		}
+
+		runes := []rune(line)
+		if len(runes) <= DerivedTitleMaxLength {
+			return line
+		}
+		cut := string(runes[:DerivedTitleMaxLength])
+		if i := strings.LastIndex(cut, " "); i > 0 {
+			cut = cut[:i]
+		}
+		return strings.TrimSpace(cut) + "…"
+	}
+	return ""
+}
+
+// resolveEditor returns the editor binary and arguments to use. An override
+// replaces the configured editor and its arguments; it must exist in PATH.
+func (s *EditorService) resolveEditor(override string) (name string, args []string, err error) {
+	fields := strings.Fields(override)
+	if len(fields) == 0 {
+		return s.cfg.Editor.Command, s.cfg.Editor.Args, nil
+	}
+
+	if _, err := exec.LookPath(fields[0]); err != nil {
+		return "", nil, fmt.Errorf("editor %q not found: %w", fields[0], err)
+	}
+	return fields[0], fields[1:], nil
 }
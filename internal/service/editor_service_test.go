@@ -0,0 +1,38 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseEditedNote(t *testing.T) {
+	long := strings.Repeat("word ", DerivedTitleMaxLength)
+	tests := []struct {
+		name        string
+		data        string
+		fallback    string
+		wantTitle   string
+		wantContent string
+	}{
+		{"frontmatter title", "---\ntitle: \"From FM\"\n---\n# Heading\nbody", "old", "From FM", "body"},
+		{"heading", "# Heading\n\nbody", "old", "Heading", "body"},
+		{"frontmatter without title", "---\ntags: a\n---\n# Heading\nbody", "old", "Heading", "body"},
+		{"first content line", "# \n\n- [ ] buy milk\nmore", "old", "buy milk", "- [ ] buy milk\nmore"},
+		{"first line without heading", "\n\nplain start\nmore", "", "plain start", "plain start\nmore"},
+		{"long first line", long, "", strings.TrimSpace(strings.Repeat("word ", DerivedTitleMaxLength/5)) + "…", strings.TrimSpace(long)},
+		{"fallback title", "# \n\n", "  old  ", "old", ""},
+		{"untitled", "#\n", "", UntitledNoteTitle, ""},
+		{"empty file", "", "", UntitledNoteTitle, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, content := parseEditedNote(tt.data, tt.fallback)
+			if title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", title, tt.wantTitle)
+			}
+			if content != tt.wantContent {
+				t.Errorf("content = %q, want %q", content, tt.wantContent)
+			}
+		})
+	}
+}
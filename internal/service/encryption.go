@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/secret"
+)
+
+// Encrypt seals a note's content with passphrase. Title, tags and the
+// other fields stay readable so the note can still be listed and found.
+func (s *NoteService) Encrypt(ctx context.Context, id int64, passphrase string) error {
+	note, err := s.noteRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get note: %w", err)
+	}
+	if note.Locked {
+		return models.ErrLocked
+	}
+	if note.Encrypted {
+		return fmt.Errorf("%w: note is already encrypted", models.ErrValidation)
+	}
+
+	sealed, err := sealContent(note.Content, passphrase)
+	if err != nil {
+		return err
+	}
+	note.Content = sealed
+	note.Encrypted = true
+	if err := note.CheckContentSize(s.maxContent); err != nil {
+		return err
+	}
+	return s.noteRepo.Update(ctx, note)
+}
+
+// Decrypt removes the encryption from a note, storing its content as
+// plain text again.
+func (s *NoteService) Decrypt(ctx context.Context, id int64, passphrase string) error {
+	note, err := s.noteRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get note: %w", err)
+	}
+	if note.Locked {
+		return models.ErrLocked
+	}
+
+	content, err := s.Reveal(note, passphrase)
+	if err != nil {
+		return err
+	}
+	note.Content = content
+	note.Encrypted = false
+	return s.noteRepo.Update(ctx, note)
+}
+
+// Reveal returns the plain content of an encrypted note without changing
+// what is stored. A note that is not encrypted returns its content as is.
+func (s *NoteService) Reveal(note *models.Note, passphrase string) (string, error) {
+	if !note.Encrypted {
+		return note.Content, nil
+	}
+	content, err := secret.Open(note.Content, passphrase)
+	if errors.Is(err, secret.ErrWrongPassphrase) {
+		return "", fmt.Errorf("%w: %w", models.ErrValidation, err)
+	}
+	if err != nil {
+		return "", fmt.Errorf("decrypt note: %w", err)
+	}
+	return content, nil
+}
+
+// UpdateEncrypted saves an edit of an encrypted note. note holds the plain
+// content, which is sealed with passphrase before it is stored; note is
+// left holding the sealed content.
+func (s *NoteService) UpdateEncrypted(ctx context.Context, note *models.Note, passphrase string) error {
+	if !note.Encrypted {
+		return s.Update(ctx, note)
+	}
+	if err := note.CheckContentSize(s.maxContent); err != nil {
+		return err
+	}
+
+	sealed, err := sealContent(note.Content, passphrase)
+	if err != nil {
+		return err
+	}
+	note.Content = sealed
+	return s.Update(ctx, note)
+}
+
+// sealContent seals content, reporting an empty passphrase as invalid input
+func sealContent(content, passphrase string) (string, error) {
+	sealed, err := secret.Seal(content, passphrase)
+	if errors.Is(err, secret.ErrEmptyPassphrase) {
+		return "", fmt.Errorf("%w: %w", models.ErrValidation, err)
+	}
+	if err != nil {
+		return "", fmt.Errorf("encrypt note: %w", err)
+	}
+	return sealed, nil
+}
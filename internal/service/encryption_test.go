@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+// secretWord appears only in the content of the encrypted note
+const secretWord = "plutonium"
+
+// createEncrypted creates an encrypted note titled "vault" sealed with
+// passphrase "hunter2"
+func (s *testServices) createEncrypted(t *testing.T) *models.Note {
+	t.Helper()
+	note := s.createNote(t, &models.Note{Title: "vault", Content: "# Vault\n\nthe " + secretWord + " is hidden", Tags: "private"})
+	if err := s.notes.Encrypt(context.Background(), note.ID, "hunter2"); err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	return s.getNote(t, note.ID)
+}
+
+func TestSearchService_EncryptedContentNotIndexed(t *testing.T) {
+	s := newTestServices(t)
+	s.createEncrypted(t)
+	s.createNote(t, &models.Note{Title: "open", Content: "nothing to hide"})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{secretWord, []string{}},
+		{"hidden", []string{}},
+		{"vault", []string{"vault"}},
+		{"tag:private", []string{"vault"}},
+		{"hide", []string{"open"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := s.searchTitles(t, tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	var indexed int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM notes_fts WHERE content LIKE ?", "%"+secretWord+"%").Scan(&indexed); err != nil {
+		t.Fatalf("read search index: %v", err)
+	}
+	if indexed != 0 {
+		t.Errorf("search index holds the encrypted content in %d row(s)", indexed)
+	}
+}
+
+func TestSearchService_EncryptedSnippet(t *testing.T) {
+	s := newTestServices(t)
+	s.createEncrypted(t)
+
+	results, err := s.search.Search(context.Background(), "vault", models.ListOptions{})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	result := results[0]
+	if !result.Note.Encrypted {
+		t.Error("result is not marked encrypted")
+	}
+	if got := result.Note.Snippet(); got != "" {
+		t.Errorf("Snippet() = %q, want empty", got)
+	}
+	if strings.Contains(result.Snippet, secretWord) {
+		t.Errorf("search snippet %q shows the encrypted content", result.Snippet)
+	}
+}
+
+func TestNoteService_ExportEncrypted(t *testing.T) {
+	tests := []struct {
+		name       string
+		passphrase string
+		wantErr    error
+		wantTitles []string
+		wantSecret bool
+	}{
+		{"without passphrase", "", nil, []string{"open"}, false},
+		{"with passphrase", "hunter2", nil, []string{"open", "vault"}, true},
+		{"wrong passphrase", "guess", models.ErrValidation, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			s.createEncrypted(t)
+			s.createNote(t, &models.Note{Title: "open", Content: "nothing to hide"})
+
+			dir := t.TempDir()
+			exported, err := s.notes.Export(context.Background(), dir, tt.passphrase)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Export() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+
+			var titles []string
+			for _, e := range exported {
+				titles = append(titles, e.Title)
+			}
+			sort.Strings(titles)
+			if !reflect.DeepEqual(titles, tt.wantTitles) {
+				t.Errorf("exported %v, want %v", titles, tt.wantTitles)
+			}
+
+			found := false
+			err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				found = found || strings.Contains(string(data), secretWord)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("read export: %v", err)
+			}
+			if found != tt.wantSecret {
+				t.Errorf("export holds the encrypted content = %v, want %v", found, tt.wantSecret)
+			}
+		})
+	}
+}
+
+func TestNoteService_ExportEncryptedFileMode(t *testing.T) {
+	s := newTestServices(t)
+	s.createEncrypted(t)
+	s.createNote(t, &models.Note{Title: "open", Content: "nothing to hide"})
+
+	// Files left by an earlier export are tightened, not kept as they were
+	dir := t.TempDir()
+	for _, name := range []string{"vault.md", "open.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatalf("write old export: %v", err)
+		}
+	}
+	if _, err := s.notes.Export(context.Background(), dir, "hunter2"); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want fs.FileMode
+	}{
+		{"vault.md", 0600},
+		{"open.md", 0644},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := os.Stat(filepath.Join(dir, tt.name))
+			if err != nil {
+				t.Fatalf("stat export: %v", err)
+			}
+			if got := info.Mode().Perm(); got != tt.want {
+				t.Errorf("mode = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
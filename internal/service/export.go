@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+// ExportManifestName is the file Export writes next to the notes, mapping
+// each note ID to the file that holds it
+const ExportManifestName = "manifest.json"
+
+// exportNameMaxBytes caps a file or directory name in bytes, leaving room
+// for an ID suffix and the extension within common 255-byte limits
+const exportNameMaxBytes = 200
+
+// ExportedNote records where Export wrote a note
+type ExportedNote struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	// Path is relative to the export directory and slash-separated
+	Path string `json:"path"`
+}
+
+// Export writes every note to dir as a markdown file named by its title,
+// in directories mirroring its folder path; notes outside any folder go in
+// dir itself. Each file starts with an "id:" frontmatter key, so importing
+// it again with --on-conflict matches the original note.
+//
+// Names are made safe on every OS. When two notes would share a file, or
+// two folders a directory, the later one gets its ID appended, e.g.
+// "Standup (42).md". The mapping is also written to ExportManifestName.
+//
+// Encrypted notes are left out unless passphrase is given, and then
+// written in plain text. Every one of them is opened before anything is
+// written, so a passphrase that fails on any note exports nothing.
+func (s *NoteService) Export(ctx context.Context, dir, passphrase string) ([]ExportedNote, error) {
+	folders, err := s.folderRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list folders: %w", err)
+	}
+	// Oldest first, so re-exporting keeps the plain name on the same note
+	notes, err := s.noteRepo.List(ctx, models.ListOptions{OrderBy: "created_at"})
+	if err != nil {
+		return nil, fmt.Errorf("list notes: %w", err)
+	}
+
+	plain := make([]*models.Note, 0, len(notes))
+	for _, note := range notes {
+		if !note.Encrypted {
+			plain = append(plain, note)
+			continue
+		}
+		if passphrase == "" {
+			continue
+		}
+		content, err := s.Reveal(note, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("open note %d: %w", note.ID, err)
+		}
+		opened := *note
+		opened.Content = content
+		plain = append(plain, &opened)
+	}
+
+	layout := newExportLayout(folders)
+	exported := make([]ExportedNote, 0, len(plain))
+	for _, note := range plain {
+		rel := layout.notePath(note)
+		file := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return exported, fmt.Errorf("create export directory: %w", err)
+		}
+		// An opened note is written as privately as OpenExternal would
+		perm := os.FileMode(0644)
+		if note.Encrypted {
+			perm = 0600
+		}
+		if err := writeExportFile(file, []byte(exportNoteData(note)), perm); err != nil {
+			return exported, fmt.Errorf("write %s: %w", rel, err)
+		}
+		exported = append(exported, ExportedNote{ID: note.ID, Title: note.Title, Path: rel})
+	}
+
+	manifest, err := json.MarshalIndent(exported, "", "  ")
+	if err != nil {
+		return exported, fmt.Errorf("encode manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ExportManifestName), append(manifest, '\n'), 0644); err != nil {
+		return exported, fmt.Errorf("write manifest: %w", err)
+	}
+	return exported, nil
+}
+
+// exportNoteData renders a note the way the editor shows it, with its ID
+// in frontmatter
+func exportNoteData(note *models.Note) string {
+	data := fmt.Sprintf("---\nid: %d\n---\n# %s\n", note.ID, note.Title)
+	if note.Content != "" {
+		data += "\n" + note.Content + "\n"
+	}
+	return data
+}
+
+// exportLayout assigns each folder a directory and each note a file,
+// keeping names unique within their directory
+type exportLayout struct {
+	byID map[int64]*models.Folder
+	dirs map[int64]string
+	// used holds the names taken in each directory, lowercased because
+	// macOS and Windows file systems ignore case
+	used map[string]map[string]bool
+}
+
+func newExportLayout(folders []*models.Folder) *exportLayout {
+	l := &exportLayout{
+		byID: make(map[int64]*models.Folder, len(folders)),
+		dirs: make(map[int64]string, len(folders)),
+		used: make(map[string]map[string]bool),
+	}
+	for _, folder := range folders {
+		l.byID[folder.ID] = folder
+	}
+	// Reserve the manifest so no folder can take its name
+	l.claim("", ExportManifestName, "", 0)
+	return l
+}
+
+// folderDir returns a folder's directory, relative and slash-separated.
+// A missing folder maps to the top level. seen guards against a corrupt
+// parent cycle.
+func (l *exportLayout) folderDir(id int64, seen map[int64]bool) string {
+	if dir, ok := l.dirs[id]; ok {
+		return dir
+	}
+	folder, ok := l.byID[id]
+	if !ok || seen[id] {
+		return ""
+	}
+	seen[id] = true
+
+	parent := ""
+	if folder.ParentID != nil {
+		parent = l.folderDir(*folder.ParentID, seen)
+	}
+	dir := path.Join(parent, l.claim(parent, safeFileName(folder.Name), "", folder.ID))
+	l.dirs[id] = dir
+	return dir
+}
+
+// notePath returns the file for a note, relative and slash-separated
+func (l *exportLayout) notePath(note *models.Note) string {
+	dir := ""
+	if note.FolderID != nil {
+		dir = l.folderDir(*note.FolderID, map[int64]bool{})
+	}
+	return path.Join(dir, l.claim(dir, safeFileName(note.Title), ".md", note.ID))
+}
+
+// claim takes name+ext in dir, appending id when it is already taken
+func (l *exportLayout) claim(dir, name, ext string, id int64) string {
+	taken := l.used[dir]
+	if taken == nil {
+		taken = make(map[string]bool)
+		l.used[dir] = taken
+	}
+
+	candidate := name + ext
+	suffix := strconv.FormatInt(id, 10)
+	// A title that itself ends in "(id)" can still collide, so count on
+	for i := 2; taken[strings.ToLower(candidate)]; i++ {
+		candidate = name + " (" + suffix + ")" + ext
+		suffix = strconv.FormatInt(id, 10) + "-" + strconv.Itoa(i)
+	}
+	taken[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+// writeExportFile writes data to file with perm, also when file is left
+// over from an earlier export with a looser one. The mode is set before
+// anything is written.
+func writeExportFile(file string, data []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// windowsReservedNames cannot be used as a file name on Windows, with or
+// without an extension
+var windowsReservedNames = map[string]bool{
+	"con": true, "prn": true, "aux": true, "nul": true,
+	"com1": true, "com2": true, "com3": true, "com4": true, "com5": true,
+	"com6": true, "com7": true, "com8": true, "com9": true,
+	"lpt1": true, "lpt2": true, "lpt3": true, "lpt4": true, "lpt5": true,
+	"lpt6": true, "lpt7": true, "lpt8": true, "lpt9": true,
+}
+
+// safeFileName turns a title into a name every OS accepts: path
+// separators, characters Windows forbids and control characters become
+// "_", trailing dots and spaces are dropped, reserved device names get a
+// leading "_", and long names are shortened
+func safeFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r < 0x20, r == 0x7f:
+			return '_'
+		case strings.ContainsRune(`/\:*?"<>|`, r):
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(title))
+
+	if len(name) > exportNameMaxBytes {
+		n := exportNameMaxBytes
+		for n > 0 && !utf8.RuneStart(name[n]) {
+			n--
+		}
+		name = name[:n]
+	}
+	name = strings.TrimRight(name, ". ")
+
+	base, _, _ := strings.Cut(name, ".")
+	if windowsReservedNames[strings.ToLower(strings.TrimSpace(base))] {
+		name = "_" + name
+	}
+	if name == "" {
+		name = UntitledNoteTitle
+	}
+	return name
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+func TestSafeFileName(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Meeting notes", "Meeting notes"},
+		{"a/b\\c", "a_b_c"},
+		{`what? "now" <maybe>: *|`, "what_ _now_ _maybe__ __"},
+		{"tab\there", "tab_here"},
+		{"trailing dots...", "trailing dots"},
+		{"  spaces  ", "spaces"},
+		{"ends. .", "ends"},
+		{"CON", "_CON"},
+		{"nul.txt", "_nul.txt"},
+		{"Lpt1 ", "_Lpt1"},
+		{"console", "console"},
+		{"...", UntitledNoteTitle},
+		{"", UntitledNoteTitle},
+		{strings.Repeat("é", exportNameMaxBytes), strings.Repeat("é", exportNameMaxBytes/2)},
+		{strings.Repeat("会", exportNameMaxBytes), strings.Repeat("会", exportNameMaxBytes/3)},
+		{strings.Repeat("📝", exportNameMaxBytes), strings.Repeat("📝", exportNameMaxBytes/4)},
+		{strings.Repeat("a", exportNameMaxBytes-1) + "é", strings.Repeat("a", exportNameMaxBytes-1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			if got := safeFileName(tt.title); got != tt.want {
+				t.Errorf("safeFileName(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoteService_Export_Names(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	work := int64(1)
+	personal := int64(2)
+	clash := &models.Folder{Name: "manifest.json"}
+	if err := s.folders.Create(ctx, clash); err != nil {
+		t.Fatalf("Create folder error = %v", err)
+	}
+	clashDir := fmt.Sprintf("manifest.json (%d)", clash.ID)
+
+	// Created in this order, so the first of each clash keeps the plain name
+	notes := []struct {
+		title    string
+		folderID *int64
+		wantPath string
+	}{
+		{"Standup", &work, "Work/Standup.md"},
+		{"Standup", &work, "Work/Standup (%d).md"},
+		{"standup", &work, "Work/standup (%d).md"},
+		{"Standup", &personal, "Personal/Standup.md"},
+		{"Standup", nil, "Standup.md"},
+		{"a/b: c?", nil, "a_b_ c_.md"},
+		{"a\\b: c*", nil, "a_b_ c_ (%d).md"},
+		{"manifest", nil, "manifest.md"},
+		{"manifest.json", nil, "manifest.json.md"},
+		{"AUX", nil, "_AUX.md"},
+		{"Inside", &clash.ID, clashDir + "/Inside.md"},
+	}
+	want := make(map[int64]string, len(notes))
+	for _, n := range notes {
+		note := s.createNote(t, &models.Note{Title: n.title, Content: "body of " + n.title, FolderID: n.folderID})
+		wantPath := n.wantPath
+		if strings.Contains(wantPath, "%d") {
+			wantPath = fmt.Sprintf(wantPath, note.ID)
+		}
+		want[note.ID] = wantPath
+	}
+
+	dir := t.TempDir()
+	exported, err := s.notes.Export(ctx, dir, "")
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if len(exported) != len(notes) {
+		t.Fatalf("exported %d notes, want %d", len(exported), len(notes))
+	}
+	for _, e := range exported {
+		if e.Path != want[e.ID] {
+			t.Errorf("note %d %q written to %q, want %q", e.ID, e.Title, e.Path, want[e.ID])
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(e.Path)))
+		if err != nil {
+			t.Errorf("read %s: %v", e.Path, err)
+			continue
+		}
+		if !strings.HasPrefix(string(data), fmt.Sprintf("---\nid: %d\n---\n", e.ID)) || !strings.Contains(string(data), "body of "+e.Title) {
+			t.Errorf("%s holds %q", e.Path, data)
+		}
+	}
+
+	var manifest []ExportedNote
+	data, err := os.ReadFile(filepath.Join(dir, ExportManifestName))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("decode manifest: %v", err)
+	}
+	for _, e := range manifest {
+		if e.Path != want[e.ID] {
+			t.Errorf("manifest maps note %d to %q, want %q", e.ID, e.Path, want[e.ID])
+		}
+	}
+	if len(manifest) != len(notes) {
+		t.Errorf("manifest lists %d notes, want %d", len(manifest), len(notes))
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/repository"
+)
+
+func TestNoteService_PicksUpExternalEdits(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		age         time.Duration
+		wantContent string
+		wantEdited  bool
+		wantFound   []string
+	}{
+		{"untouched", "", 0, "original words", false, []string{}},
+		{"edited", "rewritten elsewhere", time.Hour, "rewritten elsewhere", true, []string{"outside"}},
+		{"same content touched later", "original words", time.Hour, "original words", true, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := repository.NewContentFiles(filepath.Join(t.TempDir(), "notes"))
+			s := newTestServicesWithFiles(t, files)
+			note := s.createNote(t, &models.Note{Title: "outside", Content: "original words"})
+
+			if tt.content != "" {
+				if err := os.WriteFile(files.Path(note.ID), []byte(tt.content), 0644); err != nil {
+					t.Fatalf("write note file: %v", err)
+				}
+				modTime := note.UpdatedAt.Add(tt.age)
+				if err := os.Chtimes(files.Path(note.ID), modTime, modTime); err != nil {
+					t.Fatalf("set note file time: %v", err)
+				}
+			}
+
+			got := s.getNote(t, note.ID)
+			if got.Content != tt.wantContent {
+				t.Errorf("content = %q, want %q", got.Content, tt.wantContent)
+			}
+			if got.EditedExternally != tt.wantEdited {
+				t.Errorf("EditedExternally = %v, want %v", got.EditedExternally, tt.wantEdited)
+			}
+
+			// Reading leaves the SQLite copy alone until it is refreshed
+			var stored string
+			if err := s.db.QueryRow("SELECT content FROM notes WHERE id = ?", note.ID).Scan(&stored); err != nil {
+				t.Fatalf("read stored content: %v", err)
+			}
+			if stored != "original words" {
+				t.Errorf("stored content = %q, want %q", stored, "original words")
+			}
+			if found := s.searchTitles(t, "rewritten"); len(found) != 0 {
+				t.Errorf("search before refresh found %v, want none", found)
+			}
+
+			if _, err := s.notes.ImportContent(context.Background(), files.Dir()); err != nil {
+				t.Fatalf("ImportContent() error = %v", err)
+			}
+			if found := s.searchTitles(t, "rewritten"); !reflect.DeepEqual(found, tt.wantFound) {
+				t.Errorf("search after refresh found %v, want %v", found, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestNoteService_FilesFollowCommittedWrites(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(ctx context.Context, s *NoteService, note, other *models.Note) error
+		want    string // content of note's file, "" for no file
+		wantOld bool   // whether other's file remains
+	}{
+		{"update", func(ctx context.Context, s *NoteService, note, other *models.Note) error {
+			note.Content = "updated"
+			return s.Update(ctx, note)
+		}, "updated", true},
+		{"append", func(ctx context.Context, s *NoteService, note, other *models.Note) error {
+			return s.AppendContent(ctx, note.ID, "more")
+		}, "first\nmore", true},
+		{"delete", func(ctx context.Context, s *NoteService, note, other *models.Note) error {
+			return s.Delete(ctx, note.ID)
+		}, "", true},
+		{"merge", func(ctx context.Context, s *NoteService, note, other *models.Note) error {
+			_, err := s.Merge(ctx, note.ID, []int64{other.ID}, false)
+			return err
+		}, "first\n\n## other\n\nsecond", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "notes")
+			files := repository.NewContentFiles(dir)
+			s := newTestServicesWithFiles(t, files)
+			note := s.createNote(t, &models.Note{Title: "note", Content: "first"})
+			other := s.createNote(t, &models.Note{Title: "other", Content: "second"})
+
+			if err := tt.change(context.Background(), s.notes, note, other); err != nil {
+				t.Fatalf("change error = %v", err)
+			}
+
+			content, ok, err := files.Read(note.ID)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if ok != (tt.want != "") || content != tt.want {
+				t.Errorf("note file = %q (exists %v), want %q", content, ok, tt.want)
+			}
+			if _, ok, _ := files.Read(other.ID); ok != tt.wantOld {
+				t.Errorf("other note file exists = %v, want %v", ok, tt.wantOld)
+			}
+			if tmp, _ := filepath.Glob(filepath.Join(dir, ".note-*.tmp")); len(tmp) > 0 {
+				t.Errorf("staged files left behind: %v", tmp)
+			}
+		})
+	}
+}
+
+func TestContentFiles_DiscardKeepsFile(t *testing.T) {
+	files := repository.NewContentFiles(t.TempDir())
+	if err := files.Write(1, "committed", time.Now()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	staged, err := files.Stage(1, "rolled back", time.Now())
+	if err != nil {
+		t.Fatalf("Stage() error = %v", err)
+	}
+	if content, _, _ := files.Read(1); content != "committed" {
+		t.Errorf("staging changed the file to %q", content)
+	}
+	staged.Discard()
+
+	if content, _, _ := files.Read(1); content != "committed" {
+		t.Errorf("file = %q after discard, want %q", content, "committed")
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(files.Dir(), ".note-*.tmp")); len(tmp) > 0 {
+		t.Errorf("staged files left behind: %v", tmp)
+	}
+}
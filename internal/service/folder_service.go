@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/tranducquang/kiroku/internal/models"
 	"github.com/tranducquang/kiroku/internal/repository"
@@ -13,16 +14,20 @@ import (
 type FolderService struct {
 	folderRepo repository.FolderRepositoryInterface
 	noteRepo   repository.NoteRepositoryInterface
+	maxDepth   int
 }
 
 // NewFolderService creates a new folder service with the given repositories.
+// A positive maxDepth limits how many levels folders may be nested.
 func NewFolderService(
 	folderRepo repository.FolderRepositoryInterface,
 	noteRepo repository.NoteRepositoryInterface,
+	maxDepth int,
 ) *FolderService {
 	return &FolderService{
 		folderRepo: folderRepo,
 		noteRepo:   noteRepo,
+		maxDepth:   maxDepth,
 	}
 }
 
@@ -31,9 +36,164 @@ func (s *FolderService) Create(ctx context.Context, folder *models.Folder) error
 	if err := folder.Validate(); err != nil {
 		return fmt.Errorf("validate folder: %w", err)
 	}
+	if err := s.checkDepth(ctx, folder.ParentID, 1); err != nil {
+		return err
+	}
 	return s.folderRepo.Create(ctx, folder)
 }
 
+// GetOrCreateByName returns the folder with the given name, matched
+// case-insensitively and preferring a root folder, creating it at the
+// root when none exists.
+func (s *FolderService) GetOrCreateByName(ctx context.Context, name string) (*models.Folder, error) {
+	folders, err := s.folderRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list folders: %w", err)
+	}
+
+	var match *models.Folder
+	for _, folder := range folders {
+		if strings.EqualFold(folder.Name, name) && (match == nil || (folder.IsRoot() && !match.IsRoot())) {
+			match = folder
+		}
+	}
+	if match != nil {
+		return match, nil
+	}
+
+	folder := &models.Folder{Name: name}
+	if err := s.Create(ctx, folder); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+// MoveFolder moves a folder under a new parent, or to the root when parentID is nil.
+func (s *FolderService) MoveFolder(ctx context.Context, id int64, parentID *int64) error {
+	folder, err := s.folderRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get folder: %w", err)
+	}
+
+	if parentID != nil {
+		if *parentID == id {
+			return models.ErrFolderCycle
+		}
+		ancestors, err := s.Ancestors(ctx, *parentID)
+		if err != nil {
+			return err
+		}
+		for _, ancestor := range ancestors {
+			if ancestor.ID == id {
+				return models.ErrFolderCycle
+			}
+		}
+	}
+
+	height, err := s.subtreeHeight(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkDepth(ctx, parentID, height); err != nil {
+		return err
+	}
+
+	folder.ParentID = parentID
+	return s.folderRepo.Update(ctx, folder)
+}
+
+// Ancestors returns the ancestors of a folder, nearest parent first.
+func (s *FolderService) Ancestors(ctx context.Context, id int64) ([]*models.Folder, error) {
+	folder, err := s.folderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get folder: %w", err)
+	}
+
+	var ancestors []*models.Folder
+	seen := map[int64]bool{folder.ID: true}
+	for folder.ParentID != nil {
+		if seen[*folder.ParentID] {
+			return nil, models.ErrFolderCycle
+		}
+		folder, err = s.folderRepo.GetByID(ctx, *folder.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("get parent folder: %w", err)
+		}
+		seen[folder.ID] = true
+		ancestors = append(ancestors, folder)
+	}
+	return ancestors, nil
+}
+
+// Path returns the folder's location from the root, e.g. "Work / Projects".
+func (s *FolderService) Path(ctx context.Context, id int64) (string, error) {
+	folder, err := s.folderRepo.GetByID(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("get folder: %w", err)
+	}
+	ancestors, err := s.Ancestors(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(ancestors)+1)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		names = append(names, ancestors[i].Name)
+	}
+	names = append(names, folder.Name)
+	return strings.Join(names, models.FolderPathSeparator), nil
+}
+
+// checkDepth rejects placing a subtree of the given height under parentID
+// when the deepest folder would exceed the configured maximum depth.
+func (s *FolderService) checkDepth(ctx context.Context, parentID *int64, height int) error {
+	if s.maxDepth <= 0 {
+		return nil
+	}
+
+	parentDepth := 0
+	if parentID != nil {
+		ancestors, err := s.Ancestors(ctx, *parentID)
+		if err != nil {
+			return err
+		}
+		parentDepth = len(ancestors) + 1
+	}
+	if parentDepth+height > s.maxDepth {
+		return fmt.Errorf("%w: max depth is %d", models.ErrFolderTooDeep, s.maxDepth)
+	}
+	return nil
+}
+
+// subtreeHeight returns the number of levels in the subtree rooted at id, counting id itself.
+func (s *FolderService) subtreeHeight(ctx context.Context, id int64) (int, error) {
+	return s.subtreeHeightSeen(ctx, id, map[int64]bool{})
+}
+
+// subtreeHeightSeen is subtreeHeight, skipping folders already in seen so a
+// cycle left in the database cannot recurse forever
+func (s *FolderService) subtreeHeightSeen(ctx context.Context, id int64, seen map[int64]bool) (int, error) {
+	if seen[id] {
+		return 0, nil
+	}
+	seen[id] = true
+
+	children, err := s.folderRepo.GetChildren(ctx, id)
+	if err != nil {
+		return 0, fmt.Errorf("get children: %w", err)
+	}
+
+	height := 0
+	for _, child := range children {
+		h, err := s.subtreeHeightSeen(ctx, child.ID, seen)
+		if err != nil {
+			return 0, err
+		}
+		height = max(height, h)
+	}
+	return height + 1, nil
+}
+
 // GetByID retrieves a folder by ID.
 func (s *FolderService) GetByID(ctx context.Context, id int64) (*models.Folder, error) {
 	return s.folderRepo.GetByID(ctx, id)
@@ -91,6 +251,27 @@ func (s *FolderService) GetTree(ctx context.Context) ([]*models.Folder, error) {
 	return rootFolders, nil
 }
 
+// CountNotesRecursive counts the notes in a folder and all of its subfolders.
+func (s *FolderService) CountNotesRecursive(ctx context.Context, id int64) (int, error) {
+	count, err := s.folderRepo.CountNotes(ctx, id)
+	if err != nil {
+		return 0, fmt.Errorf("count notes: %w", err)
+	}
+
+	children, err := s.folderRepo.GetChildren(ctx, id)
+	if err != nil {
+		return 0, fmt.Errorf("get children: %w", err)
+	}
+	for _, child := range children {
+		n, err := s.CountNotesRecursive(ctx, child.ID)
+		if err != nil {
+			return 0, err
+		}
+		count += n
+	}
+	return count, nil
+}
+
 // GetChildren retrieves child folders of a parent folder.
 func (s *FolderService) GetChildren(ctx context.Context, parentID int64) ([]*models.Folder, error) {
 	return s.folderRepo.GetChildren(ctx, parentID)
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+// createChain creates a chain of nested folders, the first at the root,
+// and returns their IDs outermost first
+func (s *testServices) createChain(t *testing.T, prefix string, levels int) []int64 {
+	t.Helper()
+	var ids []int64
+	var parentID *int64
+	for i := 0; i < levels; i++ {
+		folder := &models.Folder{Name: fmt.Sprintf("%s %d", prefix, i+1), ParentID: parentID}
+		if err := s.folders.Create(context.Background(), folder); err != nil {
+			t.Fatalf("Create(%q) error = %v", folder.Name, err)
+		}
+		ids = append(ids, folder.ID)
+		parentID = &folder.ID
+	}
+	return ids
+}
+
+func TestFolderService_Create_Depth(t *testing.T) {
+	tests := []struct {
+		name    string
+		parents int // levels above the new folder
+		wantErr bool
+	}{
+		{"at root", 0, false},
+		{"at the limit", testMaxFolderDepth - 1, false},
+		{"over the limit", testMaxFolderDepth, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			chain := s.createChain(t, "level", tt.parents)
+
+			folder := &models.Folder{Name: "new"}
+			if len(chain) > 0 {
+				folder.ParentID = &chain[len(chain)-1]
+			}
+			err := s.folders.Create(context.Background(), folder)
+			if tt.wantErr {
+				if !errors.Is(err, models.ErrFolderTooDeep) {
+					t.Errorf("Create() error = %v, want ErrFolderTooDeep", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Create() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestFolderService_MoveFolder_Depth(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  int // levels of the folder the subtree moves under
+		height  int // levels in the moved subtree
+		wantErr bool
+	}{
+		{"to the root", 0, testMaxFolderDepth, false},
+		{"leaf at the limit", testMaxFolderDepth - 1, 1, false},
+		{"subtree at the limit", 1, testMaxFolderDepth - 1, false},
+		{"leaf over the limit", testMaxFolderDepth, 1, true},
+		{"subtree over the limit", 2, testMaxFolderDepth - 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			ctx := context.Background()
+			target := s.createChain(t, "target", tt.target)
+			moved := s.createChain(t, "moved", tt.height)
+
+			var parentID *int64
+			if len(target) > 0 {
+				parentID = &target[len(target)-1]
+			}
+			err := s.folders.MoveFolder(ctx, moved[0], parentID)
+			if tt.wantErr {
+				if !errors.Is(err, models.ErrFolderTooDeep) {
+					t.Errorf("MoveFolder() error = %v, want ErrFolderTooDeep", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MoveFolder() error = %v", err)
+			}
+			ancestors, err := s.folders.Ancestors(ctx, moved[len(moved)-1])
+			if err != nil {
+				t.Fatalf("Ancestors() error = %v", err)
+			}
+			if depth := len(ancestors) + 1; depth != tt.target+tt.height {
+				t.Errorf("deepest moved folder at depth %d, want %d", depth, tt.target+tt.height)
+			}
+		})
+	}
+}
+
+func TestFolderService_MoveFolder_OutOfCycle(t *testing.T) {
+	s := newTestServices(t)
+	ctx := context.Background()
+	chain := s.createChain(t, "loop", 2)
+	// Only a damaged database holds a cycle; moving to the root breaks it
+	if _, err := s.db.Exec("UPDATE folders SET parent_id = ? WHERE id = ?", chain[1], chain[0]); err != nil {
+		t.Fatalf("create cycle: %v", err)
+	}
+
+	if err := s.folders.MoveFolder(ctx, chain[0], nil); err != nil {
+		t.Fatalf("MoveFolder() error = %v", err)
+	}
+	ancestors, err := s.folders.Ancestors(ctx, chain[1])
+	if err != nil {
+		t.Fatalf("Ancestors() error = %v", err)
+	}
+	if len(ancestors) != 1 || ancestors[0].ID != chain[0] {
+		t.Errorf("ancestors of the inner folder = %v, want only #%d", ancestors, chain[0])
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/repository"
+)
+
+// Import conflict policies, applied when an imported file matches an
+// existing note by frontmatter ID or title
+const (
+	// ImportDuplicate always creates a new note, so nothing is lost
+	ImportDuplicate = "duplicate"
+	// ImportSkip leaves the existing note and drops the file
+	ImportSkip = "skip"
+	// ImportOverwrite replaces the existing note's title and content
+	ImportOverwrite = "overwrite"
+)
+
+// ImportPolicies lists the conflict policies
+var ImportPolicies = []string{ImportDuplicate, ImportSkip, ImportOverwrite}
+
+// Actions an import can take on a file
+const (
+	ImportCreated     = "created"
+	ImportSkipped     = "skipped"
+	ImportOverwritten = "overwritten"
+)
+
+// ImportResult is what importing one file did
+type ImportResult struct {
+	Note   *models.Note
+	Action string
+	// TitleTruncated is set when the file's title was longer than the
+	// title length limit and was cut to fit
+	TitleTruncated bool
+}
+
+// Import creates a note from a markdown file. The title is read the same
+// way as an edited note: frontmatter title, a leading heading, or the
+// first line, falling back to fallbackTitle. An existing note matches by
+// an "id:" frontmatter key, or else by exact title; onConflict decides what
+// happens then. New notes go into folderID when it is set. A title over
+// the length limit is truncated rather than rejected.
+func (s *NoteService) Import(ctx context.Context, data, fallbackTitle string, folderID *int64, onConflict string) (ImportResult, error) {
+	switch onConflict {
+	case ImportDuplicate, ImportSkip, ImportOverwrite:
+	default:
+		return ImportResult{}, fmt.Errorf("%w: unknown conflict policy %q (use %s)",
+			models.ErrValidation, onConflict, strings.Join(ImportPolicies, ", "))
+	}
+
+	title, content := parseEditedNote(data, fallbackTitle)
+	title, truncated := models.TruncateTitle(title, s.maxTitle)
+
+	if onConflict != ImportDuplicate {
+		existing, err := s.findImportMatch(ctx, data, title)
+		if err != nil {
+			return ImportResult{}, err
+		}
+		if existing != nil && onConflict == ImportSkip {
+			return ImportResult{Note: existing, Action: ImportSkipped, TitleTruncated: truncated}, nil
+		}
+		if existing != nil {
+			existing.Title = title
+			existing.Content = content
+			if err := s.Update(ctx, existing); err != nil {
+				return ImportResult{}, err
+			}
+			return ImportResult{Note: existing, Action: ImportOverwritten, TitleTruncated: truncated}, nil
+		}
+	}
+
+	note := &models.Note{Title: title, Content: content, FolderID: folderID}
+	if err := s.Create(ctx, note); err != nil {
+		return ImportResult{}, err
+	}
+	return ImportResult{Note: note, Action: ImportCreated, TitleTruncated: truncated}, nil
+}
+
+// findImportMatch returns the note an imported file corresponds to, or nil.
+// A frontmatter ID naming a note that no longer exists falls back to the title.
+func (s *NoteService) findImportMatch(ctx context.Context, data, title string) (*models.Note, error) {
+	if fm, _, ok := splitFrontmatter(strings.TrimLeft(data, "\n")); ok {
+		if id, err := strconv.ParseInt(frontmatterValue(fm, "id"), 10, 64); err == nil {
+			note, err := s.noteRepo.GetByID(ctx, id)
+			if err == nil {
+				return note, nil
+			}
+			if !errors.Is(err, repository.ErrNotFound) {
+				return nil, fmt.Errorf("get note: %w", err)
+			}
+		}
+	}
+
+	note, err := s.noteRepo.GetByTitle(ctx, title)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get note: %w", err)
+	}
+	return note, nil
+}
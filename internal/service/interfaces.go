@@ -5,6 +5,7 @@ package service
 import (
 	"context"
 	"os/exec"
+	"time"
 
 	"github.com/tranducquang/kiroku/internal/models"
 )
@@ -13,18 +14,49 @@ import (
 type NoteServiceInterface interface {
 	Create(ctx context.Context, note *models.Note) error
 	GetByID(ctx context.Context, id int64) (*models.Note, error)
+	GetByTitle(ctx context.Context, title string) (*models.Note, error)
+	Backlinks(ctx context.Context, note *models.Note) ([]*models.Note, error)
+	CreateOrGetByTitle(ctx context.Context, note *models.Note) (*models.Note, error)
 	Update(ctx context.Context, note *models.Note) error
 	Delete(ctx context.Context, id int64) error
 	GetAllNotes(ctx context.Context) ([]*models.Note, error)
+	List(ctx context.Context, opts models.ListOptions) ([]*models.Note, error)
 	GetByFolder(ctx context.Context, folderID int64) ([]*models.Note, error)
 	GetTodos(ctx context.Context, showCompleted bool) ([]*models.Note, error)
 	GetStarred(ctx context.Context) ([]*models.Note, error)
+	GetToday(ctx context.Context) ([]*models.Note, error)
 	GetRecent(ctx context.Context, limit int) ([]*models.Note, error)
+	GetStale(ctx context.Context, cutoff time.Time, includeStarred bool) ([]*models.Note, error)
+	GetUnfiled(ctx context.Context) ([]*models.Note, error)
+	RepairFolderRefs(ctx context.Context) (int, error)
+	ExportContent(ctx context.Context, dir string) (int, error)
+	Export(ctx context.Context, dir, passphrase string) ([]ExportedNote, error)
+	ImportContent(ctx context.Context, dir string) (int, error)
 	ToggleStar(ctx context.Context, id int64) error
 	ToggleTodo(ctx context.Context, id int64) error
+	ToggleLock(ctx context.Context, id int64) error
+	MarkDone(ctx context.Context, ids []int64, done bool) (int, error)
+	SetStarred(ctx context.Context, ids []int64, starred bool) (int, error)
 	SetPriority(ctx context.Context, id int64, priority int) error
+	SwapOrder(ctx context.Context, id, otherID int64) error
+	Merge(ctx context.Context, dstID int64, srcIDs []int64, keepSources bool) (*models.Note, error)
+	Encrypt(ctx context.Context, id int64, passphrase string) error
+	Decrypt(ctx context.Context, id int64, passphrase string) error
+	Reveal(note *models.Note, passphrase string) (string, error)
+	UpdateEncrypted(ctx context.Context, note *models.Note, passphrase string) error
+	SetDueDate(ctx context.Context, id int64, due *time.Time) error
+	SetLabel(ctx context.Context, id int64, label string) error
 	MoveToFolder(ctx context.Context, noteID, folderID int64) error
 	Count(ctx context.Context, opts models.ListOptions) (int, error)
+	ListByTags(ctx context.Context, opts models.ListOptions, tags []string, matchAll bool) ([]*models.Note, error)
+	RenameTag(ctx context.Context, oldTag, newTag string) (int, error)
+	DeleteTag(ctx context.Context, tag string) (int, error)
+	ListTags(ctx context.Context) ([]models.TagCount, error)
+	Search(ctx context.Context, query string, opts models.ListOptions) ([]models.SearchResult, error)
+	Capture(ctx context.Context, text string) (*models.Note, error)
+	Scratch(ctx context.Context) (*models.Note, error)
+	AppendContent(ctx context.Context, id int64, text string) error
+	Import(ctx context.Context, data, fallbackTitle string, folderID *int64, onConflict string) (ImportResult, error)
 }
 
 // FolderServiceInterface defines the contract for folder business logic.
@@ -37,6 +69,10 @@ type FolderServiceInterface interface {
 	GetTree(ctx context.Context) ([]*models.Folder, error)
 	GetChildren(ctx context.Context, parentID int64) ([]*models.Folder, error)
 	ToggleStar(ctx context.Context, id int64) error
+	MoveFolder(ctx context.Context, id int64, parentID *int64) error
+	CountNotesRecursive(ctx context.Context, id int64) (int, error)
+	Path(ctx context.Context, id int64) (string, error)
+	GetOrCreateByName(ctx context.Context, name string) (*models.Folder, error)
 }
 
 // TemplateServiceInterface defines the contract for template business logic.
@@ -59,9 +95,11 @@ type SearchServiceInterface interface {
 // EditorServiceInterface defines the contract for editor operations.
 type EditorServiceInterface interface {
 	EditNote(title, content string) (newTitle, newContent string, err error)
+	EditNoteWith(editor, title, content string) (newTitle, newContent string, err error)
 	PrepareEdit(title, content string) (tmpFilePath string, cmd *exec.Cmd, err error)
 	ReadEditedContent(tmpFilePath, originalTitle string) (title, content string, err error)
 	CreateNote(templateContent string) (title, content string, err error)
+	OpenExternal(note *models.Note) (path string, err error)
 }
 
 // Compile-time interface compliance checks
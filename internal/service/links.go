@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+// DanglingLink is a [[Title]] link to a note that does not exist
+type DanglingLink struct {
+	NoteID    int64  `json:"note_id"`
+	NoteTitle string `json:"note_title"`
+	Target    string `json:"target"`
+}
+
+// OrphanNote is a note no other note links to
+type OrphanNote struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+// LinkReport is what CheckLinks found across all notes
+type LinkReport struct {
+	Dangling []DanglingLink `json:"dangling"`
+	Orphans  []OrphanNote   `json:"orphans"`
+}
+
+// CheckLinks resolves every [[Title]] link between notes, matching titles
+// ignoring case as Backlinks does, and reports the links to missing notes
+// and the notes nothing links to. Encrypted notes are scanned as stored,
+// so their links are not seen.
+func (s *NoteService) CheckLinks(ctx context.Context) (*LinkReport, error) {
+	notes, err := s.GetAllNotes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list notes: %w", err)
+	}
+
+	byTitle := make(map[string][]*models.Note, len(notes))
+	for _, note := range notes {
+		key := strings.ToLower(note.Title)
+		byTitle[key] = append(byTitle[key], note)
+	}
+
+	report := &LinkReport{Dangling: []DanglingLink{}, Orphans: []OrphanNote{}}
+	linked := make(map[int64]bool, len(notes))
+	for _, note := range notes {
+		for _, target := range note.Links() {
+			targets, ok := byTitle[strings.ToLower(target)]
+			if !ok {
+				report.Dangling = append(report.Dangling, DanglingLink{
+					NoteID:    note.ID,
+					NoteTitle: note.Title,
+					Target:    target,
+				})
+				continue
+			}
+			for _, t := range targets {
+				if t.ID != note.ID {
+					linked[t.ID] = true
+				}
+			}
+		}
+	}
+
+	for _, note := range notes {
+		if !linked[note.ID] {
+			report.Orphans = append(report.Orphans, OrphanNote{ID: note.ID, Title: note.Title})
+		}
+	}
+	return report, nil
+}
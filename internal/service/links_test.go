@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+func TestNoteService_CheckLinks(t *testing.T) {
+	s := newTestServices(t)
+	hub := s.createNote(t, &models.Note{Title: "Hub", Content: "[[alpha]], [[Missing]] and [[Hub]]"})
+	s.createNote(t, &models.Note{Title: "Alpha", Content: "```\n[[Beta]] [[Ghost]]\n```"})
+	beta := s.createNote(t, &models.Note{Title: "Beta", Content: "````\n```\n````\n[[Gone]]"})
+	s.createEncrypted(t)
+
+	report, err := s.notes.CheckLinks(context.Background())
+	if err != nil {
+		t.Fatalf("CheckLinks() error = %v", err)
+	}
+
+	sort.Slice(report.Dangling, func(i, j int) bool { return report.Dangling[i].NoteID < report.Dangling[j].NoteID })
+	wantDangling := []DanglingLink{
+		{NoteID: hub.ID, NoteTitle: "Hub", Target: "Missing"},
+		{NoteID: beta.ID, NoteTitle: "Beta", Target: "Gone"},
+	}
+	if !reflect.DeepEqual(report.Dangling, wantDangling) {
+		t.Errorf("dangling = %+v, want %+v", report.Dangling, wantDangling)
+	}
+
+	// Hub only links to itself, and the fenced link to Beta does not count
+	var orphans []string
+	for _, orphan := range report.Orphans {
+		orphans = append(orphans, orphan.Title)
+	}
+	sort.Strings(orphans)
+	if want := []string{"Beta", "Hub", "vault"}; !reflect.DeepEqual(orphans, want) {
+		t.Errorf("orphans = %v, want %v", orphans, want)
+	}
+}
+
+func TestNoteService_Backlinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"plain link", "see [[Target]]", []string{"source"}},
+		{"other case", "see [[target|it]]", []string{"source"}},
+		{"inside a fence", "```\n[[Target]]\n```", []string{}},
+		{"inside a nested fence", "````\n```\n[[Target]]\n```\n````", []string{}},
+		{"after an unterminated fence", "```\ncode\n[[Target]]", []string{}},
+		{"after a closed fence", "```\ncode\n```\n[[Target]]", []string{"source"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			target := s.createNote(t, &models.Note{Title: "Target"})
+			s.createNote(t, &models.Note{Title: "source", Content: tt.content})
+
+			notes, err := s.notes.Backlinks(context.Background(), target)
+			if err != nil {
+				t.Fatalf("Backlinks() error = %v", err)
+			}
+			if got := noteTitles(notes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Backlinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+// createLocked creates a locked todo in the first seeded folder
+func (s *testServices) createLocked(t *testing.T) *models.Note {
+	t.Helper()
+	folderID := int64(1)
+	note := s.createNote(t, &models.Note{Title: "locked", Content: "kept", Tags: "keep", IsTodo: true, FolderID: &folderID})
+	if err := s.notes.ToggleLock(context.Background(), note.ID); err != nil {
+		t.Fatalf("ToggleLock() error = %v", err)
+	}
+	return s.getNote(t, note.ID)
+}
+
+func TestNoteService_LockedNoteRefusesChanges(t *testing.T) {
+	due := time.Now().Add(24 * time.Hour)
+	tests := []struct {
+		name   string
+		change func(ctx context.Context, s *NoteService, note *models.Note) error
+	}{
+		{"update", func(ctx context.Context, s *NoteService, note *models.Note) error {
+			note.Content = "changed"
+			return s.Update(ctx, note)
+		}},
+		{"delete", func(ctx context.Context, s *NoteService, note *models.Note) error {
+			return s.Delete(ctx, note.ID)
+		}},
+		{"move", func(ctx context.Context, s *NoteService, note *models.Note) error {
+			return s.MoveToFolder(ctx, note.ID, 2)
+		}},
+		{"toggle todo", func(ctx context.Context, s *NoteService, note *models.Note) error {
+			return s.ToggleTodo(ctx, note.ID)
+		}},
+		{"priority", func(ctx context.Context, s *NoteService, note *models.Note) error {
+			return s.SetPriority(ctx, note.ID, 1)
+		}},
+		{"label", func(ctx context.Context, s *NoteService, note *models.Note) error {
+			return s.SetLabel(ctx, note.ID, "red")
+		}},
+		{"due date", func(ctx context.Context, s *NoteService, note *models.Note) error {
+			return s.SetDueDate(ctx, note.ID, &due)
+		}},
+		{"append", func(ctx context.Context, s *NoteService, note *models.Note) error {
+			return s.AppendContent(ctx, note.ID, "more")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			note := s.createLocked(t)
+
+			err := tt.change(context.Background(), s.notes, note)
+			if !errors.Is(err, models.ErrLocked) {
+				t.Fatalf("error = %v, want ErrLocked", err)
+			}
+
+			got := s.getNote(t, note.ID)
+			if got.Content != "kept" || *got.FolderID != 1 || got.Priority != 0 || got.Label != "" || got.DueDate != nil || !got.IsTodo {
+				t.Errorf("locked note changed: %+v", got)
+			}
+		})
+	}
+}
+
+func TestNoteService_TagRewriteSkipsLockedNotes(t *testing.T) {
+	tests := []struct {
+		name     string
+		rewrite  func(ctx context.Context, s *NoteService) (int, error)
+		wantTags string
+	}{
+		{"rename", func(ctx context.Context, s *NoteService) (int, error) {
+			return s.RenameTag(ctx, "keep", "other")
+		}, "other"},
+		{"delete", func(ctx context.Context, s *NoteService) (int, error) {
+			return s.DeleteTag(ctx, "keep")
+		}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			locked := s.createLocked(t)
+			open := s.createNote(t, &models.Note{Title: "open", Tags: "keep"})
+
+			changed, err := tt.rewrite(context.Background(), s.notes)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if changed != 1 {
+				t.Errorf("changed %d notes, want 1", changed)
+			}
+			if got := s.getNote(t, locked.ID).Tags; got != "keep" {
+				t.Errorf("locked note tags = %q, want %q", got, "keep")
+			}
+			if got := s.getNote(t, open.ID).Tags; got != tt.wantTags {
+				t.Errorf("open note tags = %q, want %q", got, tt.wantTags)
+			}
+		})
+	}
+}
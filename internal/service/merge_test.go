@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+func TestNoteService_Merge(t *testing.T) {
+	tests := []struct {
+		name        string
+		keepSources bool
+		order       []int
+		wantContent string
+		wantTags    string
+	}{
+		{
+			name:        "in the order given",
+			order:       []int{0, 1},
+			wantContent: "intro\n\n## first\n\none\n\n## second",
+			wantTags:    "shared,work,home",
+		},
+		{
+			name:        "reversed",
+			order:       []int{1, 0},
+			wantContent: "intro\n\n## second\n\n## first\n\none",
+			wantTags:    "shared,home,work",
+		},
+		{
+			name:        "keeping the sources",
+			keepSources: true,
+			order:       []int{0},
+			wantContent: "intro\n\n## first\n\none",
+			wantTags:    "shared,work",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestServices(t)
+			dst := s.createNote(t, &models.Note{Title: "target", Content: "intro\n", Tags: "shared"})
+			sources := []*models.Note{
+				s.createNote(t, &models.Note{Title: "first", Content: "\none\n\n", Tags: "work,shared"}),
+				s.createNote(t, &models.Note{Title: "second", Tags: "home"}),
+			}
+			var srcIDs []int64
+			for _, i := range tt.order {
+				srcIDs = append(srcIDs, sources[i].ID)
+			}
+
+			merged, err := s.notes.Merge(ctx, dst.ID, srcIDs, tt.keepSources)
+			if err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+			got := s.getNote(t, dst.ID)
+			if got.Content != tt.wantContent || merged.Content != tt.wantContent {
+				t.Errorf("content = %q, want %q", got.Content, tt.wantContent)
+			}
+			if got.Tags != tt.wantTags {
+				t.Errorf("tags = %q, want %q", got.Tags, tt.wantTags)
+			}
+			for _, id := range srcIDs {
+				_, err := s.notes.GetByID(ctx, id)
+				if tt.keepSources && err != nil {
+					t.Errorf("kept source %d: GetByID() error = %v", id, err)
+				}
+				if !tt.keepSources && err == nil {
+					t.Errorf("source %d still exists", id)
+				}
+			}
+		})
+	}
+}
+
+func TestNoteService_MergeRefuses(t *testing.T) {
+	tests := []struct {
+		name    string
+		srcIDs  func(dst, src *models.Note) []int64
+		wantErr error
+	}{
+		{"source listed twice", func(dst, src *models.Note) []int64 { return []int64{src.ID, src.ID} }, models.ErrValidation},
+		{"destination as source", func(dst, src *models.Note) []int64 { return []int64{dst.ID} }, models.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			dst := s.createNote(t, &models.Note{Title: "target", Content: "intro"})
+			src := s.createNote(t, &models.Note{Title: "source", Content: "body"})
+
+			_, err := s.notes.Merge(context.Background(), dst.ID, tt.srcIDs(dst, src), false)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Merge() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := s.getNote(t, dst.ID).Content; got != "intro" {
+				t.Errorf("content = %q, want it unchanged", got)
+			}
+			s.getNote(t, src.ID)
+		})
+	}
+}
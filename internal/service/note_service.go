@@ -2,53 +2,134 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/tranducquang/kiroku/internal/models"
 	"github.com/tranducquang/kiroku/internal/repository"
+	"github.com/tranducquang/kiroku/internal/secret"
 )
 
+// DailyNoteTitleFormat is the time layout used to title daily notes.
+const DailyNoteTitleFormat = "2006-01-02"
+
+// ScratchNoteTitle is the title of the always-available scratch note.
+const ScratchNoteTitle = "Scratch"
+
+// captureTimeFormat is the time layout prefixed to captured bullets.
+const captureTimeFormat = "15:04"
+
 // NoteService handles note business logic.
 // It depends on repository interfaces, not concrete types (DI principle).
 type NoteService struct {
 	noteRepo     repository.NoteRepositoryInterface
 	templateRepo repository.TemplateRepositoryInterface
 	folderRepo   repository.FolderRepositoryInterface
+	searchRepo   repository.SearchRepositoryInterface
+	maxContent   int
+	maxTitle     int
 }
 
 // NewNoteService creates a new note service with the given repositories.
+// A positive maxContent limits note content to that many bytes, and a
+// positive maxTitle limits titles to that many characters.
 func NewNoteService(
 	noteRepo repository.NoteRepositoryInterface,
 	templateRepo repository.TemplateRepositoryInterface,
 	folderRepo repository.FolderRepositoryInterface,
+	searchRepo repository.SearchRepositoryInterface,
+	maxContent int,
+	maxTitle int,
 ) *NoteService {
 	return &NoteService{
 		noteRepo:     noteRepo,
 		templateRepo: templateRepo,
 		folderRepo:   folderRepo,
+		searchRepo:   searchRepo,
+		maxContent:   maxContent,
+		maxTitle:     maxTitle,
 	}
 }
 
 // Create creates a new note.
 func (s *NoteService) Create(ctx context.Context, note *models.Note) error {
+	if err := s.prepareNew(ctx, note); err != nil {
+		return err
+	}
+	return s.noteRepo.Create(ctx, note)
+}
+
+// prepareNew validates a note about to be created and applies its
+// template's defaults.
+func (s *NoteService) prepareNew(ctx context.Context, note *models.Note) error {
 	if err := note.Validate(); err != nil {
 		return fmt.Errorf("validate note: %w", err)
 	}
+	if err := note.CheckTitleLength(s.maxTitle); err != nil {
+		return err
+	}
+
+	if note.TemplateID != nil {
+		template, err := s.templateRepo.GetByID(ctx, *note.TemplateID)
+		if err != nil {
+			return fmt.Errorf("get template: %w", err)
+		}
+		template.ApplyDefaults(note)
+	}
 
-	if note.TemplateID == nil {
-		return s.noteRepo.Create(ctx, note)
+	return note.CheckContentSize(s.maxContent)
+}
+
+// CreateOrGetByTitle returns the existing note titled note.Title, or
+// creates note when there is none. When several notes share the title the
+// most recently updated one is returned, so callers keyed by title (such
+// as daily notes) keep appending to the same note. Another writer creating
+// the same title at once does not lead to two notes.
+func (s *NoteService) CreateOrGetByTitle(ctx context.Context, note *models.Note) (*models.Note, error) {
+	existing, err := s.noteRepo.GetByTitle(ctx, note.Title)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
 	}
 
-	template, err := s.templateRepo.GetByID(ctx, *note.TemplateID)
+	if err := s.prepareNew(ctx, note); err != nil {
+		return nil, err
+	}
+	created, err := s.noteRepo.CreateIfTitleMissing(ctx, note)
 	if err != nil {
-		return fmt.Errorf("get template: %w", err)
+		return nil, err
+	}
+	if !created {
+		return s.noteRepo.GetByTitle(ctx, note.Title)
 	}
+	return note, nil
+}
 
-	if note.Content == "" {
-		note.Content = template.Content
+// GetByTitle retrieves the most recently updated note with the exact title.
+func (s *NoteService) GetByTitle(ctx context.Context, title string) (*models.Note, error) {
+	return s.noteRepo.GetByTitle(ctx, title)
+}
+
+// Backlinks returns the notes that link to note with [[Title]], most
+// recently updated first.
+func (s *NoteService) Backlinks(ctx context.Context, note *models.Note) ([]*models.Note, error) {
+	notes, err := s.GetAllNotes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list notes: %w", err)
 	}
 
-	return s.noteRepo.Create(ctx, note)
+	var linking []*models.Note
+	for _, n := range notes {
+		if n.ID != note.ID && n.LinksTo(note.Title) {
+			linking = append(linking, n)
+		}
+	}
+	return linking, nil
 }
 
 // GetByID retrieves a note by ID.
@@ -61,14 +142,52 @@ func (s *NoteService) Update(ctx context.Context, note *models.Note) error {
 	if err := note.Validate(); err != nil {
 		return fmt.Errorf("validate note: %w", err)
 	}
+	// Plain text must never be stored under the encrypted flag
+	if note.Encrypted && !secret.IsSealed(note.Content) {
+		return fmt.Errorf("%w: content of an encrypted note must be sealed", models.ErrValidation)
+	}
+	if err := note.CheckTitleLength(s.maxTitle); err != nil {
+		return err
+	}
+	if err := note.CheckContentSize(s.maxContent); err != nil {
+		return err
+	}
+
+	existing, err := s.noteRepo.GetByID(ctx, note.ID)
+	if err != nil {
+		return fmt.Errorf("get note: %w", err)
+	}
+	if existing.Locked {
+		return models.ErrLocked
+	}
+
 	return s.noteRepo.Update(ctx, note)
 }
 
 // Delete deletes a note by ID.
 func (s *NoteService) Delete(ctx context.Context, id int64) error {
+	note, err := s.noteRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get note: %w", err)
+	}
+	if note.Locked {
+		return models.ErrLocked
+	}
+
 	return s.noteRepo.Delete(ctx, id)
 }
 
+// ToggleLock locks or unlocks a note against edits and deletion.
+func (s *NoteService) ToggleLock(ctx context.Context, id int64) error {
+	note, err := s.noteRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get note: %w", err)
+	}
+
+	note.Locked = !note.Locked
+	return s.noteRepo.Update(ctx, note)
+}
+
 // GetAllNotes retrieves all notes ordered by updated_at descending.
 func (s *NoteService) GetAllNotes(ctx context.Context) ([]*models.Note, error) {
 	return s.noteRepo.List(ctx, models.ListOptions{
@@ -77,6 +196,11 @@ func (s *NoteService) GetAllNotes(ctx context.Context) ([]*models.Note, error) {
 	})
 }
 
+// List retrieves notes matching the options.
+func (s *NoteService) List(ctx context.Context, opts models.ListOptions) ([]*models.Note, error) {
+	return s.noteRepo.List(ctx, opts)
+}
+
 // GetByFolder retrieves notes in a specific folder.
 func (s *NoteService) GetByFolder(ctx context.Context, folderID int64) ([]*models.Note, error) {
 	return s.noteRepo.GetByFolder(ctx, folderID)
@@ -97,11 +221,65 @@ func (s *NoteService) GetStarred(ctx context.Context) ([]*models.Note, error) {
 	return s.noteRepo.GetStarred(ctx)
 }
 
+// GetUnfiled retrieves notes outside any folder, including those whose
+// folder has been deleted out from under them.
+func (s *NoteService) GetUnfiled(ctx context.Context) ([]*models.Note, error) {
+	return s.noteRepo.GetUnfiled(ctx)
+}
+
+// RepairFolderRefs moves notes whose folder no longer exists to the top
+// level and returns how many were repaired.
+func (s *NoteService) RepairFolderRefs(ctx context.Context) (int, error) {
+	n, err := s.noteRepo.ClearDanglingFolders(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("repair folder references: %w", err)
+	}
+	return n, nil
+}
+
+// ExportContent writes every note's content to markdown files in dir.
+func (s *NoteService) ExportContent(ctx context.Context, dir string) (int, error) {
+	n, err := s.noteRepo.ExportContent(ctx, repository.NewContentFiles(dir))
+	if err != nil {
+		return n, fmt.Errorf("export content: %w", err)
+	}
+	return n, nil
+}
+
+// ImportContent copies the markdown files in dir back into the database
+// and returns how many notes changed.
+func (s *NoteService) ImportContent(ctx context.Context, dir string) (int, error) {
+	n, err := s.noteRepo.ImportContent(ctx, repository.NewContentFiles(dir))
+	if err != nil {
+		return 0, fmt.Errorf("import content: %w", err)
+	}
+	return n, nil
+}
+
 // GetRecent retrieves the most recently updated notes.
 func (s *NoteService) GetRecent(ctx context.Context, limit int) ([]*models.Note, error) {
 	return s.noteRepo.GetRecent(ctx, limit)
 }
 
+// GetStale retrieves the notes last updated before cutoff, oldest first,
+// for review. Starred notes are kept on purpose, so they are left out
+// unless includeStarred is set.
+func (s *NoteService) GetStale(ctx context.Context, cutoff time.Time, includeStarred bool) ([]*models.Note, error) {
+	opts := models.ListOptions{
+		UpdatedUntil: &cutoff,
+		OrderBy:      "updated_at",
+	}
+	if !includeStarred {
+		starred := false
+		opts.Starred = &starred
+	}
+	notes, err := s.noteRepo.List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("list stale notes: %w", err)
+	}
+	return notes, nil
+}
+
 // ToggleStar toggles the starred status of a note.
 func (s *NoteService) ToggleStar(ctx context.Context, id int64) error {
 	note, err := s.noteRepo.GetByID(ctx, id)
@@ -123,22 +301,88 @@ func (s *NoteService) ToggleTodo(ctx context.Context, id int64) error {
 	if !note.IsTodo {
 		return fmt.Errorf("note is not a todo")
 	}
+	if note.Locked {
+		return models.ErrLocked
+	}
 
 	note.IsDone = !note.IsDone
 	return s.noteRepo.Update(ctx, note)
 }
 
+// MarkDone marks several todos done or open at once. Notes that are not
+// todos, are locked or are already in that state are left alone; it
+// returns how many changed.
+func (s *NoteService) MarkDone(ctx context.Context, ids []int64, done bool) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return s.noteRepo.SetDone(ctx, ids, done)
+}
+
+// SetStarred stars or unstars several notes at once and returns how many
+// changed.
+func (s *NoteService) SetStarred(ctx context.Context, ids []int64, starred bool) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return s.noteRepo.SetStarred(ctx, ids, starred)
+}
+
 // SetPriority sets the priority of a note.
 func (s *NoteService) SetPriority(ctx context.Context, id int64, priority int) error {
 	note, err := s.noteRepo.GetByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("get note: %w", err)
 	}
+	if note.Locked {
+		return models.ErrLocked
+	}
 
 	note.Priority = priority
 	return s.noteRepo.Update(ctx, note)
 }
 
+// SwapOrder swaps two notes in the manual todo order.
+func (s *NoteService) SwapOrder(ctx context.Context, id, otherID int64) error {
+	if err := s.noteRepo.SwapPositions(ctx, id, otherID); err != nil {
+		return fmt.Errorf("reorder notes: %w", err)
+	}
+	return nil
+}
+
+// SetLabel sets or, when label is empty, clears the color label of a note.
+func (s *NoteService) SetLabel(ctx context.Context, id int64, label string) error {
+	label, err := models.ParseLabel(label)
+	if err != nil {
+		return err
+	}
+
+	note, err := s.noteRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get note: %w", err)
+	}
+	if note.Locked {
+		return models.ErrLocked
+	}
+
+	note.Label = label
+	return s.noteRepo.Update(ctx, note)
+}
+
+// SetDueDate sets or, when due is nil, clears the due date of a note.
+func (s *NoteService) SetDueDate(ctx context.Context, id int64, due *time.Time) error {
+	note, err := s.noteRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get note: %w", err)
+	}
+	if note.Locked {
+		return models.ErrLocked
+	}
+
+	note.DueDate = due
+	return s.noteRepo.Update(ctx, note)
+}
+
 // MoveToFolder moves a note to a different folder.
 func (s *NoteService) MoveToFolder(ctx context.Context, noteID, folderID int64) error {
 	note, err := s.noteRepo.GetByID(ctx, noteID)
@@ -146,6 +390,10 @@ func (s *NoteService) MoveToFolder(ctx context.Context, noteID, folderID int64)
 		return fmt.Errorf("get note: %w", err)
 	}
 
+	if note.Locked {
+		return models.ErrLocked
+	}
+
 	if _, err := s.folderRepo.GetByID(ctx, folderID); err != nil {
 		return fmt.Errorf("get folder: %w", err)
 	}
@@ -154,7 +402,326 @@ func (s *NoteService) MoveToFolder(ctx context.Context, noteID, folderID int64)
 	return s.noteRepo.Update(ctx, note)
 }
 
+// GetToday retrieves open todos due today or earlier, followed by
+// the other notes updated since midnight.
+func (s *NoteService) GetToday(ctx context.Context) ([]*models.Note, error) {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	endOfDay := startOfDay.AddDate(0, 0, 1).Add(-time.Second)
+
+	isTodo, isDone := true, false
+	due, err := s.noteRepo.List(ctx, models.ListOptions{
+		IsTodo:    &isTodo,
+		IsDone:    &isDone,
+		DueBefore: &endOfDay,
+		OrderBy:   "due_date",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list due todos: %w", err)
+	}
+
+	updated, err := s.noteRepo.List(ctx, models.ListOptions{
+		UpdatedSince: &startOfDay,
+		OrderBy:      "updated_at",
+		OrderDesc:    true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list updated notes: %w", err)
+	}
+
+	seen := make(map[int64]bool, len(due))
+	for _, note := range due {
+		seen[note.ID] = true
+	}
+	notes := due
+	for _, note := range updated {
+		if !seen[note.ID] {
+			notes = append(notes, note)
+		}
+	}
+	return notes, nil
+}
+
+// ListByTags lists notes matching opts that carry every one of tags when
+// matchAll is set, or at least one of them otherwise. The first tag is
+// used as a database prefilter for all-matches; the limit applies after
+// tag filtering.
+func (s *NoteService) ListByTags(ctx context.Context, opts models.ListOptions, tags []string, matchAll bool) ([]*models.Note, error) {
+	tags = models.ParseTags(strings.Join(tags, ","))
+	if len(tags) == 0 {
+		return nil, models.ErrEmptyTag
+	}
+
+	limit := opts.Limit
+	opts.Limit, opts.Offset = 0, 0
+	if matchAll {
+		opts.Tag = tags[0]
+	}
+
+	notes, err := s.noteRepo.List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("list notes by tags: %w", err)
+	}
+
+	matched := notes[:0]
+	for _, note := range notes {
+		if (matchAll && note.HasAllTags(tags)) || (!matchAll && note.HasAnyTag(tags)) {
+			matched = append(matched, note)
+		}
+	}
+	if limit > 0 && len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, nil
+}
+
+// RenameTag replaces a tag on every note that carries it, merging into
+// the new tag where a note already has both. Locked notes are left as they
+// are. It returns how many notes changed.
+func (s *NoteService) RenameTag(ctx context.Context, oldTag, newTag string) (int, error) {
+	oldTag, newTag = models.NormalizeTag(oldTag), models.NormalizeTag(newTag)
+	if oldTag == "" || newTag == "" {
+		return 0, models.ErrEmptyTag
+	}
+	if oldTag == newTag {
+		return 0, nil
+	}
+
+	return s.rewriteTag(ctx, oldTag, func(tags []string) []string {
+		for i, tag := range tags {
+			if tag == oldTag {
+				tags[i] = newTag
+			}
+		}
+		return tags
+	})
+}
+
+// DeleteTag removes a tag from every unlocked note that carries it and
+// returns how many notes changed.
+func (s *NoteService) DeleteTag(ctx context.Context, tag string) (int, error) {
+	tag = models.NormalizeTag(tag)
+	if tag == "" {
+		return 0, models.ErrEmptyTag
+	}
+
+	return s.rewriteTag(ctx, tag, func(tags []string) []string {
+		kept := tags[:0]
+		for _, t := range tags {
+			if t != tag {
+				kept = append(kept, t)
+			}
+		}
+		return kept
+	})
+}
+
+// ListTags returns every tag in use with the number of notes carrying it,
+// most used first.
+func (s *NoteService) ListTags(ctx context.Context) ([]models.TagCount, error) {
+	tagStrings, err := s.noteRepo.ListTagStrings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, tags := range tagStrings {
+		for _, tag := range models.ParseTags(tags) {
+			counts[tag]++
+		}
+	}
+
+	result := make([]models.TagCount, 0, len(counts))
+	for name, count := range counts {
+		result = append(result, models.TagCount{Name: name, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// rewriteTag applies rewrite to the tag set of every note carrying tag
+// and saves the results in one transaction. Locked notes keep their tags.
+func (s *NoteService) rewriteTag(ctx context.Context, tag string, rewrite func(tags []string) []string) (int, error) {
+	notes, err := s.noteRepo.List(ctx, models.ListOptions{Tag: tag})
+	if err != nil {
+		return 0, fmt.Errorf("list tagged notes: %w", err)
+	}
+	updates := make(map[int64]string, len(notes))
+	for _, note := range notes {
+		if note.Locked {
+			continue
+		}
+		updates[note.ID] = models.FormatTags(rewrite(note.TagList()))
+	}
+	if len(updates) == 0 {
+		return 0, nil
+	}
+
+	if err := s.noteRepo.UpdateTags(ctx, updates); err != nil {
+		return 0, err
+	}
+	return len(updates), nil
+}
+
 // Count returns the total number of notes matching the options.
 func (s *NoteService) Count(ctx context.Context, opts models.ListOptions) (int, error) {
 	return s.noteRepo.Count(ctx, opts)
 }
+
+// Search performs a full-text search restricted to the notes matching
+// the folder, todo and starred filters in opts, ordered by rank.
+func (s *NoteService) Search(ctx context.Context, query string, opts models.ListOptions) ([]models.SearchResult, error) {
+	results, err := s.searchRepo.Search(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	return toSearchResults(results), nil
+}
+
+// Capture appends a timestamped bullet to today's daily note,
+// creating the daily note first if it does not exist yet.
+func (s *NoteService) Capture(ctx context.Context, text string) (*models.Note, error) {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return nil, fmt.Errorf("capture text cannot be empty")
+	}
+
+	now := time.Now()
+	note, err := s.getOrCreateDailyNote(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	bullet := fmt.Sprintf("- %s %s", now.Format(captureTimeFormat), text)
+	if err := s.AppendContent(ctx, note.ID, bullet); err != nil {
+		return nil, fmt.Errorf("append to daily note: %w", err)
+	}
+
+	return s.noteRepo.GetByID(ctx, note.ID)
+}
+
+// Scratch returns the scratch note, creating it empty if it does not
+// exist. A deleted scratch note is recreated on the next use.
+func (s *NoteService) Scratch(ctx context.Context) (*models.Note, error) {
+	note, err := s.CreateOrGetByTitle(ctx, &models.Note{Title: ScratchNoteTitle})
+	if err != nil {
+		return nil, fmt.Errorf("get scratch note: %w", err)
+	}
+	return note, nil
+}
+
+// AppendContent appends text to the end of a note's content on a new line.
+// Existing content is never rewritten, only extended.
+func (s *NoteService) AppendContent(ctx context.Context, id int64, text string) error {
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("append text cannot be empty")
+	}
+
+	note, err := s.noteRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get note: %w", err)
+	}
+	if note.Locked {
+		return models.ErrLocked
+	}
+	if note.Encrypted {
+		return models.ErrEncrypted
+	}
+
+	line := strings.TrimRight(text, "\n")
+	note.Content = appendLine(note.Content, line)
+	if err := note.CheckContentSize(s.maxContent); err != nil {
+		return err
+	}
+	return s.noteRepo.AppendContent(ctx, id, line)
+}
+
+// Merge appends the content of each source note to the destination under
+// a heading with the source's title, adds the sources' tags to its own,
+// and deletes the sources unless keepSources is set. It returns the
+// merged note.
+func (s *NoteService) Merge(ctx context.Context, dstID int64, srcIDs []int64, keepSources bool) (*models.Note, error) {
+	dst, err := s.noteRepo.GetByID(ctx, dstID)
+	if err != nil {
+		return nil, fmt.Errorf("get note: %w", err)
+	}
+	if dst.Locked {
+		return nil, models.ErrLocked
+	}
+	if dst.Encrypted {
+		return nil, models.ErrEncrypted
+	}
+
+	tags := dst.TagList()
+	var deleteIDs []int64
+	seen := map[int64]bool{dstID: true}
+	for _, id := range srcIDs {
+		if seen[id] {
+			return nil, fmt.Errorf("%w: note %d is listed twice", models.ErrValidation, id)
+		}
+		seen[id] = true
+
+		src, err := s.noteRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get note %d: %w", id, err)
+		}
+		if src.Locked && !keepSources {
+			return nil, fmt.Errorf("note %d: %w", id, models.ErrLocked)
+		}
+		if src.Encrypted {
+			return nil, fmt.Errorf("note %d: %w", id, models.ErrEncrypted)
+		}
+		dst.Content = mergeSection(dst.Content, src)
+		tags = append(tags, src.TagList()...)
+		if !keepSources {
+			deleteIDs = append(deleteIDs, id)
+		}
+	}
+	dst.SetTags(tags)
+	if err := dst.CheckContentSize(s.maxContent); err != nil {
+		return nil, err
+	}
+
+	if err := s.noteRepo.Merge(ctx, dst, deleteIDs); err != nil {
+		return nil, fmt.Errorf("merge notes: %w", err)
+	}
+	return dst, nil
+}
+
+// mergeSection appends a merged note to content as its own section,
+// headed by the note's title.
+func mergeSection(content string, src *models.Note) string {
+	section := "## " + src.Title
+	if body := strings.Trim(src.Content, "\n"); body != "" {
+		section += "\n\n" + body
+	}
+	trimmed := strings.TrimRight(content, "\n")
+	if trimmed == "" {
+		return section
+	}
+	return trimmed + "\n\n" + section
+}
+
+// getOrCreateDailyNote returns the daily note for the given day, creating it if missing.
+func (s *NoteService) getOrCreateDailyNote(ctx context.Context, day time.Time) (*models.Note, error) {
+	note, err := s.CreateOrGetByTitle(ctx, &models.Note{Title: day.Format(DailyNoteTitleFormat)})
+	if err != nil {
+		return nil, fmt.Errorf("get daily note: %w", err)
+	}
+	return note, nil
+}
+
+// appendLine appends line to content, making sure it starts on its own line.
+// Trailing blank lines in content are collapsed so repeated appends stay tight.
+func appendLine(content, line string) string {
+	trimmed := strings.TrimRight(content, "\n")
+	if trimmed == "" {
+		return line
+	}
+	return trimmed + "\n" + line
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"regexp"
+	"strings"
 
 	"github.com/tranducquang/kiroku/internal/models"
 	"github.com/tranducquang/kiroku/internal/repository"
@@ -20,14 +22,33 @@ func NewSearchService(searchRepo repository.SearchRepositoryInterface) *SearchSe
 	}
 }
 
-// Search performs a full-text search on notes.
+// Search performs a full-text search on notes. A term written "tag:work"
+// only matches the note's tags, and can be combined with other terms,
+// e.g. "tag:work meeting".
 func (s *SearchService) Search(ctx context.Context, query string, opts models.ListOptions) ([]models.SearchResult, error) {
-	results, err := s.searchRepo.Search(ctx, query, opts)
+	results, err := s.searchRepo.Search(ctx, ftsQuery(query), opts)
 	if err != nil {
 		return nil, err
 	}
+	return toSearchResults(results), nil
+}
+
+// tagField matches a "tag:" prefix, with an optional '#' on the tag, at
+// the start of a term
+var tagField = regexp.MustCompile(`(?i)(^|[\s(])tag:#?`)
+
+// ftsQuery rewrites "tag:" terms into the FTS5 filter on the tags column.
+// Quoted phrases are left alone.
+func ftsQuery(query string) string {
+	parts := strings.Split(query, `"`)
+	for i := 0; i < len(parts); i += 2 {
+		parts[i] = tagField.ReplaceAllString(parts[i], "${1}tags:")
+	}
+	return strings.Join(parts, `"`)
+}
 
-	// Convert repository.SearchResult to models.SearchResult
+// toSearchResults converts repository.SearchResult to models.SearchResult.
+func toSearchResults(results []repository.SearchResult) []models.SearchResult {
 	modelResults := make([]models.SearchResult, len(results))
 	for i, r := range results {
 		modelResults[i] = models.SearchResult{
@@ -36,8 +57,7 @@ func (s *SearchService) Search(ctx context.Context, query string, opts models.Li
 			Rank:    r.Rank,
 		}
 	}
-
-	return modelResults, nil
+	return modelResults
 }
 
 // SearchByTag searches notes by tag.
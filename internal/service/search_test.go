@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+// searchTitles runs query through the search service and returns the
+// titles it found, sorted
+func (s *testServices) searchTitles(t *testing.T, query string) []string {
+	t.Helper()
+	results, err := s.search.Search(context.Background(), query, models.ListOptions{})
+	if err != nil {
+		t.Fatalf("Search(%q) error = %v", query, err)
+	}
+	notes := make([]*models.Note, len(results))
+	for i := range results {
+		notes[i] = &results[i].Note
+	}
+	return noteTitles(notes)
+}
+
+func TestSearchService_Tags(t *testing.T) {
+	s := newTestServices(t)
+	s.createNote(t, &models.Note{Title: "sprint", Content: "meeting at ten", Tags: "work,planning"})
+	s.createNote(t, &models.Note{Title: "groceries", Content: "milk", Tags: "home"})
+	s.createNote(t, &models.Note{Title: "homework", Content: "work on the essay"})
+	s.createNote(t, &models.Note{Title: "retro", Content: "meeting notes", Tags: "team"})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"planning", []string{"sprint"}},
+		{"tag:work", []string{"sprint"}},
+		{"tag:#work", []string{"sprint"}},
+		{"TAG:Home", []string{"groceries"}},
+		{"tag:work meeting", []string{"sprint"}},
+		{"meeting", []string{"retro", "sprint"}},
+		{"tag:team OR tag:home", []string{"groceries", "retro"}},
+		{`"tag:work"`, []string{}},
+		{"tag:missing", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := s.searchTitles(t, tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchService_FollowsWrites(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	note := s.createNote(t, &models.Note{Title: "draft", Content: "alpha", Tags: "first"})
+
+	steps := []struct {
+		name   string
+		change func(t *testing.T)
+		want   map[string][]string // query to the titles it finds
+	}{
+		{"create", func(t *testing.T) {}, map[string][]string{
+			"alpha": {"draft"}, "draft": {"draft"}, "tag:first": {"draft"},
+		}},
+		{"edit content", func(t *testing.T) {
+			note.Content = "beta"
+			if err := s.notes.Update(ctx, note); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+		}, map[string][]string{
+			"alpha": {}, "beta": {"draft"},
+		}},
+		{"edit title and tags", func(t *testing.T) {
+			note.Title, note.Tags = "final", "second"
+			if err := s.notes.Update(ctx, note); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+		}, map[string][]string{
+			"draft": {}, "final": {"final"}, "tag:first": {}, "tag:second": {"final"},
+		}},
+		{"append", func(t *testing.T) {
+			if err := s.notes.AppendContent(ctx, note.ID, "gamma"); err != nil {
+				t.Fatalf("AppendContent() error = %v", err)
+			}
+		}, map[string][]string{
+			"beta": {"final"}, "gamma": {"final"},
+		}},
+		{"toggle star", func(t *testing.T) {
+			if err := s.notes.ToggleStar(ctx, note.ID); err != nil {
+				t.Fatalf("ToggleStar() error = %v", err)
+			}
+		}, map[string][]string{
+			"final": {"final"},
+		}},
+		{"delete", func(t *testing.T) {
+			if err := s.notes.Delete(ctx, note.ID); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+		}, map[string][]string{
+			"final": {}, "gamma": {}, "tag:second": {},
+		}},
+	}
+	// Each step builds on the last, so they run in order in one test
+	for _, step := range steps {
+		step.change(t)
+		for query, want := range step.want {
+			if got := s.searchTitles(t, query); !reflect.DeepEqual(got, want) {
+				t.Errorf("after %s: Search(%q) = %v, want %v", step.name, query, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/tranducquang/kiroku/internal/database"
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/repository"
+)
+
+// testServices wires the services to a migrated database in a temporary
+// directory, the same way the app does
+type testServices struct {
+	db      *database.DB
+	path    string
+	notes   *NoteService
+	folders *FolderService
+	search  *SearchService
+}
+
+// testMaxFolderDepth is the folder depth limit the test services enforce
+const testMaxFolderDepth = 3
+
+// testTitleMaxLength is the title length limit the test services enforce
+const testTitleMaxLength = 40
+
+func newTestServices(t *testing.T) *testServices {
+	t.Helper()
+	return newTestServicesWithFiles(t, nil)
+}
+
+// newTestServicesWithFiles is newTestServices with note content stored in
+// files, if set
+func newTestServicesWithFiles(t *testing.T, files *repository.ContentFiles) *testServices {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "kiroku.db")
+	db, err := database.New(path)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	noteRepo := repository.NewNoteRepository(db, files)
+	folderRepo := repository.NewFolderRepository(db)
+	templateRepo := repository.NewTemplateRepository(db)
+	searchRepo := repository.NewSearchRepository(db, files)
+	return &testServices{
+		db:      db,
+		path:    path,
+		notes:   NewNoteService(noteRepo, templateRepo, folderRepo, searchRepo, 0, testTitleMaxLength),
+		folders: NewFolderService(folderRepo, noteRepo, testMaxFolderDepth),
+		search:  NewSearchService(searchRepo),
+	}
+}
+
+// createNote creates a note or fails the test
+func (s *testServices) createNote(t *testing.T, note *models.Note) *models.Note {
+	t.Helper()
+	if err := s.notes.Create(context.Background(), note); err != nil {
+		t.Fatalf("Create(%q) error = %v", note.Title, err)
+	}
+	return note
+}
+
+// getNote loads a note or fails the test
+func (s *testServices) getNote(t *testing.T, id int64) *models.Note {
+	t.Helper()
+	note, err := s.notes.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetByID(%d) error = %v", id, err)
+	}
+	return note
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+// noteTitles returns the titles of notes, sorted
+func noteTitles(notes []*models.Note) []string {
+	titles := []string{}
+	for _, note := range notes {
+		titles = append(titles, note.Title)
+	}
+	sort.Strings(titles)
+	return titles
+}
+
+func TestNoteService_ListByTags(t *testing.T) {
+	s := newTestServices(t)
+	s.createNote(t, &models.Note{Title: "both", Tags: "work,urgent"})
+	s.createNote(t, &models.Note{Title: "work", Tags: "Work"})
+	s.createNote(t, &models.Note{Title: "urgent", Tags: "urgent,home"})
+	s.createNote(t, &models.Note{Title: "untagged"})
+
+	tests := []struct {
+		name     string
+		tags     []string
+		matchAll bool
+		limit    int
+		want     []string
+	}{
+		{"all of one", []string{"work"}, true, 0, []string{"both", "work"}},
+		{"all of two", []string{"work", "urgent"}, true, 0, []string{"both"}},
+		{"any of two", []string{"work", "urgent"}, false, 0, []string{"both", "urgent", "work"}},
+		{"all ignores case", []string{"WORK", "Urgent"}, true, 0, []string{"both"}},
+		{"any ignores case", []string{"HOME"}, false, 0, []string{"urgent"}},
+		{"comma separated", []string{"work,urgent"}, true, 0, []string{"both"}},
+		{"no match", []string{"missing"}, false, 0, []string{}},
+		{"limit after filtering", []string{"urgent"}, false, 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes, err := s.notes.ListByTags(context.Background(), models.ListOptions{Limit: tt.limit}, tt.tags, tt.matchAll)
+			if err != nil {
+				t.Fatalf("ListByTags() error = %v", err)
+			}
+			if tt.limit > 0 {
+				if len(notes) != tt.limit {
+					t.Errorf("got %d notes, want %d", len(notes), tt.limit)
+				}
+				return
+			}
+			if got := noteTitles(notes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoteService_ListByTags_NoTags(t *testing.T) {
+	s := newTestServices(t)
+	for _, tags := range [][]string{nil, {" ", ","}} {
+		if _, err := s.notes.ListByTags(context.Background(), models.ListOptions{}, tags, true); !errors.Is(err, models.ErrEmptyTag) {
+			t.Errorf("ListByTags(%q) error = %v, want ErrEmptyTag", tags, err)
+		}
+	}
+}
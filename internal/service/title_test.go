@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/repository"
+)
+
+// setUpdatedAt backdates a note so tests do not depend on the clock
+func (s *testServices) setUpdatedAt(t *testing.T, id int64, updatedAt string) {
+	t.Helper()
+	if _, err := s.db.Exec(`UPDATE notes SET updated_at = ? WHERE id = ?`, updatedAt, id); err != nil {
+		t.Fatalf("set updated_at: %v", err)
+	}
+}
+
+func TestNoteService_GetByTitle(t *testing.T) {
+	tests := []struct {
+		name    string
+		updated []string // updated_at of each note titled "Plan", in creation order
+		want    int      // index of the expected match, -1 for none
+	}{
+		{"no match", nil, -1},
+		{"single match", []string{"2026-01-01 10:00:00"}, 0},
+		{"most recently updated wins", []string{"2026-01-02 10:00:00", "2026-01-01 10:00:00", "2026-01-01 12:00:00"}, 0},
+		{"newest wins a tie", []string{"2026-01-01 10:00:00", "2026-01-01 10:00:00"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			s.createNote(t, &models.Note{Title: "Other"})
+			var ids []int64
+			for _, updatedAt := range tt.updated {
+				note := s.createNote(t, &models.Note{Title: "Plan"})
+				s.setUpdatedAt(t, note.ID, updatedAt)
+				ids = append(ids, note.ID)
+			}
+
+			got, err := s.notes.GetByTitle(context.Background(), "Plan")
+			if tt.want < 0 {
+				if !errors.Is(err, repository.ErrNotFound) {
+					t.Errorf("GetByTitle() error = %v, want ErrNotFound", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetByTitle() error = %v", err)
+			}
+			if got.ID != ids[tt.want] {
+				t.Errorf("got note %d, want %d", got.ID, ids[tt.want])
+			}
+		})
+	}
+}
+
+func TestNoteService_CreateOrGetByTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing int // notes already titled "Journal"
+		wantNew  bool
+	}{
+		{"no match creates", 0, true},
+		{"single match is reused", 1, false},
+		{"multiple matches reuse one", 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestServices(t)
+			for i := 0; i < tt.existing; i++ {
+				s.createNote(t, &models.Note{Title: "Journal"})
+			}
+
+			note, err := s.notes.CreateOrGetByTitle(ctx, &models.Note{Title: "Journal"})
+			if err != nil {
+				t.Fatalf("CreateOrGetByTitle() error = %v", err)
+			}
+			want, err := s.notes.GetByTitle(ctx, "Journal")
+			if err != nil {
+				t.Fatalf("GetByTitle() error = %v", err)
+			}
+			if note.ID != want.ID {
+				t.Errorf("got note %d, want the most recent match %d", note.ID, want.ID)
+			}
+			count, err := s.notes.Count(ctx, models.ListOptions{})
+			if err != nil {
+				t.Fatalf("Count() error = %v", err)
+			}
+			wantCount := tt.existing
+			if tt.wantNew {
+				wantCount++
+			}
+			if count != wantCount {
+				t.Errorf("got %d notes, want %d", count, wantCount)
+			}
+		})
+	}
+}
+
+func TestNoteService_TitleLength(t *testing.T) {
+	atLimit := strings.Repeat("é", testTitleMaxLength)
+	overLimit := atLimit + "x"
+	tests := []struct {
+		name    string
+		title   string
+		wantErr error
+	}{
+		{"under the limit", "short", nil},
+		{"at the limit", atLimit, nil},
+		{"over the limit", overLimit, models.ErrTitleTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestServices(t)
+
+			err := s.notes.Create(ctx, &models.Note{Title: tt.title})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+
+			note := s.createNote(t, &models.Note{Title: "before"})
+			note.Title = tt.title
+			err = s.notes.Update(ctx, note)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+			want := tt.title
+			if tt.wantErr != nil {
+				want = "before"
+			}
+			if got := s.getNote(t, note.ID).Title; got != want {
+				t.Errorf("stored title = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestNoteService_ImportTruncatesTitle(t *testing.T) {
+	atLimit := strings.Repeat("é", testTitleMaxLength)
+	tests := []struct {
+		name          string
+		title         string
+		wantTitle     string
+		wantTruncated bool
+	}{
+		{"at the limit", atLimit, atLimit, false},
+		{"over the limit", atLimit + "x", strings.Repeat("é", testTitleMaxLength-1) + "…", true},
+		{"trailing space cut", strings.Repeat("a", testTitleMaxLength-2) + "  tail", strings.Repeat("a", testTitleMaxLength-2) + "…", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			result, err := s.notes.Import(context.Background(), "# "+tt.title+"\n\nbody", "fallback", nil, ImportDuplicate)
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			if result.Note.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", result.Note.Title, tt.wantTitle)
+			}
+			if result.TitleTruncated != tt.wantTruncated {
+				t.Errorf("TitleTruncated = %v, want %v", result.TitleTruncated, tt.wantTruncated)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+// orphanNote creates a note in a folder, then deletes the folder behind
+// the repository's back, as an older version or another tool could
+func (s *testServices) orphanNote(t *testing.T, title string) *models.Note {
+	t.Helper()
+	folder := &models.Folder{Name: "doomed " + title}
+	if err := s.folders.Create(context.Background(), folder); err != nil {
+		t.Fatalf("Create folder error = %v", err)
+	}
+	note := s.createNote(t, &models.Note{Title: title, FolderID: &folder.ID})
+
+	// The pool holds a single connection, so the pragma applies to the delete
+	if _, err := s.db.Exec(`PRAGMA foreign_keys = OFF`); err != nil {
+		t.Fatalf("disable foreign keys: %v", err)
+	}
+	if _, err := s.db.Exec(`DELETE FROM folders WHERE id = ?`, folder.ID); err != nil {
+		t.Fatalf("delete folder: %v", err)
+	}
+	if _, err := s.db.Exec(`PRAGMA foreign_keys = ON`); err != nil {
+		t.Fatalf("enable foreign keys: %v", err)
+	}
+	return note
+}
+
+func TestNoteService_RepairFolderRefs(t *testing.T) {
+	tests := []struct {
+		name    string
+		orphans int
+	}{
+		{"nothing to repair", 0},
+		{"one orphan", 1},
+		{"several orphans", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newTestServices(t)
+			filedID := int64(1)
+			filed := s.createNote(t, &models.Note{Title: "filed", FolderID: &filedID})
+			var orphans []*models.Note
+			for i := 0; i < tt.orphans; i++ {
+				orphans = append(orphans, s.orphanNote(t, string(rune('a'+i))))
+			}
+
+			unfiled, err := s.notes.GetUnfiled(ctx)
+			if err != nil {
+				t.Fatalf("GetUnfiled() error = %v", err)
+			}
+			if len(unfiled) != tt.orphans {
+				t.Errorf("Unfiled lists %d notes before repair, want %d", len(unfiled), tt.orphans)
+			}
+
+			repaired, err := s.notes.RepairFolderRefs(ctx)
+			if err != nil {
+				t.Fatalf("RepairFolderRefs() error = %v", err)
+			}
+			if repaired != tt.orphans {
+				t.Errorf("repaired %d notes, want %d", repaired, tt.orphans)
+			}
+			for _, note := range orphans {
+				if got := s.getNote(t, note.ID); got.FolderID != nil {
+					t.Errorf("note %q still points at folder %d", got.Title, *got.FolderID)
+				}
+			}
+			if got := s.getNote(t, filed.ID); got.FolderID == nil || *got.FolderID != filedID {
+				t.Errorf("filed note moved to %v", got.FolderID)
+			}
+
+			again, err := s.notes.RepairFolderRefs(ctx)
+			if err != nil {
+				t.Fatalf("second RepairFolderRefs() error = %v", err)
+			}
+			if again != 0 {
+				t.Errorf("second repair changed %d notes, want 0", again)
+			}
+		})
+	}
+}
+
+func TestFolderService_Delete_UnfilesNotes(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	chain := s.createChain(t, "nested", 2)
+	inParent := s.createNote(t, &models.Note{Title: "in parent", FolderID: &chain[0]})
+	inChild := s.createNote(t, &models.Note{Title: "in child", FolderID: &chain[1]})
+
+	if err := s.folders.Delete(ctx, chain[0]); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	for _, note := range []*models.Note{inParent, inChild} {
+		if got := s.getNote(t, note.ID); got.FolderID != nil {
+			t.Errorf("note %q still points at folder %d", got.Title, *got.FolderID)
+		}
+	}
+	unfiled, err := s.notes.GetUnfiled(ctx)
+	if err != nil {
+		t.Fatalf("GetUnfiled() error = %v", err)
+	}
+	if len(unfiled) != 2 {
+		t.Errorf("Unfiled lists %d notes, want 2", len(unfiled))
+	}
+}
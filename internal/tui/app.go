@@ -1,9 +1,13 @@
 package tui
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -64,6 +68,58 @@ type App struct {
 	currentFilter string
 	currentNote   *models.Note
 	currentFolder *models.Folder
+	// hideDone leaves completed todos out of folder views
+	hideDone bool
+	// tagFilter narrows every view to the notes carrying it; empty when
+	// no tag filter is active
+	tagFilter string
+
+	// lastMoveFolder is the destination of the last successful move
+	lastMoveFolder *models.Folder
+	// pickerAction is the dialog type the folder picker was opened for
+	pickerAction string
+
+	history *undoHistory
+	nav     *navHistory
+
+	// unsaved holds quick field edits waiting for autosave; autosaveSeq
+	// identifies the latest scheduled save so earlier timers are ignored
+	unsaved     *unsavedEdit
+	autosaveSeq int
+
+	// previewSeq identifies the latest selection change waiting to be
+	// previewed, so earlier settle timers are ignored
+	previewSeq int
+
+	// unlocked holds the encrypted notes opened with U this session
+	unlocked map[int64]unlockedNote
+
+	// pendingEdit holds an edited copy of a note while its diff is
+	// reviewed; nil when no edit awaits confirmation
+	pendingEdit *models.Note
+
+	// completeIDs are the shown todos a complete-all confirmation applies
+	// to, captured when it was opened
+	completeIDs []int64
+
+	// deleteFolder is the folder a delete confirmation applies to,
+	// captured when it was opened
+	deleteFolder *models.Folder
+
+	// dialogNoteID is the note a delete, unlock or due-date dialog applies
+	// to, captured when it was opened
+	dialogNoteID int64
+
+	// sessionPath is where the view is saved for the next launch; empty
+	// when session restore is disabled. restore holds the saved view until
+	// folders are loaded, and restoreNoteID the note to reselect on the next
+	// load, after that or after a delete.
+	sessionPath   string
+	restore       *sessionState
+	restoreNoteID *int64
+	// openLast starts on the most recently updated note once folders are
+	// loaded, in place of the saved view
+	openLast bool
 
 	// Data
 	folders   []*models.Folder
@@ -78,15 +134,27 @@ type App struct {
 	searchBar *components.SearchBar
 	help      *components.Help
 	dialog    *components.Dialog
+	diff      *components.DiffView
+	picker    *components.FolderPicker
+	menu      *components.SelectMenu
+	toasts    *components.Toasts
 
 	// UI State
 	showHelp        bool
 	showDialog      bool
 	showPreview     bool
-	dialogType      string
 	searchMode      bool
 	searchQuery     string
 	editingTempFile string
+
+	// runner builds the commands that query the services, under a
+	// context stop cancels once the TUI exits
+	runner *commands.Runner
+	stop   context.CancelFunc
+
+	// crash is the first panic recovered in Update or View; once set the
+	// app quits on the next message
+	crash error
 }
 
 // NewApp creates a new TUI application with the given services.
@@ -100,8 +168,16 @@ func NewApp(
 ) *App {
 	noteList := components.NewNoteList()
 	noteList.SetFocused(true)
+	noteList.SetShowSnippets(cfg.UI.ListSnippet)
+
+	preview := components.NewPreview()
+	preview.SetRaw(!cfg.UI.PreviewRender)
+	preview.SetLargeThreshold(cfg.UI.PreviewLargeLines)
+	styles.UseASCIIIcons(cfg.UI.ASCIIIcons)
+	styles.UseFocusIndicator(cfg.UI.FocusIndicator)
+	ctx, stop := context.WithCancel(context.Background())
 
-	return &App{
+	a := &App{
 		noteService:     noteService,
 		folderService:   folderService,
 		templateService: templateService,
@@ -113,43 +189,110 @@ func NewApp(
 		currentFilter:   constants.FilterStarred,
 		sidebar:         components.NewSidebar(),
 		noteList:        noteList,
-		preview:         components.NewPreview(),
+		preview:         preview,
 		statusBar:       components.NewStatusBar(),
+		toasts:          components.NewToasts(),
 		searchBar:       components.NewSearchBar(),
 		help:            components.NewHelp(),
 		dialog:          components.NewDialog(),
+		diff:            components.NewDiffView(),
+		picker:          components.NewFolderPicker(),
+		menu:            components.NewSelectMenu(),
 		showPreview:     true,
+		hideDone:        cfg.UI.HideDoneInFolders,
+		history:         newUndoHistory(constants.UndoHistoryLimit),
+		nav:             newNavHistory(constants.NavHistoryLimit),
+		unlocked:        make(map[int64]unlockedNote),
+		openLast:        cfg.UI.OpenLastNote,
+		runner:          commands.NewRunner(ctx, cfg.Database.QueryTimeout),
+		stop:            stop,
+	}
+
+	if cfg.UI.RestoreSession {
+		a.sessionPath = filepath.Join(filepath.Dir(cfg.Database.Path), sessionFileName)
+		state, err := loadSession(a.sessionPath)
+		if err != nil {
+			logging.Warn().Err(err).Msg("Ignoring saved session")
+		} else {
+			a.restore = &state
+		}
+	}
+
+	return a
+}
+
+// Stop runs once the program has exited, however it quit: it writes any
+// edit still waiting for autosave, then cancels the database work of
+// commands still running so nothing outlives the program.
+func (a *App) Stop() {
+	if flush := a.flushEdits(); flush != nil {
+		if msg, ok := flush().(messages.ErrorMsg); ok {
+			logging.Error().Err(msg.Err).Msg("Failed to save pending edits on exit")
+		}
 	}
+	a.stop()
 }
 
 // Init initializes the application.
 func (a *App) Init() tea.Cmd {
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		a.loadData(),
-		tea.SetWindowTitle("記録 Kiroku"),
-	)
+		a.runner.CountUnfiled(a.noteService),
+		tea.SetWindowTitle(styles.Icons.AppTitle),
+	}
+	if a.sessionPath != "" {
+		cmds = append(cmds, commands.SaveSessionAfter(constants.SessionSaveInterval))
+	}
+	return tea.Batch(cmds...)
 }
 
 // loadData returns a command that loads initial data.
 func (a *App) loadData() tea.Cmd {
-	return commands.LoadData(commands.LoadDataParams{
+	return a.runner.LoadData(commands.LoadDataParams{
 		FolderService:   a.folderService,
 		NoteService:     a.noteService,
 		TemplateService: a.templateService,
+		Notes:           a.reloadParams(),
 	})
 }
 
+// discardEditFile removes the editor's temporary file without reading it
+// back. It may hold the plain text of an encrypted note, so it must not
+// be left behind.
+func (a *App) discardEditFile() {
+	if a.editingTempFile == "" {
+		return
+	}
+	if err := os.Remove(a.editingTempFile); err != nil && !os.IsNotExist(err) {
+		logging.Warn().Err(err).Str("temp_file", a.editingTempFile).Msg("Failed to remove editor temp file")
+	}
+	a.editingTempFile = ""
+}
+
 // Update handles messages.
-func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (a *App) Update(msg tea.Msg) (model tea.Model, cmd tea.Cmd) {
+	defer a.recoverUpdate(&model, &cmd)
+	if a.crash != nil {
+		return a, tea.Quit
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		return a.handleWindowResize(msg)
 	case messages.DataLoadedMsg:
 		return a.handleDataLoaded(msg)
+	case messages.DialogResultMsg:
+		return a.handleDialogResult(msg)
 	case messages.ErrorMsg:
 		return a.handleError(msg)
 	case messages.StatusClearMsg:
 		return a.handleStatusClear()
+	case messages.ToastExpiredMsg:
+		a.toasts.Dismiss(msg.ID)
+		return a, nil
+	case messages.SessionSaveMsg:
+		a.persistSession()
+		return a, commands.SaveSessionAfter(constants.SessionSaveInterval)
 	case messages.EditorFinishedMsg:
 		return a.handleEditorFinished(msg)
 	case messages.NoteCreatedMsg:
@@ -157,7 +300,47 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.NoteDeletedMsg:
 		return a.handleNoteDeleted(msg)
 	case messages.NoteUpdatedMsg:
-		return a.handleNoteUpdated()
+		return a.handleNoteUpdated(msg)
+	case messages.EncryptedNoteSavedMsg:
+		return a.handleEncryptedNoteSaved(msg)
+	case messages.NoteUnlockedMsg:
+		return a.handleNoteUnlocked(msg)
+	case messages.PreviewSettleMsg:
+		if msg.Seq == a.previewSeq {
+			a.updatePreview()
+		}
+		return a, nil
+	case messages.AutosaveMsg:
+		if msg.Seq != a.autosaveSeq {
+			return a, nil
+		}
+		return a, a.flushEdits()
+	case messages.NoteRestoredMsg:
+		return a.handleNoteRestored(msg)
+	case messages.ClipboardCopiedMsg:
+		return a, a.notify(components.ToastSuccess, fmt.Sprintf("Copied %s to clipboard", msg.What))
+	case messages.NoteCapturedMsg:
+		return a.handleNoteCaptured(msg)
+	case messages.TodoReorderedMsg:
+		// Keep the moved todo selected at its new place
+		a.restoreNoteID = &msg.NoteID
+		return a, a.reloadNotes()
+	case messages.TodosCompletedMsg:
+		return a, tea.Batch(
+			a.reloadNotes(),
+			a.notify(components.ToastSuccess, fmt.Sprintf("Completed %d todo(s)", msg.Count)),
+		)
+	case messages.ScratchLoadedMsg:
+		return a.handleScratchLoaded(msg)
+	case messages.LastNoteLoadedMsg:
+		return a.handleLastNoteLoaded(msg)
+	case messages.NoteMovedMsg:
+		return a.handleNoteMoved(msg)
+	case messages.FolderNoteCountMsg:
+		return a.handleFolderNoteCount(msg)
+	case messages.UnfiledCountMsg:
+		a.sidebar.SetUnfiledCount(msg.Count)
+		return a, nil
 	case messages.SearchResultsMsg:
 		return a.handleSearchResults(msg)
 	case tea.KeyMsg:
@@ -188,23 +371,19 @@ func (a *App) handleDataLoaded(msg messages.DataLoadedMsg) (tea.Model, tea.Cmd)
 	if msg.Folders != nil {
 		a.folders = msg.Folders
 		a.sidebar.SetFolders(a.folders)
+		if a.lastMoveFolder != nil && findFolder(a.folders, a.lastMoveFolder.ID) == nil {
+			a.lastMoveFolder = nil
+		}
 	}
 
 	// Always update notes, treating nil as empty list
 	a.notes = msg.Notes
 
-	// If we are in Starred filter but got all notes (e.g. from LoadData), filter them
-	if a.currentFilter == constants.FilterStarred {
-		var starred []*models.Note
-		for _, n := range a.notes {
-			if n.Starred {
-				starred = append(starred, n)
-			}
-		}
-		a.notes = starred
-	}
-
 	a.noteList.SetNotes(a.notes)
+	if a.restoreNoteID != nil {
+		a.noteList.SelectNote(*a.restoreNoteID)
+		a.restoreNoteID = nil
+	}
 
 	if msg.Templates != nil {
 		a.templates = msg.Templates
@@ -219,14 +398,34 @@ func (a *App) handleDataLoaded(msg messages.DataLoadedMsg) (tea.Model, tea.Cmd)
 	}
 
 	a.updatePreview()
+
+	if a.openLast && msg.Folders != nil {
+		a.openLast = false
+		a.restore = nil
+		return a, a.runner.LoadLastNote(a.noteService)
+	}
+	if a.restore != nil && msg.Folders != nil {
+		return a, a.restoreSession()
+	}
 	return a, nil
 }
 
 // handleError handles error events.
 func (a *App) handleError(msg messages.ErrorMsg) (tea.Model, tea.Cmd) {
 	logging.Error().Err(msg.Err).Str("context", msg.Context).Msg("TUI error")
-	a.statusBar.SetMessage(fmt.Sprintf("Error: %v", msg))
-	return a, commands.ClearStatusAfter(constants.ErrorMessageDuration)
+	if msg.IsNoteLoad() {
+		a.noteList.SetError(msg.Err)
+	}
+	return a, a.notify(components.ToastError, fmt.Sprintf("Error: %v", msg))
+}
+
+// notify shows a toast and schedules its dismissal. Errors stay up longer.
+func (a *App) notify(kind components.ToastKind, text string) tea.Cmd {
+	d := constants.StatusMessageDuration
+	if kind == components.ToastError {
+		d = constants.ErrorMessageDuration
+	}
+	return commands.DismissToastAfter(a.toasts.Push(kind, text), d)
 }
 
 // handleStatusClear handles status clear events.
@@ -242,52 +441,200 @@ func (a *App) handleEditorFinished(msg messages.EditorFinishedMsg) (tea.Model, t
 	// Check if editor process returned an error
 	if msg.Err != nil {
 		logging.Error().Err(msg.Err).Msg("Editor process failed")
-		a.statusBar.SetMessage(fmt.Sprintf("Editor failed: %v", msg.Err))
-		a.editingTempFile = ""
-		return a, commands.ClearStatusAfter(constants.ErrorMessageDuration)
+		a.discardEditFile()
+		return a, a.notify(components.ToastError, fmt.Sprintf("Editor failed: %v", msg.Err))
 	}
 
 	if a.currentNote == nil || a.editingTempFile == "" {
 		logging.Warn().Msg("No note or temp file to process")
+		a.discardEditFile()
 		return a, nil
 	}
 
 	newTitle, newContent, err := a.editorService.ReadEditedContent(a.editingTempFile, a.currentNote.Title)
 	if err != nil {
 		logging.Error().Err(err).Msg("Failed to read edited content")
-		a.statusBar.SetMessage(fmt.Sprintf("Error: %v", err))
 		a.editingTempFile = ""
-		return a, commands.ClearStatusAfter(constants.ErrorMessageDuration)
+		return a, a.notify(components.ToastError, fmt.Sprintf("Error: %v", err))
+	}
+
+	a.editingTempFile = ""
+
+	if !a.currentNote.HasChanges(newTitle, newContent) {
+		logging.Debug().Int64("note_id", a.currentNote.ID).Msg("Editor closed without changes")
+		return a, a.notify(components.ToastInfo, "No changes")
+	}
+
+	if a.cfg.Editor.ConfirmChanges {
+		edited := *a.currentNote
+		edited.Title = newTitle
+		edited.Content = newContent
+		a.pendingEdit = &edited
+		a.diff.Show(newTitle, editedText(a.currentNote.Title, a.currentNote.Content), editedText(newTitle, newContent))
+		return a, nil
 	}
 
 	a.currentNote.Title = newTitle
 	a.currentNote.Content = newContent
-	a.editingTempFile = ""
 
 	return a, tea.Batch(
-		commands.UpdateNote(a.noteService, a.currentNote),
+		a.saveEdit(a.currentNote),
 		a.reloadNotes(),
 	)
 }
 
+// editedText lays a note out as the editor shows it, so the diff covers
+// title changes too.
+func editedText(title, content string) string {
+	return "# " + title + "\n\n" + content
+}
+
+// handleDiffInput handles input while an edit's diff is being reviewed.
+// Enter saves the edit; Esc or q discards it, leaving the note untouched.
+func (a *App) handleDiffInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.DefaultKeyMap.Enter):
+		edited := a.pendingEdit
+		a.pendingEdit = nil
+		a.diff.Hide()
+		a.currentNote = edited
+		return a, tea.Batch(
+			a.saveEdit(edited),
+			a.reloadNotes(),
+		)
+
+	case key.Matches(msg, keys.DefaultKeyMap.Escape), msg.String() == "q":
+		logging.Debug().Int64("note_id", a.pendingEdit.ID).Msg("Discarded edit after review")
+		a.pendingEdit = nil
+		a.diff.Hide()
+		return a, a.notify(components.ToastInfo, "Changes discarded")
+
+	case key.Matches(msg, keys.DefaultKeyMap.Up):
+		a.diff.ScrollUp(1)
+	case key.Matches(msg, keys.DefaultKeyMap.Down):
+		a.diff.ScrollDown(1)
+	case key.Matches(msg, keys.DefaultKeyMap.PageUp):
+		a.diff.ScrollUp(a.diff.PageSize())
+	case key.Matches(msg, keys.DefaultKeyMap.PageDown):
+		a.diff.ScrollDown(a.diff.PageSize())
+	}
+	return a, nil
+}
+
+// handlePickerInput handles input while the folder picker is open. Enter
+// applies the picked folder to the action the picker was opened for.
+func (a *App) handlePickerInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.DefaultKeyMap.Escape):
+		a.picker.Hide()
+		return a, nil
+
+	case key.Matches(msg, keys.DefaultKeyMap.Enter):
+		folder := a.picker.Selected()
+		if folder == nil {
+			return a, nil
+		}
+		a.picker.Hide()
+		switch a.pickerAction {
+		case constants.DialogTypeMove:
+			if a.currentNote != nil {
+				return a, a.runner.MoveNote(a.noteService, a.currentNote.ID, folder)
+			}
+		case constants.DialogTypeGoToFolder:
+			return a, a.goToFolder(folder)
+		}
+		return a, nil
+	}
+
+	var cmd tea.Cmd
+	a.picker, cmd = a.picker.Update(msg)
+	return a, cmd
+}
+
+// handlePriorityMenuInput handles input while the priority menu is open.
+// Enter or a number key sets the chosen priority on the current note.
+func (a *App) handlePriorityMenuInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.DefaultKeyMap.Escape):
+		a.menu.Hide()
+		return a, nil
+	case key.Matches(msg, keys.DefaultKeyMap.Enter):
+		return a, a.applyPriority(a.menu.Selected())
+	}
+
+	var chosen bool
+	a.menu, chosen = a.menu.Update(msg)
+	if chosen {
+		return a, a.applyPriority(a.menu.Selected())
+	}
+	return a, nil
+}
+
+// showPriorityMenu opens the priority menu on the note's current priority
+func (a *App) showPriorityMenu(note *models.Note) {
+	options := make([]components.SelectOption, constants.PriorityMax)
+	for p := range options {
+		options[p] = components.SelectOption{
+			Label:  (&models.Note{Priority: p}).PriorityString(),
+			Marker: styles.RenderPriority(p),
+		}
+	}
+	a.menu.Show(fmt.Sprintf("Priority of '%s'", note.Title), options, note.Priority)
+}
+
+// applyPriority closes the priority menu and sets the current note's
+// priority, through autosave like the other field edits
+func (a *App) applyPriority(priority int) tea.Cmd {
+	a.menu.Hide()
+	note := a.currentNote
+	if note == nil || note.Priority == priority {
+		return nil
+	}
+	logging.Debug().Int64("note_id", note.ID).Int("priority", priority).Msg("Picked priority")
+	return a.editFields(note, func(n *models.Note) {
+		n.Priority = priority
+	}, a.runner.SetPriority(a.noteService, note.ID, priority))
+}
+
+// goToFolder opens a folder, expanding its ancestors in the sidebar
+func (a *App) goToFolder(folder *models.Folder) tea.Cmd {
+	logging.Debug().Int64("folder_id", folder.ID).Str("folder_name", folder.Name).Msg("Jumped to folder")
+	a.sidebar.SelectFolder(folder.ID)
+	if a.currentFolder != nil && a.currentFolder.ID == folder.ID {
+		return nil
+	}
+	a.rememberView()
+	a.currentFolder = folder
+	a.currentFilter = ""
+	a.notes = nil
+	a.noteList.SetNotes(nil)
+	return a.flushThen(a.reloadNotes())
+}
+
 // handleNoteCreated handles note created events.
 func (a *App) handleNoteCreated(msg messages.NoteCreatedMsg) (tea.Model, tea.Cmd) {
+	a.history.record("create", msg.NoteChange)
 	a.showDialog = false
-	a.statusBar.SetMessage(fmt.Sprintf("Created: %s", msg.Note.Title))
+	reload := a.reloadNotes()
+	// A note filed into a just-created inbox folder needs the sidebar
+	// refreshed; load first so the filtered reload lands last
+	if msg.Note.FolderID != nil && findFolder(a.folders, *msg.Note.FolderID) == nil {
+		reload = tea.Sequence(a.loadData(), reload)
+	}
 	return a, tea.Batch(
-		a.reloadNotes(),
-		commands.ClearStatusAfter(constants.StatusMessageDuration),
+		reload,
+		a.notify(components.ToastSuccess, fmt.Sprintf("Created: %s", msg.Note.Title)),
 	)
 }
 
 // handleNoteDeleted handles note deleted events.
 func (a *App) handleNoteDeleted(msg messages.NoteDeletedMsg) (tea.Model, tea.Cmd) {
+	a.history.record("delete", msg.NoteChange)
 	a.showDialog = false
-	a.statusBar.SetMessage("Note deleted")
 
-	// Explicitly clear state to ensure immediate visual feedback
-	a.currentNote = nil
-	a.preview.SetNote(nil)
+	// Keep working near the deleted note: select the one after it, or the
+	// one before when it was last, and keep it selected through the reload
+	next := a.noteList.Neighbor(msg.NoteID)
 
 	// Remove from local list immediately
 	var newNotes []*models.Note
@@ -298,28 +645,114 @@ func (a *App) handleNoteDeleted(msg messages.NoteDeletedMsg) (tea.Model, tea.Cmd
 	}
 	a.notes = newNotes
 	a.noteList.SetNotes(a.notes)
+	if next != nil {
+		a.noteList.SelectNote(next.ID)
+		a.restoreNoteID = &next.ID
+	}
+	a.updatePreview()
 
 	return a, tea.Batch(
 		a.reloadNotes(),
-		commands.ClearStatusAfter(constants.StatusMessageDuration),
+		a.notify(components.ToastSuccess, "Note deleted"),
 	)
 }
 
 // handleNoteUpdated handles note updated events.
-func (a *App) handleNoteUpdated() (tea.Model, tea.Cmd) {
-	a.statusBar.SetMessage("Note saved")
+func (a *App) handleNoteUpdated(msg messages.NoteUpdatedMsg) (tea.Model, tea.Cmd) {
+	a.history.record("update", msg.NoteChange)
 	return a, tea.Batch(
 		a.reloadNotes(),
-		commands.ClearStatusAfter(constants.StatusMessageDuration),
+		a.notify(components.ToastSuccess, "Note saved"),
 	)
 }
 
+// handleScratchLoaded shows the scratch note under All notes, or opens it
+// in the editor when it is already the selected note.
+func (a *App) handleScratchLoaded(msg messages.ScratchLoadedMsg) (tea.Model, tea.Cmd) {
+	if a.currentNote != nil && a.currentNote.ID == msg.Note.ID {
+		return a.editNote(a.currentNote)
+	}
+
+	logging.Debug().Int64("note_id", msg.Note.ID).Msg("Jumping to scratch note")
+	a.rememberView()
+	id := msg.Note.ID
+	cmd, _ := a.showView(sessionState{Filter: constants.FilterAll, NoteID: &id})
+	a.currentPanel = PanelNoteList
+	a.noteList.SetFocused(true)
+	a.sidebar.SetFocused(false)
+	return a, a.flushThen(cmd)
+}
+
+// handleLastNoteLoaded shows the most recently updated note under All
+// notes on launch, and opens it in the editor with ui.resume_editing. With
+// no notes yet the start view stays as it is.
+func (a *App) handleLastNoteLoaded(msg messages.LastNoteLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.Note == nil {
+		logging.Debug().Msg("No notes to open on launch")
+		return a, nil
+	}
+
+	logging.Debug().Int64("note_id", msg.Note.ID).Msg("Opening last note on launch")
+	id := msg.Note.ID
+	cmd, _ := a.showView(sessionState{Filter: constants.FilterAll, NoteID: &id})
+	if !a.cfg.UI.ResumeEditing || msg.Note.Locked {
+		return a, cmd
+	}
+	_, edit := a.editNote(msg.Note)
+	return a, tea.Batch(cmd, edit)
+}
+
+// handleNoteCaptured handles quick capture events.
+func (a *App) handleNoteCaptured(msg messages.NoteCapturedMsg) (tea.Model, tea.Cmd) {
+	return a, tea.Batch(
+		a.reloadNotes(),
+		a.notify(components.ToastSuccess, fmt.Sprintf("Captured to: %s", msg.Note.Title)),
+	)
+}
+
+// handleNoteMoved handles note moved events.
+func (a *App) handleNoteMoved(msg messages.NoteMovedMsg) (tea.Model, tea.Cmd) {
+	a.history.record("move", msg.NoteChange)
+	a.lastMoveFolder = msg.Folder
+	return a, tea.Batch(
+		a.reloadNotes(),
+		a.runner.ReloadFolders(a.folderService),
+		a.notify(components.ToastSuccess, fmt.Sprintf("Moved to: %s", msg.Folder.Name)),
+	)
+}
+
+// handleNoteRestored handles undo and redo results.
+func (a *App) handleNoteRestored(msg messages.NoteRestoredMsg) (tea.Model, tea.Cmd) {
+	if msg.Note != nil && msg.Note.ID != msg.OldID {
+		a.history.remapID(msg.OldID, msg.Note.ID)
+	}
+	return a, tea.Batch(
+		a.reloadNotes(),
+		a.runner.ReloadFolders(a.folderService),
+		a.notify(components.ToastInfo, msg.Label),
+	)
+}
+
+// handleFolderNoteCount asks for the confirmation that matches how many
+// notes a folder deletion affects.
+func (a *App) handleFolderNoteCount(msg messages.FolderNoteCountMsg) (tea.Model, tea.Cmd) {
+	if msg.Count > a.cfg.UI.ConfirmDeleteThreshold {
+		a.showTypedDeleteFolderConfirm(msg.Folder, msg.Count)
+		return a, nil
+	}
+	a.showDeleteFolderConfirm(msg.Folder)
+	return a, nil
+}
+
 // handleSearchResults handles search results.
 func (a *App) handleSearchResults(msg messages.SearchResultsMsg) (tea.Model, tea.Cmd) {
 	a.noteList.SetFolderName(fmt.Sprintf("Search: %s", msg.Query))
+	a.noteList.SetGroupCompleted(false)
+	a.noteList.SetSectionFunc(nil)
 	a.notes = msg.Notes
 	a.noteList.SetNotes(a.notes)
 	a.noteList.ResetCursor()
+	a.preview.SetHighlight(msg.Query)
 	a.updatePreview()
 	return a, nil
 }
@@ -338,6 +771,15 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if a.showHelp {
 		return a.handleHelpInput(msg)
 	}
+	if a.diff.IsVisible() {
+		return a.handleDiffInput(msg)
+	}
+	if a.picker.IsVisible() {
+		return a.handlePickerInput(msg)
+	}
+	if a.menu.IsVisible() {
+		return a.handlePriorityMenuInput(msg)
+	}
 	if a.showDialog {
 		return a.handleDialogInput(msg)
 	}
@@ -359,7 +801,8 @@ func (a *App) handleGlobalKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
 	switch {
 	case key.Matches(msg, keys.DefaultKeyMap.Quit):
 		logging.Info().Msg("User quit application")
-		return true, tea.Quit
+		a.persistSession()
+		return true, a.flushThen(tea.Quit)
 
 	case key.Matches(msg, keys.DefaultKeyMap.Help):
 		logging.Debug().Msg("Showing help")
@@ -367,6 +810,28 @@ func (a *App) handleGlobalKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
 		a.help.Show()
 		return true, nil
 
+	case key.Matches(msg, keys.DefaultKeyMap.Back):
+		return true, a.navigate(true)
+
+	case key.Matches(msg, keys.DefaultKeyMap.Forward):
+		return true, a.navigate(false)
+
+	case key.Matches(msg, keys.DefaultKeyMap.Scratch):
+		return true, a.runner.OpenScratch(a.noteService)
+
+	case key.Matches(msg, keys.DefaultKeyMap.GoTo):
+		if len(a.folders) == 0 {
+			return true, a.notify(components.ToastInfo, "No folders to go to")
+		}
+		logging.Debug().Msg("Showing folder jump")
+		var current *int64
+		if a.currentFolder != nil {
+			current = &a.currentFolder.ID
+		}
+		a.picker.Show("Go to folder", a.folders, current)
+		a.pickerAction = constants.DialogTypeGoToFolder
+		return true, nil
+
 	case key.Matches(msg, keys.DefaultKeyMap.Search):
 		logging.Debug().Msg("Entering search mode")
 		a.searchMode = true
@@ -380,6 +845,11 @@ func (a *App) handleGlobalKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
 		a.updateLayout()
 		return true, nil
 
+	case key.Matches(msg, keys.DefaultKeyMap.ToggleRender):
+		logging.Debug().Bool("raw", !a.preview.Raw()).Msg("Toggling preview rendering")
+		a.preview.ToggleRaw()
+		return true, nil
+
 	case key.Matches(msg, keys.DefaultKeyMap.NewNote):
 		logging.Debug().Msg("Showing new note dialog")
 		a.showNewNoteDialog()
@@ -395,6 +865,11 @@ func (a *App) handleGlobalKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
 		a.showNewFolderDialog()
 		return true, nil
 
+	case key.Matches(msg, keys.DefaultKeyMap.Capture):
+		logging.Debug().Msg("Showing quick capture dialog")
+		a.showCaptureDialog()
+		return true, nil
+
 	case key.Matches(msg, keys.DefaultKeyMap.Tab), key.Matches(msg, keys.DefaultKeyMap.Right):
 		logging.Debug().Msg("Switching panel right")
 		a.switchPanel(1)
@@ -405,6 +880,37 @@ func (a *App) handleGlobalKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
 		a.switchPanel(-1)
 		return true, nil
 
+	case key.Matches(msg, keys.DefaultKeyMap.HideDone):
+		a.hideDone = !a.hideDone
+		logging.Debug().Bool("hide_done", a.hideDone).Msg("Toggling done todos in folders")
+		text := "Showing done todos in folders"
+		if a.hideDone {
+			text = "Hiding done todos in folders"
+		}
+		return true, tea.Batch(a.reloadNotes(), a.notify(components.ToastInfo, text))
+
+	case key.Matches(msg, keys.DefaultKeyMap.FilterTag):
+		logging.Debug().Str("tag", a.tagFilter).Msg("Showing tag filter dialog")
+		a.showTagFilterDialog()
+		return true, nil
+
+	case key.Matches(msg, keys.DefaultKeyMap.Undo):
+		return true, a.undo()
+
+	case key.Matches(msg, keys.DefaultKeyMap.Redo):
+		return true, a.redo()
+
+	case key.Matches(msg, keys.DefaultKeyMap.Refresh):
+		logging.Debug().Msg("Refreshing data")
+		a.history.clear()
+		return true, tea.Batch(a.loadData(), a.reloadNotes())
+
+	case key.Matches(msg, keys.DefaultKeyMap.SidebarShrink):
+		return true, a.resizeSidebar(-constants.SidebarResizeStep)
+
+	case key.Matches(msg, keys.DefaultKeyMap.SidebarGrow):
+		return true, a.resizeSidebar(constants.SidebarResizeStep)
+
 	default:
 		return false, nil
 	}
@@ -421,40 +927,45 @@ func (a *App) handleHelpInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleDialogInput handles input when dialog is visible.
 func (a *App) handleDialogInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	logging.Debug().Str("dialog_type", a.dialogType).Msg("Handling dialog input")
-	a.dialog, _ = a.dialog.Update(msg)
-
-	if a.dialog.IsVisible() {
-		return a, nil
+	logging.Debug().Msg("Handling dialog input")
+	var cmd tea.Cmd
+	a.dialog, cmd = a.dialog.Update(msg)
+	if !a.dialog.IsVisible() {
+		a.showDialog = false
 	}
+	return a, cmd
+}
 
-	if !a.dialog.IsConfirmed() {
-		a.showDialog = false
+// handleDialogResult acts on the answer to a closed dialog.
+func (a *App) handleDialogResult(msg messages.DialogResultMsg) (tea.Model, tea.Cmd) {
+	logging.Debug().Str("dialog_type", msg.Type).Bool("confirmed", msg.Confirmed).Msg("Dialog closed")
+	if !msg.Confirmed {
 		return a, nil
 	}
 
-	a.showDialog = false
-
-	switch a.dialogType {
+	switch msg.Type {
 	case constants.DialogTypeNewNote:
-		return a, commands.CreateNote(commands.CreateNoteParams{
-			NoteService:   a.noteService,
-			Title:         a.dialog.InputValue(),
-			IsTodo:        false,
-			CurrentFolder: a.currentFolder,
-		})
+		return a, a.runner.CreateNote(a.createNoteParams(msg.Value, false))
 
 	case constants.DialogTypeNewTodo:
-		return a, commands.CreateNote(commands.CreateNoteParams{
-			NoteService:   a.noteService,
-			Title:         a.dialog.InputValue(),
-			IsTodo:        true,
-			CurrentFolder: a.currentFolder,
-		})
+		return a, a.runner.CreateNote(a.createNoteParams(msg.Value, true))
+
+	case constants.DialogTypeFilterTag:
+		a.tagFilter = models.NormalizeTag(msg.Value)
+		logging.Debug().Str("tag", a.tagFilter).Msg("Tag filter changed")
+		text := "Showing every tag"
+		if a.tagFilter != "" {
+			text = "Showing notes tagged #" + a.tagFilter
+		}
+		return a, tea.Batch(a.reloadNotes(), a.notify(components.ToastInfo, text))
+
+	case constants.DialogTypeCapture:
+		return a, a.runner.Capture(a.noteService, msg.Value)
 
 	case constants.DialogTypeDelete:
-		if a.currentNote != nil {
-			return a, commands.DeleteNote(a.noteService, a.currentNote.ID)
+		id := a.takeDialogNoteID()
+		if id != 0 {
+			return a, a.runner.DeleteNote(a.noteService, id)
 		}
 
 	case constants.DialogTypeNewFolder:
@@ -462,16 +973,54 @@ func (a *App) handleDialogInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if a.currentFolder != nil {
 			parentID = &a.currentFolder.ID
 		}
-		return a, commands.CreateFolder(commands.CreateFolderParams{
+		return a, a.runner.CreateFolder(commands.CreateFolderParams{
 			FolderService: a.folderService,
-			Name:          a.dialog.InputValue(),
+			Name:          strings.TrimSpace(msg.Value),
 			ParentID:      parentID,
 		})
 
 	case constants.DialogTypeDeleteFolder:
-		if a.currentFolder != nil {
-			return a, commands.DeleteFolder(a.folderService, a.currentFolder.ID)
+		folder := a.deleteFolder
+		a.deleteFolder = nil
+		if folder != nil {
+			return a, a.runner.DeleteFolder(a.folderService, folder.ID)
 		}
+
+	case constants.DialogTypeTypedDelete:
+		folder := a.deleteFolder
+		a.deleteFolder = nil
+		if folder == nil {
+			return a, nil
+		}
+		if msg.Value != folder.Name {
+			return a, a.notify(components.ToastInfo, "Folder name did not match, nothing deleted")
+		}
+		return a, a.runner.DeleteFolder(a.folderService, folder.ID)
+
+	case constants.DialogTypeCompleteAll:
+		ids := a.completeIDs
+		a.completeIDs = nil
+		if len(ids) > 0 {
+			return a, a.flushThen(a.runner.CompleteTodos(a.noteService, ids))
+		}
+
+	case constants.DialogTypeUnlock:
+		note := a.shownNote(a.takeDialogNoteID())
+		if note == nil || msg.Value == "" {
+			return a, nil
+		}
+		return a, commands.UnlockNote(a.noteService, note, msg.Value)
+
+	case constants.DialogTypeDueDate:
+		note := a.shownNote(a.takeDialogNoteID())
+		due, err := models.ParseDueDate(msg.Value, time.Now())
+		if note == nil || err != nil {
+			return a, nil
+		}
+		return a, a.editFields(note, func(n *models.Note) {
+			n.DueDate = due
+		}, a.runner.SetDueDate(a.noteService, note.ID, due))
+
 	}
 
 	return a, nil
@@ -506,9 +1055,11 @@ func (a *App) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		a.noteList.SetFocused(true)
 		a.sidebar.SetFocused(false)
 
-		return a, commands.Search(commands.SearchParams{
-			SearchService: a.searchService,
-			Query:         query,
+		return a, a.runner.Search(commands.SearchParams{
+			NoteService: a.noteService,
+			Query:       query,
+			Options:     a.searchOptions(),
+			View:        a.searchView(),
 		})
 	}
 
@@ -537,19 +1088,21 @@ func (a *App) handleSidebarInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	special := a.sidebar.SelectedSpecial()
 	if special != "" && special != a.currentFilter {
 		logging.Debug().Str("filter", special).Msg("Sidebar filter changed")
+		a.rememberView()
 		a.currentFilter = special
 		a.currentFolder = nil
-		return a, a.reloadNotes()
+		return a, a.flushThen(a.reloadNotes())
 	}
 
 	folder := a.sidebar.SelectedFolder()
 	if folder != nil && (a.currentFolder == nil || folder.ID != a.currentFolder.ID) {
 		logging.Debug().Int64("folder_id", folder.ID).Str("folder_name", folder.Name).Msg("Folder selected")
+		a.rememberView()
 		a.currentFolder = folder
 		a.currentFilter = ""
 		a.notes = nil
 		a.noteList.SetNotes(nil)
-		return a, a.reloadNotes()
+		return a, a.flushThen(a.reloadNotes())
 	}
 
 	if key.Matches(msg, keys.DefaultKeyMap.Enter) {
@@ -561,14 +1114,14 @@ func (a *App) handleSidebarInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if key.Matches(msg, keys.DefaultKeyMap.Delete) {
 		folder := a.sidebar.SelectedFolder()
 		if folder != nil {
-			a.showDeleteFolderConfirm(folder)
+			return a, a.runner.CountFolderNotes(a.folderService, folder)
 		}
 	}
 
 	if key.Matches(msg, keys.DefaultKeyMap.ToggleStar) {
 		folder := a.sidebar.SelectedFolder()
 		if folder != nil {
-			return a, commands.ToggleFolderStar(a.folderService, folder.ID)
+			return a, a.runner.ToggleFolderStar(a.folderService, folder.ID)
 		}
 	}
 
@@ -577,9 +1130,22 @@ func (a *App) handleSidebarInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleNoteListInput handles note list panel input.
 func (a *App) handleNoteListInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	previous := a.currentNote
 	a.noteList, _ = a.noteList.Update(msg)
+	if note := a.noteList.SelectedNote(); note != nil && previous != nil && note.ID != previous.ID {
+		// Moving through the list only renders the note it rests on; the
+		// selection itself changes at once so actions apply to it
+		a.currentNote = note
+		a.preview.SetPending(note)
+		a.previewSeq++
+		return a, commands.SettlePreviewAfter(a.previewSeq, constants.PreviewSettleDelay)
+	}
 	a.updatePreview()
 
+	if key.Matches(msg, keys.DefaultKeyMap.CompleteAll) {
+		return a, a.showCompleteAllConfirm()
+	}
+
 	note := a.noteList.SelectedNote()
 	folder := a.noteList.SelectedFolder()
 
@@ -593,6 +1159,7 @@ func (a *App) handleNoteListInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		switch {
 		case key.Matches(msg, keys.DefaultKeyMap.Enter):
 			// Navigate to folder
+			a.rememberView()
 			a.currentFolder = folder
 			a.currentFilter = ""
 			// Clean up note list state before reload
@@ -603,11 +1170,11 @@ func (a *App) handleNoteListInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// We also need to update the sidebar selection to reflect this change
 			a.sidebar.SelectFolder(folder.ID)
 
-			return a, a.reloadNotes()
+			return a, a.flushThen(a.reloadNotes())
 
 		case key.Matches(msg, keys.DefaultKeyMap.ToggleStar):
 			return a, tea.Batch(
-				commands.ToggleFolderStar(a.folderService, folder.ID),
+				a.runner.ToggleFolderStar(a.folderService, folder.ID),
 				// We need to reload to refresh the list, specifically the Starred list
 				a.reloadNotes(),
 			)
@@ -617,31 +1184,110 @@ func (a *App) handleNoteListInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	logging.Debug().Int64("note_id", note.ID).Str("note_title", note.Title).Msg("Note selected")
 
+	if note.Locked && isLockedAction(msg) {
+		logging.Debug().Int64("note_id", note.ID).Msg("Refusing change to locked note")
+		return a, a.notify(components.ToastInfo, "Note is locked (L to unlock)")
+	}
+
 	switch {
 	case key.Matches(msg, keys.DefaultKeyMap.Enter), key.Matches(msg, keys.DefaultKeyMap.Edit):
 		logging.Info().Int64("note_id", note.ID).Msg("Opening note to edit")
 		return a.editNote(note)
 
+	case key.Matches(msg, keys.DefaultKeyMap.OpenExternal):
+		return a, a.openExternal(note)
+
 	case key.Matches(msg, keys.DefaultKeyMap.Delete):
+		if !a.cfg.UI.ConfirmNoteDelete {
+			logging.Debug().Int64("note_id", note.ID).Msg("Deleting note without confirmation")
+			return a, a.runner.DeleteNote(a.noteService, note.ID)
+		}
 		logging.Debug().Int64("note_id", note.ID).Msg("Showing delete confirmation")
 		a.showDeleteConfirm(note)
 
+	case key.Matches(msg, keys.DefaultKeyMap.ToggleLock):
+		logging.Debug().Int64("note_id", note.ID).Bool("locked", !note.Locked).Msg("Toggling lock")
+		return a, a.runner.ToggleLock(a.noteService, note.ID)
+
 	case key.Matches(msg, keys.DefaultKeyMap.ToggleStar):
 		logging.Debug().Int64("note_id", note.ID).Msg("Toggling star")
-		return a, commands.ToggleStar(a.noteService, note.ID)
+		return a, a.runner.ToggleStar(a.noteService, note.ID)
 
 	case key.Matches(msg, keys.DefaultKeyMap.ToggleDone) && note.IsTodo:
 		logging.Debug().Int64("note_id", note.ID).Msg("Toggling todo done")
-		return a, commands.ToggleTodo(a.noteService, note.ID)
+		return a, a.runner.ToggleTodo(a.noteService, note.ID)
+
+	case key.Matches(msg, keys.DefaultKeyMap.CycleLabel):
+		logging.Debug().Int64("note_id", note.ID).Str("label", note.Label).Msg("Cycling label")
+		return a, a.editFields(note, func(n *models.Note) {
+			n.Label = models.NextLabel(n.Label)
+		}, a.runner.CycleLabel(a.noteService, note.ID, note.Label))
 
 	case key.Matches(msg, keys.DefaultKeyMap.CyclePriority) && note.IsTodo:
 		logging.Debug().Int64("note_id", note.ID).Int("priority", note.Priority).Msg("Cycling priority")
-		return a, commands.CyclePriority(a.noteService, note.ID, note.Priority)
+		return a, a.editFields(note, func(n *models.Note) {
+			n.Priority = (n.Priority + 1) % constants.PriorityMax
+		}, a.runner.CyclePriority(a.noteService, note.ID, note.Priority))
+
+	case key.Matches(msg, keys.DefaultKeyMap.PickPriority) && note.IsTodo:
+		logging.Debug().Int64("note_id", note.ID).Int("priority", note.Priority).Msg("Showing priority menu")
+		a.showPriorityMenu(note)
+
+	case key.Matches(msg, keys.DefaultKeyMap.SetDueDate) && note.IsTodo:
+		logging.Debug().Int64("note_id", note.ID).Msg("Showing due date dialog")
+		a.showDueDateDialog(note)
+
+	case key.Matches(msg, keys.DefaultKeyMap.MoveNote):
+		logging.Debug().Int64("note_id", note.ID).Msg("Showing move dialog")
+		return a, a.showMoveDialog(note)
+
+	case key.Matches(msg, keys.DefaultKeyMap.CopyContent):
+		if note.Encrypted {
+			entry, ok := a.unlockedContent(note)
+			if !ok {
+				return a, a.notify(components.ToastInfo, "Note is encrypted (U to unlock)")
+			}
+			return a, commands.CopyToClipboard(entry.content, "content")
+		}
+		return a, commands.CopyToClipboard(note.Content, "content")
+
+	case key.Matches(msg, keys.DefaultKeyMap.Unlock):
+		return a, a.toggleUnlock(note)
+
+	case key.Matches(msg, keys.DefaultKeyMap.CopyTitle):
+		return a, commands.CopyToClipboard(note.Title, "title")
+
+	case key.Matches(msg, keys.DefaultKeyMap.RepeatMove):
+		if a.lastMoveFolder == nil {
+			return a, a.notify(components.ToastInfo, "No previous move to repeat")
+		}
+		logging.Debug().Int64("note_id", note.ID).Int64("folder_id", a.lastMoveFolder.ID).Msg("Repeating last move")
+		return a, a.runner.MoveNote(a.noteService, note.ID, a.lastMoveFolder)
+
+	case key.Matches(msg, keys.DefaultKeyMap.MoveUp):
+		return a, a.moveTodo(note, -1)
+
+	case key.Matches(msg, keys.DefaultKeyMap.MoveDown):
+		return a, a.moveTodo(note, 1)
 	}
 
 	return a, nil
 }
 
+// moveTodo swaps a todo with the one step rows away in the Todos view when
+// it is in manual order
+func (a *App) moveTodo(note *models.Note, step int) tea.Cmd {
+	if a.currentFilter != constants.FilterTodos || !a.cfg.Todos.ManualOrder {
+		return a.notify(components.ToastInfo, "Reordering needs the Todos view and todos.manual_order")
+	}
+	other := a.noteList.Adjacent(note.ID, step)
+	if other == nil {
+		return nil
+	}
+	logging.Debug().Int64("note_id", note.ID).Int64("other_id", other.ID).Msg("Reordering todo")
+	return a.flushThen(a.runner.MoveTodo(a.noteService, note.ID, other.ID))
+}
+
 // Panel switching and layout methods
 
 func (a *App) switchPanel(delta int) {
@@ -661,16 +1307,33 @@ func (a *App) switchPanel(delta int) {
 	a.noteList.SetFocused(a.currentPanel == PanelNoteList)
 }
 
-func (a *App) updateLayout() {
-	// Calculate sidebar width
-	sidebarWidth := a.width * constants.SidebarWidthPercent / 100
-	if sidebarWidth < constants.SidebarMinWidth {
-		sidebarWidth = constants.SidebarMinWidth
+// sidebarWidth returns the configured sidebar width in columns, falling back
+// to a percentage of the screen when no width is configured.
+func (a *App) sidebarWidth() int {
+	width := a.cfg.UI.SidebarWidth
+	if width <= 0 {
+		width = a.width * constants.SidebarWidthPercent / 100
 	}
-	if sidebarWidth > constants.SidebarMaxWidth {
-		sidebarWidth = constants.SidebarMaxWidth
+	return clampSidebarWidth(width)
+}
+
+// resizeSidebar adjusts the sidebar width by delta columns and persists it.
+func (a *App) resizeSidebar(delta int) tea.Cmd {
+	a.cfg.UI.SidebarWidth = clampSidebarWidth(a.sidebarWidth() + delta)
+	logging.Debug().Int("sidebar_width", a.cfg.UI.SidebarWidth).Msg("Resizing sidebar")
+	a.updateLayout()
+	return commands.SaveConfig(a.cfg)
+}
+
+func (a *App) updateLayout() {
+	// View shows a notice instead of panels until the terminal grows, and
+	// the next resize lays everything out again
+	if a.tooSmall() {
+		return
 	}
 
+	sidebarWidth := a.sidebarWidth()
+
 	// Right panel takes remaining width
 	rightPanelWidth := a.width - sidebarWidth
 	if rightPanelWidth < 40 {
@@ -689,54 +1352,94 @@ func (a *App) updateLayout() {
 		availableHeight = 15
 	}
 
-	// Note list gets 50% of height, preview gets 50%
-	// Add 2 to each for borders (top + bottom)
-	noteListTotalHeight := (availableHeight * 50) / 100
-	previewTotalHeight := availableHeight - noteListTotalHeight
-
-	if !a.showPreview {
-		noteListTotalHeight = availableHeight
-		previewTotalHeight = 0
-	}
-
-	// Ensure minimum heights (including borders)
-	if noteListTotalHeight < 8 {
-		noteListTotalHeight = 8
-	}
-	if a.showPreview && previewTotalHeight < 6 {
-		previewTotalHeight = 6
-	}
-
 	// Sidebar gets full available height
 	a.sidebar.SetSize(sidebarWidth, availableHeight)
-	a.noteList.SetSize(rightPanelWidth, noteListTotalHeight)
-	if a.showPreview {
+
+	switch {
+	case a.previewRight():
+		// Side by side, the note list and preview split the width and
+		// both get the full height
+		noteListWidth := rightPanelWidth / 2
+		a.noteList.SetSize(noteListWidth, availableHeight)
+		a.preview.SetSize(rightPanelWidth-noteListWidth, availableHeight)
+
+	case a.showPreview:
+		// Note list gets 50% of height, preview gets 50%
+		// Add 2 to each for borders (top + bottom)
+		noteListTotalHeight := (availableHeight * 50) / 100
+		previewTotalHeight := availableHeight - noteListTotalHeight
+
+		// Ensure minimum heights (including borders)
+		if noteListTotalHeight < 8 {
+			noteListTotalHeight = 8
+		}
+		if previewTotalHeight < 6 {
+			previewTotalHeight = 6
+		}
+		a.noteList.SetSize(rightPanelWidth, noteListTotalHeight)
 		a.preview.SetSize(rightPanelWidth, previewTotalHeight)
-	} else {
+
+	default:
+		a.noteList.SetSize(rightPanelWidth, availableHeight)
 		a.preview.SetSize(0, 0)
 	}
 	a.statusBar.SetWidth(a.width)
 	a.searchBar.SetSize(a.width, 3)
 	a.help.SetSize(a.width, a.height)
+	a.diff.SetSize(a.width, a.height)
+	a.picker.SetSize(a.width, a.height)
+	a.menu.SetSize(a.width, a.height)
 	a.dialog.SetSize(a.width, a.height)
 }
 
+// previewRight reports whether the preview goes beside the note list rather
+// than below it: it is configured to, and both panels fit at their minimum
+// width. Narrower terminals fall back to stacking them.
+func (a *App) previewRight() bool {
+	return a.showPreview &&
+		a.cfg.UI.PreviewPosition == config.PreviewRight &&
+		a.width-a.sidebarWidth() >= 2*constants.SplitPanelMinWidth
+}
+
 func (a *App) updatePreview() {
+	// Any settle still pending is superseded by this selection
+	a.previewSeq++
 	note := a.noteList.SelectedNote()
-	a.preview.SetNote(note)
+	a.preview.SetNote(a.previewNote(note))
 	a.currentNote = note
 }
 
 // View renders the UI.
-func (a *App) View() string {
+func (a *App) View() (view string) {
+	defer a.recoverView(&view)
+	if a.crash != nil {
+		return a.renderCrashed()
+	}
+
 	if !a.ready {
 		return "Loading..."
 	}
 
+	if a.tooSmall() {
+		return a.renderTooSmall()
+	}
+
 	if a.showHelp {
 		return a.renderWithOverlay(a.help.View())
 	}
 
+	if a.diff.IsVisible() {
+		return a.renderWithOverlay(a.diff.View())
+	}
+
+	if a.picker.IsVisible() {
+		return a.renderWithOverlay(a.picker.View())
+	}
+
+	if a.menu.IsVisible() {
+		return a.renderWithOverlay(a.menu.View())
+	}
+
 	if a.showDialog {
 		return a.renderWithOverlay(a.dialog.View())
 	}
@@ -745,15 +1448,18 @@ func (a *App) View() string {
 
 	sidebar := a.sidebar.View()
 
-	// Note list and preview combined vertically on the right side
+	// Note list and preview combined on the right side, stacked unless
+	// the preview is placed to the right
 	noteListView := a.noteList.View()
 	parts := []string{sidebar}
 
-	if a.showPreview {
-		previewView := a.preview.View()
-		rightPanel := lipgloss.JoinVertical(lipgloss.Left, noteListView, previewView)
+	switch {
+	case a.previewRight():
+		parts = append(parts, noteListView, a.preview.View())
+	case a.showPreview:
+		rightPanel := lipgloss.JoinVertical(lipgloss.Left, noteListView, a.preview.View())
 		parts = append(parts, rightPanel)
-	} else {
+	default:
 		parts = append(parts, noteListView)
 	}
 
@@ -766,11 +1472,28 @@ func (a *App) View() string {
 	}
 	parts = append(parts, mainContent, statusBar)
 
-	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+	view = lipgloss.JoinVertical(lipgloss.Left, parts...)
+	return a.toasts.Overlay(view, a.width, lipgloss.Height(header))
+}
+
+// tooSmall reports whether the terminal is below the size the layout needs.
+func (a *App) tooSmall() bool {
+	return a.width < constants.MinTerminalWidth || a.height < constants.MinTerminalHeight
+}
+
+// renderTooSmall renders a centered notice in place of a layout that would
+// overlap or clip at the current size.
+func (a *App) renderTooSmall() string {
+	msg := lipgloss.JoinVertical(lipgloss.Center,
+		styles.ErrorStyle.Render("Terminal too small"),
+		styles.TextMuted.Render(fmt.Sprintf("need at least %dx%d, have %dx%d",
+			constants.MinTerminalWidth, constants.MinTerminalHeight, a.width, a.height)),
+	)
+	return lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, msg)
 }
 
 func (a *App) renderHeader() string {
-	title := styles.TitleStyle.Render("記録 Kiroku")
+	title := styles.TitleStyle.Render(styles.Icons.AppTitle)
 	date := styles.DateStyle.Render(time.Now().Format("Mon, Jan 2 15:04"))
 
 	spacing := a.width - lipgloss.Width(title) - lipgloss.Width(date) - 2
@@ -790,16 +1513,102 @@ func (a *App) renderWithOverlay(overlay string) string {
 // Action methods
 
 func (a *App) reloadNotes() tea.Cmd {
+	// Reloading leaves the search results, so their highlight goes too
+	a.preview.SetHighlight("")
 	folderName := a.getFolderDisplayName()
+	if a.tagFilter != "" {
+		folderName += " #" + a.tagFilter
+	}
 	a.noteList.SetFolderName(folderName)
+	a.noteList.SetGroupCompleted(a.currentFilter == constants.FilterTodos && a.cfg.Todos.ShowCompleted)
+	if a.currentFilter == constants.FilterToday {
+		a.noteList.SetSectionFunc(todaySection(time.Now()))
+	} else {
+		a.noteList.SetSectionFunc(nil)
+	}
+
+	reload := a.runner.ReloadNotes(a.reloadParams())
+	// Creating, moving and deleting notes all change the Unfiled count
+	return tea.Batch(reload, a.runner.CountUnfiled(a.noteService))
+}
 
-	return commands.ReloadNotes(commands.ReloadNotesParams{
+// reloadParams selects the notes of the current view. The initial load and
+// every reload share it so both list the same notes.
+func (a *App) reloadParams() commands.ReloadNotesParams {
+	return commands.ReloadNotesParams{
 		NoteService:   a.noteService,
 		FolderService: a.folderService,
 		CurrentFilter: a.currentFilter,
 		CurrentFolder: a.currentFolder,
 		ShowCompleted: a.cfg.Todos.ShowCompleted,
-	})
+		HideDone:      a.hideDone,
+		RecentLimit:   a.cfg.UI.RecentLimit,
+		ReviewAge:     a.cfg.UI.ReviewAge,
+		ManualOrder:   a.cfg.Todos.ManualOrder,
+		Tag:           a.tagFilter,
+	}
+}
+
+// createNoteParams creates a note titled title in the open folder, tagged
+// with the tag filter, unless ui.inherit_filter_on_create is off.
+func (a *App) createNoteParams(title string, isTodo bool) commands.CreateNoteParams {
+	params := commands.CreateNoteParams{
+		NoteService:   a.noteService,
+		Title:         strings.TrimSpace(title),
+		IsTodo:        isTodo,
+		FolderService: a.folderService,
+		InboxFolder:   a.cfg.UI.DefaultFolder,
+	}
+	if a.cfg.UI.InheritFilterOnCreate {
+		params.CurrentFolder = a.currentFolder
+		params.Tags = a.tagFilter
+	}
+	return params
+}
+
+// searchOptions scopes a search to the active folder or filter.
+func (a *App) searchOptions() models.ListOptions {
+	var opts models.ListOptions
+	switch a.currentFilter {
+	case constants.FilterTodos:
+		isTodo := true
+		opts.IsTodo = &isTodo
+		if !a.cfg.Todos.ShowCompleted {
+			isDone := false
+			opts.IsDone = &isDone
+		}
+	case constants.FilterStarred:
+		starred := true
+		opts.Starred = &starred
+	case constants.FilterUnfiled:
+		opts.Unfiled = true
+	case constants.FilterReview:
+		// The same notes GetStale lists; an unparsable age leaves the
+		// view empty, so the search is left unscoped
+		if cutoff, err := models.ParseAge(a.cfg.UI.ReviewAge, time.Now()); err == nil {
+			starred := false
+			opts.UpdatedUntil = &cutoff
+			opts.Starred = &starred
+		}
+	case constants.FilterToday, constants.FilterRecent:
+		// Scoped by searchView
+	default:
+		if a.currentFolder != nil {
+			opts.FolderID = &a.currentFolder.ID
+		}
+	}
+	opts.Tag = a.tagFilter
+	return opts
+}
+
+// searchView scopes a search to the views searchOptions cannot express
+func (a *App) searchView() *commands.ReloadNotesParams {
+	switch a.currentFilter {
+	case constants.FilterToday, constants.FilterRecent:
+		params := a.reloadParams()
+		return &params
+	}
+	return nil
 }
 
 func (a *App) getFolderDisplayName() string {
@@ -808,9 +1617,20 @@ func (a *App) getFolderDisplayName() string {
 		return "All Notes"
 	case constants.FilterTodos:
 		return "Todos"
+	case constants.FilterToday:
+		return "Today"
+	case constants.FilterRecent:
+		return "Recent"
+	case constants.FilterReview:
+		return "Review (unchanged " + a.cfg.UI.ReviewAge + ")"
 	case constants.FilterStarred:
 		return "Starred"
+	case constants.FilterUnfiled:
+		return "Unfiled"
 	default:
+		if a.currentFolder != nil && a.hideDone {
+			return a.currentFolder.Name + " — active"
+		}
 		if a.currentFolder != nil {
 			return a.currentFolder.Name
 		}
@@ -820,13 +1640,17 @@ func (a *App) getFolderDisplayName() string {
 
 func (a *App) showNewNoteDialog() {
 	a.dialog.ShowInput("New Note", "Enter note title...")
-	a.dialogType = constants.DialogTypeNewNote
+	a.dialog.SetValidator(requireName("Title", a.cfg.Database.TitleMaxLength))
+	a.dialog.SetCharLimit(a.cfg.Database.TitleMaxLength)
+	a.dialog.SetType(constants.DialogTypeNewNote)
 	a.showDialog = true
 }
 
 func (a *App) showNewTodoDialog() {
 	a.dialog.ShowInput("New Todo", "Enter todo title...")
-	a.dialogType = constants.DialogTypeNewTodo
+	a.dialog.SetValidator(requireName("Title", a.cfg.Database.TitleMaxLength))
+	a.dialog.SetCharLimit(a.cfg.Database.TitleMaxLength)
+	a.dialog.SetType(constants.DialogTypeNewTodo)
 	a.showDialog = true
 }
 
@@ -836,32 +1660,158 @@ func (a *App) showNewFolderDialog() {
 		title = fmt.Sprintf("New Folder in '%s'", a.currentFolder.Name)
 	}
 	a.dialog.ShowInput(title, "Enter folder name...")
-	a.dialogType = constants.DialogTypeNewFolder
+	a.dialog.SetValidator(requireName("Folder name", constants.MaxNameLength))
+	a.dialog.SetType(constants.DialogTypeNewFolder)
+	a.showDialog = true
+}
+
+func (a *App) showCaptureDialog() {
+	a.dialog.ShowInput("Quick Capture", "Jot something down for today...")
+	a.dialog.SetType(constants.DialogTypeCapture)
+	a.showDialog = true
+}
+
+// showTagFilterDialog asks for the tag to filter every view by, starting
+// from the active one; an empty answer clears it
+func (a *App) showTagFilterDialog() {
+	a.dialog.ShowInput("Filter by tag", "Tag to show, empty for all...")
+	a.dialog.SetInputValue(a.tagFilter)
+	a.dialog.SetType(constants.DialogTypeFilterTag)
 	a.showDialog = true
 }
 
 func (a *App) showDeleteConfirm(note *models.Note) {
+	a.dialogNoteID = note.ID
 	a.dialog.ShowConfirm("Delete Note", fmt.Sprintf("Delete '%s'?", note.Title))
-	a.dialogType = constants.DialogTypeDelete
+	a.dialog.SetType(constants.DialogTypeDelete)
 	a.showDialog = true
 }
 
+// showCompleteAllConfirm asks before completing the open todos in the
+// note list. Only the notes shown are affected, never the whole database,
+// and locked todos are left alone.
+func (a *App) showCompleteAllConfirm() tea.Cmd {
+	var ids []int64
+	for _, note := range a.notes {
+		if note.IsTodo && !note.IsDone && !note.Locked {
+			ids = append(ids, note.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return a.notify(components.ToastInfo, "No open todos shown")
+	}
+
+	a.completeIDs = ids
+	a.dialog.ShowConfirm("Complete Todos", fmt.Sprintf("Mark %d shown todo(s) done?", len(ids)))
+	a.dialog.SetType(constants.DialogTypeCompleteAll)
+	a.showDialog = true
+	return nil
+}
+
 func (a *App) showDeleteFolderConfirm(folder *models.Folder) {
+	a.deleteFolder = folder
 	a.dialog.ShowConfirm("Delete Folder", fmt.Sprintf("Delete '%s'?", folder.Name))
-	a.dialogType = constants.DialogTypeDeleteFolder
+	a.dialog.SetType(constants.DialogTypeDeleteFolder)
 	a.showDialog = true
 }
 
+func (a *App) showMoveDialog(note *models.Note) tea.Cmd {
+	if len(a.folders) == 0 {
+		return a.notify(components.ToastInfo, "No folders to move to")
+	}
+
+	selected := note.FolderID
+	if a.lastMoveFolder != nil {
+		selected = &a.lastMoveFolder.ID
+	}
+	a.picker.Show(fmt.Sprintf("Move '%s' to", note.Title), a.folders, selected)
+	a.pickerAction = constants.DialogTypeMove
+	return nil
+}
+
+func (a *App) showDueDateDialog(note *models.Note) {
+	a.dialog.ShowInput(fmt.Sprintf("Due date for '%s'", note.Title), "tomorrow, fri, in 3 days, 2024-03-01...")
+	if note.DueDate != nil {
+		a.dialog.SetInputValue(note.DueDate.Format(models.DueDateFormat))
+	}
+	a.dialog.SetValidator(func(input string) error {
+		_, err := models.ParseDueDate(input, time.Now())
+		return err
+	})
+	a.dialogNoteID = note.ID
+	a.dialog.SetType(constants.DialogTypeDueDate)
+	a.showDialog = true
+}
+
+// takeDialogNoteID returns the note the open dialog was shown for and
+// clears it, so a later dialog never acts on it again
+func (a *App) takeDialogNoteID() int64 {
+	id := a.dialogNoteID
+	a.dialogNoteID = 0
+	return id
+}
+
+// shownNote returns the listed note with id, or nil when it is no longer
+// shown
+func (a *App) shownNote(id int64) *models.Note {
+	for _, note := range a.notes {
+		if note.ID == id {
+			return note
+		}
+	}
+	return nil
+}
+
+func (a *App) showTypedDeleteFolderConfirm(folder *models.Folder, noteCount int) {
+	title := fmt.Sprintf("Delete '%s' (%d notes affected)", folder.Name, noteCount)
+	a.deleteFolder = folder
+	a.dialog.ShowInput(title, fmt.Sprintf("Type '%s' to confirm...", folder.Name))
+	a.dialog.SetType(constants.DialogTypeTypedDelete)
+	a.showDialog = true
+}
+
+// undo reverts the most recent recorded note change.
+func (a *App) undo() tea.Cmd {
+	entry, ok := a.history.popUndo()
+	if !ok {
+		return a.notify(components.ToastInfo, "Nothing to undo")
+	}
+	logging.Debug().Str("change", entry.label).Msg("Undoing change")
+	label := fmt.Sprintf("Undid %s", entry.label)
+	return a.runner.RestoreNote(a.noteService, label, entry.change.After, entry.change.Before)
+}
+
+// redo reapplies the most recently undone note change.
+func (a *App) redo() tea.Cmd {
+	entry, ok := a.history.popRedo()
+	if !ok {
+		return a.notify(components.ToastInfo, "Nothing to redo")
+	}
+	logging.Debug().Str("change", entry.label).Msg("Redoing change")
+	label := fmt.Sprintf("Redid %s", entry.label)
+	return a.runner.RestoreNote(a.noteService, label, entry.change.Before, entry.change.After)
+}
+
 func (a *App) editNote(note *models.Note) (tea.Model, tea.Cmd) {
 	logging.Info().Int64("note_id", note.ID).Str("title", note.Title).Msg("Opening editor for note")
 
+	if note.Encrypted {
+		entry, ok := a.unlockedContent(note)
+		if !ok {
+			return a, a.notify(components.ToastInfo, "Note is encrypted (U to unlock it first)")
+		}
+		// The editor works on the plain text; saving seals it again
+		plain := *note
+		plain.Content = entry.content
+		note = &plain
+	}
+
 	a.currentNote = note
 
 	tmpFile, editorCmd, err := a.editorService.PrepareEdit(note.Title, note.Content)
 	if err != nil {
 		logging.Error().Err(err).Msg("Failed to prepare editor")
-		a.statusBar.SetMessage(fmt.Sprintf("Error: %v", err))
-		return a, commands.ClearStatusAfter(constants.ErrorMessageDuration)
+		return a, a.notify(components.ToastError, fmt.Sprintf("Error: %v", err))
 	}
 
 	a.editingTempFile = tmpFile
@@ -877,8 +1827,192 @@ func (a *App) editNote(note *models.Note) (tea.Model, tea.Cmd) {
 	})
 }
 
+// openExternal opens a note in the system default app. Edits made there
+// are not watched; "kiroku open <id> --watch" saves them back.
+func (a *App) openExternal(note *models.Note) tea.Cmd {
+	logging.Info().Int64("note_id", note.ID).Msg("Opening note in default app")
+
+	path, err := a.editorService.OpenExternal(note)
+	if err != nil {
+		logging.Error().Err(err).Msg("Failed to open note in default app")
+		return a.notify(components.ToastError, fmt.Sprintf("Error: %v", err))
+	}
+	return a.notify(components.ToastInfo, fmt.Sprintf("Opened %s in default app", path))
+}
+
 // Helper functions
 
+func clampSidebarWidth(width int) int {
+	if width < constants.SidebarMinWidth {
+		return constants.SidebarMinWidth
+	}
+	if width > constants.SidebarMaxWidth {
+		return constants.SidebarMaxWidth
+	}
+	return width
+}
+
+// restoreSession switches to the view saved by the previous session.
+// A saved folder that no longer exists leaves the default view in place.
+func (a *App) restoreSession() tea.Cmd {
+	state := *a.restore
+	a.restore = nil
+
+	cmd, ok := a.showView(state)
+	if ok {
+		logging.Debug().Str("filter", a.currentFilter).Msg("Restoring session")
+	}
+	return cmd
+}
+
+// showView switches to a saved folder or filter and reselects its note
+// once the notes load. It reports false, changing nothing, when the
+// folder no longer exists or the filter is unknown.
+func (a *App) showView(state sessionState) (tea.Cmd, bool) {
+	switch {
+	case state.FolderID != nil:
+		folder := findFolder(a.folders, *state.FolderID)
+		if folder == nil {
+			logging.Debug().Int64("folder_id", *state.FolderID).Msg("Saved folder no longer exists")
+			return nil, false
+		}
+		a.currentFolder = folder
+		a.currentFilter = ""
+		a.sidebar.SelectFolder(folder.ID)
+	case isFilter(state.Filter):
+		a.currentFilter = state.Filter
+		a.currentFolder = nil
+		a.sidebar.SelectSpecial(state.Filter)
+	default:
+		return nil, false
+	}
+
+	a.restoreNoteID = state.NoteID
+	return a.reloadNotes(), true
+}
+
+// viewState returns the current folder or filter and selected note.
+func (a *App) viewState() sessionState {
+	state := sessionState{Filter: a.currentFilter}
+	if a.currentFolder != nil {
+		id := a.currentFolder.ID
+		state.FolderID = &id
+	}
+	if note := a.noteList.SelectedNote(); note != nil {
+		id := note.ID
+		state.NoteID = &id
+	}
+	return state
+}
+
+// rememberView records the current view for Back before it changes.
+func (a *App) rememberView() {
+	a.nav.push(a.viewState())
+}
+
+// navigate goes back or forward through the visited views, skipping
+// views whose folder has since been deleted.
+func (a *App) navigate(back bool) tea.Cmd {
+	var cmd tea.Cmd
+	moved := a.nav.move(back, a.viewState(), func(view sessionState) bool {
+		var ok bool
+		cmd, ok = a.showView(view)
+		return ok
+	})
+	if !moved {
+		if back {
+			return a.notify(components.ToastInfo, "No previous folder")
+		}
+		return a.notify(components.ToastInfo, "No next folder")
+	}
+	logging.Debug().Str("filter", a.currentFilter).Bool("back", back).Msg("Navigating history")
+	return a.flushThen(cmd)
+}
+
+// persistSession saves the current view when session restore is enabled.
+func (a *App) persistSession() {
+	if a.sessionPath == "" {
+		return
+	}
+
+	state := a.viewState()
+	if err := saveSession(a.sessionPath, state); err != nil {
+		logging.Warn().Err(err).Msg("Failed to save session")
+	}
+}
+
+// isFilter reports whether filter is one of the sidebar filters.
+func isFilter(filter string) bool {
+	switch filter {
+	case constants.FilterAll, constants.FilterTodos, constants.FilterStarred, constants.FilterToday, constants.FilterRecent,
+		constants.FilterReview, constants.FilterUnfiled:
+		return true
+	default:
+		return false
+	}
+}
+
+// requireName returns a dialog validator that rejects blank names and
+// names over maxLength characters, labelling the message with what is
+// being named. A maxLength of zero or less allows any length.
+func requireName(label string, maxLength int) func(string) error {
+	return func(input string) error {
+		name := strings.TrimSpace(input)
+		if name == "" {
+			return fmt.Errorf("%s required", label)
+		}
+		if n := utf8.RuneCountInString(name); maxLength > 0 && n > maxLength {
+			return fmt.Errorf("%s too long (%d/%d)", label, n, maxLength)
+		}
+		return nil
+	}
+}
+
+// isLockedAction reports whether a note list key would change or delete a note.
+func isLockedAction(msg tea.KeyMsg) bool {
+	return key.Matches(msg, keys.DefaultKeyMap.Enter) ||
+		key.Matches(msg, keys.DefaultKeyMap.Edit) ||
+		key.Matches(msg, keys.DefaultKeyMap.Delete) ||
+		key.Matches(msg, keys.DefaultKeyMap.ToggleDone) ||
+		key.Matches(msg, keys.DefaultKeyMap.CyclePriority) ||
+		key.Matches(msg, keys.DefaultKeyMap.PickPriority) ||
+		key.Matches(msg, keys.DefaultKeyMap.CycleLabel) ||
+		key.Matches(msg, keys.DefaultKeyMap.SetDueDate) ||
+		key.Matches(msg, keys.DefaultKeyMap.MoveNote) ||
+		key.Matches(msg, keys.DefaultKeyMap.RepeatMove)
+}
+
+// findFolder returns the folder with the given ID anywhere in the tree, or nil.
+func findFolder(folders []*models.Folder, id int64) *models.Folder {
+	for _, folder := range folders {
+		if folder.ID == id {
+			return folder
+		}
+		if found := findFolder(folder.Children, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// todaySection groups the Today view into overdue todos, todos due
+// today, and the remaining notes touched today.
+func todaySection(now time.Time) func(*models.Note) string {
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	startOfTomorrow := startOfDay.AddDate(0, 0, 1)
+	return func(note *models.Note) string {
+		open := note.IsTodo && !note.IsDone && note.DueDate != nil
+		switch {
+		case open && note.DueDate.Before(startOfDay):
+			return "Overdue"
+		case open && note.DueDate.Before(startOfTomorrow):
+			return "Due today"
+		default:
+			return "Updated today"
+		}
+	}
+}
+
 func panelName(p Panel) string {
 	switch p {
 	case PanelSidebar:
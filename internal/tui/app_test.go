@@ -0,0 +1,406 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tranducquang/kiroku/internal/config"
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/service"
+	"github.com/tranducquang/kiroku/internal/tui/constants"
+	"github.com/tranducquang/kiroku/internal/tui/messages"
+)
+
+func runeKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func TestIsLockedAction(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  tea.KeyMsg
+		want bool
+	}{
+		{"edit", tea.KeyMsg{Type: tea.KeyEnter}, true},
+		{"move", runeKey('m'), true},
+		{"repeat move", runeKey('M'), true},
+		{"star", runeKey('s'), false},
+		{"copy title", runeKey('Y'), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isLockedAction(tt.msg); got != tt.want {
+				t.Errorf("isLockedAction(%q) = %v, want %v", tt.msg.String(), got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeFolders records the folders deleted through it; any other call
+// panics on the nil embedded interface
+type fakeFolders struct {
+	service.FolderServiceInterface
+	deleted []int64
+}
+
+func (f *fakeFolders) Delete(ctx context.Context, id int64) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func (f *fakeFolders) GetTree(ctx context.Context) ([]*models.Folder, error) {
+	return nil, nil
+}
+
+func TestDeleteFolder_DeletesCountedFolder(t *testing.T) {
+	counted := &models.Folder{ID: 2, Name: "Counted"}
+	tests := []struct {
+		name       string
+		count      int
+		dialogType string
+		value      string
+		want       []int64
+	}{
+		{"confirm", 0, constants.DialogTypeDeleteFolder, "", []int64{2}},
+		{"typed name", 5, constants.DialogTypeTypedDelete, "Counted", []int64{2}},
+		{"typed other name", 5, constants.DialogTypeTypedDelete, "Current", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folders := &fakeFolders{}
+			a := NewApp(nil, nil, nil, nil, nil, &config.Config{})
+			a.folderService = folders
+			a.cfg.UI.ConfirmDeleteThreshold = 1
+
+			a.handleFolderNoteCount(messages.FolderNoteCountMsg{Folder: counted, Count: tt.count})
+			// The selection moves on while the dialog is open
+			a.currentFolder = &models.Folder{ID: 3, Name: "Current"}
+
+			_, cmd := a.handleDialogResult(messages.DialogResultMsg{
+				Type:      tt.dialogType,
+				Confirmed: true,
+				Value:     tt.value,
+			})
+			// A refusal only shows a toast, whose command waits out its timer
+			if cmd != nil && tt.want != nil {
+				cmd()
+			}
+			if !reflect.DeepEqual(folders.deleted, tt.want) {
+				t.Errorf("deleted folders %v, want %v", folders.deleted, tt.want)
+			}
+		})
+	}
+}
+
+// fakeNotes records the notes acted on through it; any other call panics
+// on the nil embedded interface
+type fakeNotes struct {
+	service.NoteServiceInterface
+	touched []int64
+}
+
+func (f *fakeNotes) GetByID(ctx context.Context, id int64) (*models.Note, error) {
+	return &models.Note{ID: id}, nil
+}
+
+func (f *fakeNotes) Delete(ctx context.Context, id int64) error {
+	f.touched = append(f.touched, id)
+	return nil
+}
+
+func (f *fakeNotes) SetDueDate(ctx context.Context, id int64, due *time.Time) error {
+	f.touched = append(f.touched, id)
+	return nil
+}
+
+func (f *fakeNotes) Reveal(note *models.Note, passphrase string) (string, error) {
+	f.touched = append(f.touched, note.ID)
+	return "", nil
+}
+
+func TestNoteDialogs_ActOnNoteShownFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		open       func(a *App, note *models.Note)
+		dialogType string
+		value      string
+	}{
+		{"delete", (*App).showDeleteConfirm, constants.DialogTypeDelete, ""},
+		{"due date", (*App).showDueDateDialog, constants.DialogTypeDueDate, "2024-03-01"},
+		{"unlock", func(a *App, note *models.Note) { a.toggleUnlock(note) }, constants.DialogTypeUnlock, "hunter2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes := &fakeNotes{}
+			a := NewApp(nil, nil, nil, nil, nil, &config.Config{})
+			a.noteService = notes
+			shown := &models.Note{ID: 1, Title: "Shown", Encrypted: true}
+			other := &models.Note{ID: 2, Title: "Other", Encrypted: true}
+			a.notes = []*models.Note{shown, other}
+
+			tt.open(a, shown)
+			// The selection moves on while the dialog is open
+			a.currentNote = other
+
+			_, cmd := a.handleDialogResult(messages.DialogResultMsg{
+				Type:      tt.dialogType,
+				Confirmed: true,
+				Value:     tt.value,
+			})
+			if cmd == nil {
+				t.Fatal("handleDialogResult() returned no command")
+			}
+			cmd()
+			if want := []int64{1}; !reflect.DeepEqual(notes.touched, want) {
+				t.Errorf("acted on notes %v, want %v", notes.touched, want)
+			}
+		})
+	}
+}
+
+func TestCreateNoteParams_InheritFilter(t *testing.T) {
+	folder := &models.Folder{ID: 4, Name: "Work"}
+	tests := []struct {
+		name       string
+		inherit    bool
+		folder     *models.Folder
+		tag        string
+		wantFolder *models.Folder
+		wantTags   string
+	}{
+		{"folder and tag", true, folder, "urgent", folder, "urgent"},
+		{"tag outside a folder", true, nil, "urgent", nil, "urgent"},
+		{"no filter", true, nil, "", nil, ""},
+		{"inheritance off", false, folder, "urgent", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewApp(nil, nil, nil, nil, nil, &config.Config{})
+			a.cfg.UI.InheritFilterOnCreate = tt.inherit
+			a.cfg.UI.DefaultFolder = "Inbox"
+			a.currentFolder = tt.folder
+			a.tagFilter = tt.tag
+
+			params := a.createNoteParams("  Title  ", true)
+			if params.CurrentFolder != tt.wantFolder || params.Tags != tt.wantTags {
+				t.Errorf("folder %v, tags %q; want folder %v, tags %q", params.CurrentFolder, params.Tags, tt.wantFolder, tt.wantTags)
+			}
+			if params.Title != "Title" || !params.IsTodo || params.InboxFolder != "Inbox" {
+				t.Errorf("params = %+v", params)
+			}
+		})
+	}
+}
+
+func TestTagFilterDialog(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"set", "#Work ", "work"},
+		{"clear", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewApp(nil, nil, nil, nil, nil, &config.Config{})
+			a.tagFilter = "old"
+			a.handleDialogResult(messages.DialogResultMsg{Type: constants.DialogTypeFilterTag, Confirmed: true, Value: tt.value})
+			if a.tagFilter != tt.want {
+				t.Errorf("tagFilter = %q, want %q", a.tagFilter, tt.want)
+			}
+			if got := a.searchOptions().Tag; got != tt.want {
+				t.Errorf("search tag = %q, want %q", got, tt.want)
+			}
+			if got := a.reloadParams().Tag; got != tt.want {
+				t.Errorf("reload tag = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// appWithNotes returns an app listing notes with IDs 1 to count, the one
+// with ID selected
+func appWithNotes(count int, selected int64) *App {
+	a := NewApp(nil, nil, nil, nil, nil, &config.Config{})
+	a.currentFilter = constants.FilterAll
+	for i := 1; i <= count; i++ {
+		a.notes = append(a.notes, &models.Note{ID: int64(i), Title: fmt.Sprintf("note %d", i)})
+	}
+	a.noteList.SetSize(60, 20)
+	a.noteList.SetNotes(a.notes)
+	a.noteList.SelectNote(selected)
+	a.updatePreview()
+	return a
+}
+
+func TestHandleNoteDeleted_SelectsNeighbor(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int
+		deleted int64
+		want    int64 // 0 when nothing is left to select
+	}{
+		{"top", 5, 1, 2},
+		{"middle", 5, 3, 4},
+		{"end", 5, 5, 4},
+		{"only note", 1, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := appWithNotes(tt.count, tt.deleted)
+
+			a.handleNoteDeleted(messages.NoteDeletedMsg{NoteID: tt.deleted})
+			if got := selectedID(a); got != tt.want {
+				t.Errorf("selected note %d after delete, want %d", got, tt.want)
+			}
+
+			// The reload that follows keeps the same note selected
+			a.Update(messages.DataLoadedMsg{Notes: append([]*models.Note(nil), a.notes...)})
+			if got := selectedID(a); got != tt.want {
+				t.Errorf("selected note %d after reload, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// selectedID returns the ID of the previewed note, 0 when there is none
+func selectedID(a *App) int64 {
+	if a.currentNote == nil {
+		return 0
+	}
+	return a.currentNote.ID
+}
+
+func TestShowView_Filters(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{constants.FilterAll, true},
+		{constants.FilterTodos, true},
+		{constants.FilterStarred, true},
+		{constants.FilterToday, true},
+		{constants.FilterRecent, true},
+		{constants.FilterReview, true},
+		{constants.FilterUnfiled, true},
+		{"trash", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			a := NewApp(nil, nil, nil, nil, nil, &config.Config{})
+			a.currentFilter = constants.FilterAll
+
+			_, ok := a.showView(sessionState{Filter: tt.filter})
+			if ok != tt.want {
+				t.Fatalf("showView(%q) ok = %v, want %v", tt.filter, ok, tt.want)
+			}
+			if ok && a.currentFilter != tt.filter {
+				t.Errorf("current filter = %q, want %q", a.currentFilter, tt.filter)
+			}
+		})
+	}
+}
+
+func TestRequireName(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		maxLength int
+		wantErr   bool
+	}{
+		{"blank", "   ", 10, true},
+		{"at the limit", strings.Repeat("é", 10), 10, false},
+		{"over the limit", strings.Repeat("é", 11), 10, true},
+		{"surrounding space ignored", "  " + strings.Repeat("a", 10) + "  ", 10, false},
+		{"above the folder limit", strings.Repeat("a", constants.MaxNameLength+50), 200, false},
+		{"no limit", strings.Repeat("a", 1000), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := requireName("Title", tt.maxLength)(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("requireName(%d)(%q) error = %v, want error %v", tt.maxLength, tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewNoteDialog_TitleLimit(t *testing.T) {
+	long := strings.Repeat("a", constants.MaxNameLength+1)
+	tests := []struct {
+		name      string
+		maxLength int
+		title     string
+		want      string
+	}{
+		{"longer than a folder name", 200, long, long},
+		{"at the configured limit", 200, strings.Repeat("a", 200), strings.Repeat("a", 200)},
+		{"cut at the configured limit", 50, strings.Repeat("a", 51), strings.Repeat("a", 50)},
+		{"no limit", 0, strings.Repeat("a", 500), strings.Repeat("a", 500)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Database.TitleMaxLength = tt.maxLength
+			a := NewApp(nil, nil, nil, nil, nil, cfg)
+
+			for _, show := range []func(){a.showNewNoteDialog, a.showNewTodoDialog} {
+				show()
+				a.dialog.SetInputValue(tt.title)
+				_, cmd := a.dialog.Update(tea.KeyMsg{Type: tea.KeyEnter})
+				if cmd == nil {
+					t.Fatalf("dialog rejected a %d character title", len(tt.title))
+				}
+				got, ok := cmd().(messages.DialogResultMsg)
+				if !ok || !got.Confirmed {
+					t.Fatalf("dialog result = %+v, want it confirmed", got)
+				}
+				if got.Value != tt.want {
+					t.Errorf("%s dialog title has %d characters, want %d", got.Type, len(got.Value), len(tt.want))
+				}
+			}
+		})
+	}
+}
+
+func TestHandleEditorFinished_RemovesTempFile(t *testing.T) {
+	tests := []struct {
+		name string
+		note *models.Note
+		err  error
+	}{
+		{"editor failed", &models.Note{ID: 1, Title: "secret"}, errors.New("exit status 1")},
+		{"no note", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmp := filepath.Join(t.TempDir(), "kiroku-edit.md")
+			if err := os.WriteFile(tmp, []byte("# secret\n\nplain text"), 0600); err != nil {
+				t.Fatalf("write temp file: %v", err)
+			}
+			a := NewApp(nil, nil, nil, nil, nil, &config.Config{})
+			a.currentNote = tt.note
+			a.editingTempFile = tmp
+
+			a.handleEditorFinished(messages.EditorFinishedMsg{TempFile: tmp, Err: tt.err})
+
+			if _, err := os.Stat(tmp); !os.IsNotExist(err) {
+				t.Errorf("temp file still exists (stat error = %v)", err)
+			}
+			if a.editingTempFile != "" {
+				t.Errorf("editingTempFile = %q, want it cleared", a.editingTempFile)
+			}
+		})
+	}
+}
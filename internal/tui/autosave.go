@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tranducquang/kiroku/internal/logging"
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/tui/commands"
+)
+
+// unsavedEdit is a label, priority or due date change shown in the list
+// but not yet written. base is the note as it was before the first change.
+type unsavedEdit struct {
+	note *models.Note
+	base models.Note
+}
+
+// changed reports whether the edit differs from what is saved, so cycling
+// a label back to where it started writes nothing
+func (e *unsavedEdit) changed() bool {
+	return e.note.Label != e.base.Label ||
+		e.note.Priority != e.base.Priority ||
+		!models.SameDueDate(e.note.DueDate, e.base.DueDate)
+}
+
+// editFields applies change to note. With autosave on, the change shows at
+// once and is written after the configured delay, restarted by each further
+// change; with it off, save is returned to write the change now.
+func (a *App) editFields(note *models.Note, change func(*models.Note), save tea.Cmd) tea.Cmd {
+	delay := a.cfg.UI.AutosaveDelay
+	if delay <= 0 {
+		return save
+	}
+
+	// A reload hands out new note values, so an edit to another value,
+	// even of the same note, saves the previous one first
+	var flush tea.Cmd
+	if a.unsaved != nil && a.unsaved.note != note {
+		flush = a.flushEdits()
+	}
+	if a.unsaved == nil {
+		a.unsaved = &unsavedEdit{note: note, base: *note}
+	}
+
+	change(note)
+	a.preview.SetNote(a.previewNote(a.currentNote))
+	a.autosaveSeq++
+	return tea.Batch(flush, commands.AutosaveAfter(a.autosaveSeq, time.Duration(delay)*time.Millisecond))
+}
+
+// flushEdits returns a command that writes the unsaved edit, or nil when
+// there is nothing to write
+func (a *App) flushEdits() tea.Cmd {
+	edit := a.unsaved
+	a.unsaved = nil
+	if edit == nil || !edit.changed() {
+		return nil
+	}
+	logging.Debug().Int64("note_id", edit.note.ID).Msg("Saving pending note edits")
+	return a.runner.SaveNoteFields(a.noteService, edit.base, *edit.note)
+}
+
+// flushThen writes any unsaved edit before running cmd
+func (a *App) flushThen(cmd tea.Cmd) tea.Cmd {
+	flush := a.flushEdits()
+	if flush == nil {
+		return cmd
+	}
+	return tea.Sequence(flush, cmd)
+}
@@ -4,27 +4,71 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/tranducquang/kiroku/internal/config"
 	"github.com/tranducquang/kiroku/internal/models"
 	"github.com/tranducquang/kiroku/internal/tui/constants"
 	"github.com/tranducquang/kiroku/internal/tui/messages"
 )
 
+// Runner builds the commands that query the services. Every command's
+// queries run under the context the Runner was made with, so canceling
+// it cancels the queries still in flight.
+type Runner struct {
+	ctx     context.Context
+	timeout time.Duration
+}
+
+// NewRunner returns a Runner whose commands derive their context from
+// ctx, each with a deadline of timeout when it is positive.
+func NewRunner(ctx context.Context, timeout time.Duration) *Runner {
+	return &Runner{ctx: ctx, timeout: timeout}
+}
+
+// opContext returns the context for one command's queries
+func (r *Runner) opContext() (context.Context, context.CancelFunc) {
+	if r.timeout <= 0 {
+		return context.WithCancel(r.ctx)
+	}
+	return context.WithTimeout(r.ctx, r.timeout)
+}
+
 // NoteService defines the interface for note operations.
 type NoteService interface {
+	GetByID(ctx context.Context, id int64) (*models.Note, error)
 	GetAllNotes(ctx context.Context) ([]*models.Note, error)
+	List(ctx context.Context, opts models.ListOptions) ([]*models.Note, error)
 	GetTodos(ctx context.Context, showCompleted bool) ([]*models.Note, error)
 	GetStarred(ctx context.Context) ([]*models.Note, error)
+	GetToday(ctx context.Context) ([]*models.Note, error)
+	GetRecent(ctx context.Context, limit int) ([]*models.Note, error)
+	GetStale(ctx context.Context, cutoff time.Time, includeStarred bool) ([]*models.Note, error)
 	GetByFolder(ctx context.Context, folderID int64) ([]*models.Note, error)
+	GetUnfiled(ctx context.Context) ([]*models.Note, error)
+	Count(ctx context.Context, opts models.ListOptions) (int, error)
 	Create(ctx context.Context, note *models.Note) error
 	Update(ctx context.Context, note *models.Note) error
 	Delete(ctx context.Context, id int64) error
 	ToggleStar(ctx context.Context, id int64) error
 	ToggleTodo(ctx context.Context, id int64) error
+	MarkDone(ctx context.Context, ids []int64, done bool) (int, error)
+	ToggleLock(ctx context.Context, id int64) error
 	SetPriority(ctx context.Context, id int64, priority int) error
+	SwapOrder(ctx context.Context, id, otherID int64) error
+	SetLabel(ctx context.Context, id int64, label string) error
+	SetDueDate(ctx context.Context, id int64, due *time.Time) error
+	MoveToFolder(ctx context.Context, noteID, folderID int64) error
+	Capture(ctx context.Context, text string) (*models.Note, error)
+	Scratch(ctx context.Context) (*models.Note, error)
+	AppendContent(ctx context.Context, id int64, text string) error
+	Reveal(note *models.Note, passphrase string) (string, error)
+	UpdateEncrypted(ctx context.Context, note *models.Note, passphrase string) error
+	Search(ctx context.Context, query string, opts models.ListOptions) ([]models.SearchResult, error)
 }
 
 // FolderService defines the interface for folder operations.
@@ -33,6 +77,8 @@ type FolderService interface {
 	Create(ctx context.Context, folder *models.Folder) error
 	Delete(ctx context.Context, id int64) error
 	ToggleStar(ctx context.Context, id int64) error
+	CountNotesRecursive(ctx context.Context, id int64) (int, error)
+	GetOrCreateByName(ctx context.Context, name string) (*models.Folder, error)
 }
 
 // TemplateService defines the interface for template operations.
@@ -40,31 +86,29 @@ type TemplateService interface {
 	List(ctx context.Context) ([]models.Template, error)
 }
 
-// SearchService defines the interface for search operations.
-type SearchService interface {
-	Search(ctx context.Context, query string, opts models.ListOptions) ([]models.SearchResult, error)
-}
-
 // LoadDataParams contains parameters for loading initial data.
 type LoadDataParams struct {
 	FolderService   FolderService
 	NoteService     NoteService
 	TemplateService TemplateService
+	// Notes selects the notes to load, the same way ReloadNotes does
+	Notes ReloadNotesParams
 }
 
 // LoadData returns a command that loads all initial data.
-func LoadData(params LoadDataParams) tea.Cmd {
+func (r *Runner) LoadData(params LoadDataParams) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
+		ctx, cancel := r.opContext()
+		defer cancel()
 
 		folders, err := params.FolderService.GetTree(ctx)
 		if err != nil {
 			return messages.NewError(err, "load folders")
 		}
 
-		notes, err := params.NoteService.GetAllNotes(ctx)
+		notes, starredFolders, err := loadNotes(ctx, params.Notes)
 		if err != nil {
-			return messages.NewError(err, "load notes")
+			return messages.NewError(err, messages.ContextLoadNotes)
 		}
 
 		templates, err := params.TemplateService.List(ctx)
@@ -72,14 +116,6 @@ func LoadData(params LoadDataParams) tea.Cmd {
 			return messages.NewError(err, "load templates")
 		}
 
-		// If we can get starred folders, include them
-		var starredFolders []*models.Folder
-		if fs, ok := params.FolderService.(interface {
-			GetStarred(ctx context.Context) ([]*models.Folder, error)
-		}); ok {
-			starredFolders, _ = fs.GetStarred(ctx)
-		}
-
 		return messages.DataLoadedMsg{
 			Folders:        folders,
 			Notes:          notes,
@@ -96,65 +132,145 @@ type ReloadNotesParams struct {
 	CurrentFilter string
 	CurrentFolder *models.Folder
 	ShowCompleted bool
+	// HideDone leaves completed todos out of folder views
+	HideDone bool
+	// RecentLimit is how many notes the Recent view lists
+	RecentLimit int
+	// ReviewAge is how long notes in the Review view have gone unchanged
+	ReviewAge string
+	// ManualOrder sorts the Todos view by manual order instead of priority
+	ManualOrder bool
+	// Tag narrows the view to notes carrying it; empty lists them all
+	Tag string
 }
 
 // ReloadNotes returns a command that reloads notes based on the current filter.
-func ReloadNotes(params ReloadNotesParams) tea.Cmd {
+func (r *Runner) ReloadNotes(params ReloadNotesParams) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
-		var notes []*models.Note
-		var folders []*models.Folder
-		var err error
+		ctx, cancel := r.opContext()
+		defer cancel()
+		notes, folders, err := loadNotes(ctx, params)
+		if err != nil {
+			return messages.NewError(err, messages.ContextReloadNotes)
+		}
 
-		switch params.CurrentFilter {
-		case constants.FilterAll:
-			notes, err = params.NoteService.GetAllNotes(ctx)
-		case constants.FilterTodos:
+		return messages.DataLoadedMsg{
+			Notes:          notes,
+			StarredFolders: folders,
+		}
+	}
+}
+
+// loadNotes fetches the notes for the current filter, plus the starred
+// folders when that filter is Starred
+func loadNotes(ctx context.Context, params ReloadNotesParams) ([]*models.Note, []*models.Folder, error) {
+	var notes []*models.Note
+	var folders []*models.Folder
+	var err error
+
+	switch params.CurrentFilter {
+	case constants.FilterAll:
+		notes, err = params.NoteService.GetAllNotes(ctx)
+	case constants.FilterTodos:
+		if params.ManualOrder {
+			isTodo := true
+			opts := models.ListOptions{IsTodo: &isTodo, OrderBy: "position"}
+			if !params.ShowCompleted {
+				done := false
+				opts.IsDone = &done
+			}
+			notes, err = params.NoteService.List(ctx, opts)
+		} else {
 			notes, err = params.NoteService.GetTodos(ctx, params.ShowCompleted)
-		case constants.FilterStarred:
-			notes, err = params.NoteService.GetStarred(ctx)
-			if err == nil && params.FolderService != nil {
-				// We need to type assert or check if FolderService has GetStarred
-				// Since we defined the interface in this package, we need to update it
-				if fs, ok := params.FolderService.(interface {
-					GetStarred(ctx context.Context) ([]*models.Folder, error)
-				}); ok {
-					folders, err = fs.GetStarred(ctx)
-				}
+		}
+	case constants.FilterToday:
+		notes, err = params.NoteService.GetToday(ctx)
+	case constants.FilterRecent:
+		notes, err = params.NoteService.GetRecent(ctx, params.RecentLimit)
+	case constants.FilterReview:
+		var cutoff time.Time
+		if cutoff, err = models.ParseAge(params.ReviewAge, time.Now()); err == nil {
+			notes, err = params.NoteService.GetStale(ctx, cutoff, false)
+		}
+	case constants.FilterUnfiled:
+		notes, err = params.NoteService.GetUnfiled(ctx)
+	case constants.FilterStarred:
+		notes, err = params.NoteService.GetStarred(ctx)
+		if err == nil && params.FolderService != nil {
+			// We need to type assert or check if FolderService has GetStarred
+			// Since we defined the interface in this package, we need to update it
+			if fs, ok := params.FolderService.(interface {
+				GetStarred(ctx context.Context) ([]*models.Folder, error)
+			}); ok {
+				folders, err = fs.GetStarred(ctx)
 			}
-		default:
-			if params.CurrentFolder != nil {
-				notes, err = params.NoteService.GetByFolder(ctx, params.CurrentFolder.ID)
-			} else {
-				notes, err = params.NoteService.GetAllNotes(ctx)
+		}
+	default:
+		if params.CurrentFolder != nil {
+			notes, err = params.NoteService.GetByFolder(ctx, params.CurrentFolder.ID)
+			if err == nil && params.HideDone {
+				notes = withoutDoneTodos(notes)
 			}
+		} else {
+			notes, err = params.NoteService.GetAllNotes(ctx)
 		}
+	}
+	if err == nil && params.Tag != "" {
+		notes = withTag(notes, params.Tag)
+	}
+	return notes, folders, err
+}
 
+// CountUnfiled returns a command that counts notes outside any folder.
+func (r *Runner) CountUnfiled(noteService NoteService) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := r.opContext()
+		defer cancel()
+		count, err := noteService.Count(ctx, models.ListOptions{Unfiled: true})
 		if err != nil {
-			return messages.NewError(err, "reload notes")
+			return messages.NewError(err, "check unfiled notes")
 		}
+		return messages.UnfiledCountMsg{Count: count}
+	}
+}
 
-		return messages.DataLoadedMsg{
-			Notes:          notes,
-			StarredFolders: folders,
+// withoutDoneTodos drops completed todos, keeping every other note
+func withoutDoneTodos(notes []*models.Note) []*models.Note {
+	kept := make([]*models.Note, 0, len(notes))
+	for _, note := range notes {
+		if !(note.IsTodo && note.IsDone) {
+			kept = append(kept, note)
 		}
 	}
+	return kept
 }
 
 // SearchParams contains parameters for search.
 type SearchParams struct {
-	SearchService SearchService
-	Query         string
+	NoteService NoteService
+	Query       string
+	Options     models.ListOptions
+	// View, when set, keeps only the matches the view lists. Today and
+	// Recent cannot be put as list options, so they are scoped this way.
+	View *ReloadNotesParams
 }
 
 // Search returns a command that performs a search.
-func Search(params SearchParams) tea.Cmd {
+func (r *Runner) Search(params SearchParams) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
-		results, err := params.SearchService.Search(ctx, params.Query, models.ListOptions{})
+		ctx, cancel := r.opContext()
+		defer cancel()
+		results, err := params.NoteService.Search(ctx, params.Query, params.Options)
 		if err != nil {
 			return messages.NewError(err, "search")
 		}
+		if params.View != nil {
+			listed, _, err := loadNotes(ctx, *params.View)
+			if err != nil {
+				return messages.NewError(err, "search")
+			}
+			results = inView(results, listed)
+		}
 
 		notes := make([]*models.Note, len(results))
 		for i, r := range results {
@@ -169,6 +285,42 @@ func Search(params SearchParams) tea.Cmd {
 	}
 }
 
+// withTag keeps the notes carrying tag
+func withTag(notes []*models.Note, tag string) []*models.Note {
+	kept := make([]*models.Note, 0, len(notes))
+	for _, note := range notes {
+		if note.HasTag(tag) {
+			kept = append(kept, note)
+		}
+	}
+	return kept
+}
+
+// inView keeps the search results for the listed notes, in rank order
+func inView(results []models.SearchResult, listed []*models.Note) []models.SearchResult {
+	ids := make(map[int64]bool, len(listed))
+	for _, note := range listed {
+		ids[note.ID] = true
+	}
+	kept := results[:0]
+	for _, r := range results {
+		if ids[r.Note.ID] {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// SaveConfig returns a command that persists the configuration to disk.
+func SaveConfig(cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		if err := cfg.Save(); err != nil {
+			return messages.NewError(err, "save config")
+		}
+		return nil
+	}
+}
+
 // ClearStatusAfter returns a command that clears the status after a duration.
 func ClearStatusAfter(d time.Duration) tea.Cmd {
 	return tea.Tick(d, func(time.Time) tea.Msg {
@@ -176,91 +328,393 @@ func ClearStatusAfter(d time.Duration) tea.Cmd {
 	})
 }
 
+// errNoClipboard explains a missing clipboard in terms the user can act on.
+var errNoClipboard = errors.New("no clipboard available (install xclip, xsel or wl-clipboard)")
+
+// CopyToClipboard returns a command that copies text to the system
+// clipboard. what names the copied text in the confirmation.
+func CopyToClipboard(text, what string) tea.Cmd {
+	return func() tea.Msg {
+		if clipboard.Unsupported {
+			return messages.NewError(errNoClipboard, "copy")
+		}
+		if err := clipboard.WriteAll(text); err != nil {
+			return messages.NewError(err, "copy")
+		}
+		return messages.ClipboardCopiedMsg{What: what}
+	}
+}
+
+// SaveSessionAfter returns a command that asks for the session to be saved after a duration.
+func SaveSessionAfter(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return messages.SessionSaveMsg{}
+	})
+}
+
+// SettlePreviewAfter returns a command that asks for the preview to render
+// the selection after a duration.
+func SettlePreviewAfter(seq int, d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return messages.PreviewSettleMsg{Seq: seq}
+	})
+}
+
+// AutosaveAfter returns a command that asks for pending edits to be saved after a duration.
+func AutosaveAfter(seq int, d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return messages.AutosaveMsg{Seq: seq}
+	})
+}
+
+// DismissToastAfter returns a command that dismisses a toast after a duration.
+func DismissToastAfter(id int, d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return messages.ToastExpiredMsg{ID: id}
+	})
+}
+
 // CreateNoteParams contains parameters for creating a note.
 type CreateNoteParams struct {
 	NoteService   NoteService
+	FolderService FolderService
 	Title         string
 	IsTodo        bool
 	CurrentFolder *models.Folder
+	// InboxFolder names the folder for notes created outside a folder;
+	// it is created on first use. Empty leaves such notes unfiled.
+	InboxFolder string
+	// Tags are given to the new note
+	Tags string
 }
 
-// CreateNote returns a command that creates a new note.
-func CreateNote(params CreateNoteParams) tea.Cmd {
+// CreateNote returns a command that creates a new note in the current
+// folder, else the inbox folder, else no folder.
+func (r *Runner) CreateNote(params CreateNoteParams) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
+		ctx, cancel := r.opContext()
+		defer cancel()
 
 		var folderID *int64
-		if params.CurrentFolder != nil {
+		switch {
+		case params.CurrentFolder != nil:
 			folderID = &params.CurrentFolder.ID
+		case params.InboxFolder != "":
+			inbox, err := params.FolderService.GetOrCreateByName(ctx, params.InboxFolder)
+			if err != nil {
+				return messages.NewError(err, "create note")
+			}
+			folderID = &inbox.ID
 		}
 
 		note := &models.Note{
 			Title:    params.Title,
 			FolderID: folderID,
 			IsTodo:   params.IsTodo,
+			Tags:     params.Tags,
 		}
 
 		if err := params.NoteService.Create(ctx, note); err != nil {
 			return messages.NewError(err, "create note")
 		}
 
-		return messages.NoteCreatedMsg{Note: note}
+		created := *note
+		return messages.NoteCreatedMsg{
+			Note:       note,
+			NoteChange: messages.NoteChange{After: &created},
+		}
 	}
 }
 
 // DeleteNote returns a command that deletes a note.
-func DeleteNote(noteService NoteService, noteID int64) tea.Cmd {
+func (r *Runner) DeleteNote(noteService NoteService, noteID int64) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
+		ctx, cancel := r.opContext()
+		defer cancel()
+		before, err := noteService.GetByID(ctx, noteID)
+		if err != nil {
+			return messages.NewError(err, "delete note")
+		}
 		if err := noteService.Delete(ctx, noteID); err != nil {
 			return messages.NewError(err, "delete note")
 		}
-		return messages.NoteDeletedMsg{NoteID: noteID}
+		return messages.NoteDeletedMsg{
+			NoteID:     noteID,
+			NoteChange: messages.NoteChange{Before: before},
+		}
+	}
+}
+
+// changeNote runs a mutation on a single note and captures the note
+// before and after it so the change can be undone.
+func (r *Runner) changeNote(noteService NoteService, noteID int64, mutate func(ctx context.Context) error) (messages.NoteChange, error) {
+	ctx, cancel := r.opContext()
+	defer cancel()
+	before, err := noteService.GetByID(ctx, noteID)
+	if err != nil {
+		return messages.NoteChange{}, err
+	}
+	if err := mutate(ctx); err != nil {
+		return messages.NoteChange{}, err
+	}
+	after, err := noteService.GetByID(ctx, noteID)
+	if err != nil {
+		return messages.NoteChange{}, err
+	}
+	return messages.NoteChange{Before: before, After: after}, nil
+}
+
+// updateNoteCmd wraps changeNote into a command reporting NoteUpdatedMsg.
+func (r *Runner) updateNoteCmd(noteService NoteService, noteID int64, errContext string, mutate func(ctx context.Context) error) tea.Cmd {
+	return func() tea.Msg {
+		change, err := r.changeNote(noteService, noteID, mutate)
+		if err != nil {
+			return messages.NewError(err, errContext)
+		}
+		return messages.NoteUpdatedMsg{Note: change.After, NoteChange: change}
+	}
+}
+
+// RestoreNote returns a command that puts a note back into the target
+// state: deleting it when target is nil, recreating it when current is
+// nil, and overwriting it otherwise.
+func (r *Runner) RestoreNote(noteService NoteService, label string, current, target *models.Note) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := r.opContext()
+		defer cancel()
+
+		switch {
+		case target == nil:
+			if err := noteService.Delete(ctx, current.ID); err != nil {
+				return messages.NewError(err, label)
+			}
+			return messages.NoteRestoredMsg{Label: label, OldID: current.ID}
+
+		case current == nil:
+			note := *target
+			note.ID = 0
+			if err := noteService.Create(ctx, &note); err != nil {
+				return messages.NewError(err, label)
+			}
+			return messages.NoteRestoredMsg{Label: label, OldID: target.ID, Note: &note}
+
+		default:
+			note := *target
+			if err := noteService.Update(ctx, &note); err != nil {
+				return messages.NewError(err, label)
+			}
+			return messages.NoteRestoredMsg{Label: label, OldID: target.ID, Note: &note}
+		}
 	}
 }
 
 // ToggleStar returns a command that toggles a note's starred status.
-func ToggleStar(noteService NoteService, noteID int64) tea.Cmd {
+func (r *Runner) ToggleStar(noteService NoteService, noteID int64) tea.Cmd {
+	return r.updateNoteCmd(noteService, noteID, "toggle star", func(ctx context.Context) error {
+		return noteService.ToggleStar(ctx, noteID)
+	})
+}
+
+// ToggleLock returns a command that locks or unlocks a note.
+func (r *Runner) ToggleLock(noteService NoteService, noteID int64) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
-		if err := noteService.ToggleStar(ctx, noteID); err != nil {
-			return messages.NewError(err, "toggle star")
+		ctx, cancel := r.opContext()
+		defer cancel()
+		if err := noteService.ToggleLock(ctx, noteID); err != nil {
+			return messages.NewError(err, "toggle lock")
 		}
 		return messages.NoteUpdatedMsg{}
 	}
 }
 
 // ToggleTodo returns a command that toggles a todo's done status.
-func ToggleTodo(noteService NoteService, noteID int64) tea.Cmd {
+func (r *Runner) ToggleTodo(noteService NoteService, noteID int64) tea.Cmd {
+	return r.updateNoteCmd(noteService, noteID, "toggle todo", func(ctx context.Context) error {
+		return noteService.ToggleTodo(ctx, noteID)
+	})
+}
+
+// CompleteTodos returns a command that marks the given todos done in one
+// transaction, so either all of them are completed or none are.
+func (r *Runner) CompleteTodos(noteService NoteService, ids []int64) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
-		if err := noteService.ToggleTodo(ctx, noteID); err != nil {
-			return messages.NewError(err, "toggle todo")
+		ctx, cancel := r.opContext()
+		defer cancel()
+		n, err := noteService.MarkDone(ctx, ids, true)
+		if err != nil {
+			return messages.NewError(err, "complete todos")
 		}
-		return messages.NoteUpdatedMsg{}
+		return messages.TodosCompletedMsg{Count: n}
+	}
+}
+
+// MoveTodo returns a command that swaps a todo with its neighbor in the
+// manual todo order.
+func (r *Runner) MoveTodo(noteService NoteService, noteID, otherID int64) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := r.opContext()
+		defer cancel()
+		if err := noteService.SwapOrder(ctx, noteID, otherID); err != nil {
+			return messages.NewError(err, "reorder todo")
+		}
+		return messages.TodoReorderedMsg{NoteID: noteID}
 	}
 }
 
 // CyclePriority returns a command that cycles a note's priority.
-func CyclePriority(noteService NoteService, noteID int64, currentPriority int) tea.Cmd {
+func (r *Runner) CyclePriority(noteService NoteService, noteID int64, currentPriority int) tea.Cmd {
+	newPriority := (currentPriority + 1) % constants.PriorityMax
+	return r.updateNoteCmd(noteService, noteID, "set priority", func(ctx context.Context) error {
+		return noteService.SetPriority(ctx, noteID, newPriority)
+	})
+}
+
+// SetPriority returns a command that sets a note's priority.
+func (r *Runner) SetPriority(noteService NoteService, noteID int64, priority int) tea.Cmd {
+	return r.updateNoteCmd(noteService, noteID, "set priority", func(ctx context.Context) error {
+		return noteService.SetPriority(ctx, noteID, priority)
+	})
+}
+
+// CycleLabel returns a command that moves a note to the next label color.
+func (r *Runner) CycleLabel(noteService NoteService, noteID int64, currentLabel string) tea.Cmd {
+	newLabel := models.NextLabel(currentLabel)
+	return r.updateNoteCmd(noteService, noteID, "set label", func(ctx context.Context) error {
+		return noteService.SetLabel(ctx, noteID, newLabel)
+	})
+}
+
+// SaveNoteFields returns a command that writes the label, priority and due
+// date of note that differ from base, recorded as a single change.
+func (r *Runner) SaveNoteFields(noteService NoteService, base, note models.Note) tea.Cmd {
+	return r.updateNoteCmd(noteService, note.ID, "save note", func(ctx context.Context) error {
+		if note.Label != base.Label {
+			if err := noteService.SetLabel(ctx, note.ID, note.Label); err != nil {
+				return err
+			}
+		}
+		if note.Priority != base.Priority {
+			if err := noteService.SetPriority(ctx, note.ID, note.Priority); err != nil {
+				return err
+			}
+		}
+		if !models.SameDueDate(note.DueDate, base.DueDate) {
+			if err := noteService.SetDueDate(ctx, note.ID, note.DueDate); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetDueDate returns a command that sets or clears a note's due date.
+func (r *Runner) SetDueDate(noteService NoteService, noteID int64, due *time.Time) tea.Cmd {
+	return r.updateNoteCmd(noteService, noteID, "set due date", func(ctx context.Context) error {
+		return noteService.SetDueDate(ctx, noteID, due)
+	})
+}
+
+// OpenScratch returns a command that finds or creates the scratch note.
+func (r *Runner) OpenScratch(noteService NoteService) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
-		newPriority := (currentPriority + 1) % constants.PriorityMax
-		if err := noteService.SetPriority(ctx, noteID, newPriority); err != nil {
-			return messages.NewError(err, "set priority")
+		ctx, cancel := r.opContext()
+		defer cancel()
+		note, err := noteService.Scratch(ctx)
+		if err != nil {
+			return messages.NewError(err, "open scratch note")
 		}
-		return messages.NoteUpdatedMsg{}
+		return messages.ScratchLoadedMsg{Note: note}
+	}
+}
+
+// LoadLastNote returns a command that finds the most recently updated note.
+func (r *Runner) LoadLastNote(noteService NoteService) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := r.opContext()
+		defer cancel()
+		notes, err := noteService.GetRecent(ctx, 1)
+		if err != nil {
+			return messages.NewError(err, "load last note")
+		}
+		if len(notes) == 0 {
+			return messages.LastNoteLoadedMsg{}
+		}
+		return messages.LastNoteLoadedMsg{Note: notes[0]}
+	}
+}
+
+// Capture returns a command that appends text to today's daily note.
+func (r *Runner) Capture(noteService NoteService, text string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := r.opContext()
+		defer cancel()
+		note, err := noteService.Capture(ctx, text)
+		if err != nil {
+			return messages.NewError(err, "capture")
+		}
+		return messages.NoteCapturedMsg{Note: note}
+	}
+}
+
+// AppendContent returns a command that appends text to a note.
+func (r *Runner) AppendContent(noteService NoteService, noteID int64, text string) tea.Cmd {
+	return r.updateNoteCmd(noteService, noteID, "append content", func(ctx context.Context) error {
+		return noteService.AppendContent(ctx, noteID, text)
+	})
+}
+
+// MoveNote returns a command that moves a note to a folder.
+func (r *Runner) MoveNote(noteService NoteService, noteID int64, folder *models.Folder) tea.Cmd {
+	return func() tea.Msg {
+		change, err := r.changeNote(noteService, noteID, func(ctx context.Context) error {
+			return noteService.MoveToFolder(ctx, noteID, folder.ID)
+		})
+		if err != nil {
+			return messages.NewError(err, "move note")
+		}
+		return messages.NoteMovedMsg{NoteID: noteID, Folder: folder, NoteChange: change}
 	}
 }
 
 // UpdateNote returns a command that updates a note.
-func UpdateNote(noteService NoteService, note *models.Note) tea.Cmd {
+func (r *Runner) UpdateNote(noteService NoteService, note *models.Note) tea.Cmd {
+	return r.updateNoteCmd(noteService, note.ID, "update note", func(ctx context.Context) error {
+		return noteService.Update(ctx, note)
+	})
+}
+
+// UnlockNote returns a command that opens an encrypted note with a
+// passphrase. Deriving the key takes a moment, so it runs off the UI loop.
+func UnlockNote(noteService NoteService, note *models.Note, passphrase string) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
-		if err := noteService.Update(ctx, note); err != nil {
+		content, err := noteService.Reveal(note, passphrase)
+		if err != nil {
+			return messages.NewError(err, "unlock note")
+		}
+		return messages.NoteUnlockedMsg{
+			NoteID:     note.ID,
+			Sealed:     note.Content,
+			Content:    content,
+			Passphrase: passphrase,
+		}
+	}
+}
+
+// UpdateEncryptedNote returns a command that saves an edit to an unlocked
+// note, sealing its plain content with passphrase again.
+func (r *Runner) UpdateEncryptedNote(noteService NoteService, note *models.Note, passphrase string) tea.Cmd {
+	content := note.Content
+	return func() tea.Msg {
+		change, err := r.changeNote(noteService, note.ID, func(ctx context.Context) error {
+			return noteService.UpdateEncrypted(ctx, note, passphrase)
+		})
+		if err != nil {
 			return messages.NewError(err, "update note")
 		}
-		return messages.NoteUpdatedMsg{Note: note}
+		return messages.EncryptedNoteSavedMsg{
+			NoteUpdatedMsg: messages.NoteUpdatedMsg{Note: change.After, NoteChange: change},
+			Content:        content,
+		}
 	}
 }
 
@@ -272,9 +726,10 @@ type CreateFolderParams struct {
 }
 
 // CreateFolder returns a command that creates a new folder.
-func CreateFolder(params CreateFolderParams) tea.Cmd {
+func (r *Runner) CreateFolder(params CreateFolderParams) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
+		ctx, cancel := r.opContext()
+		defer cancel()
 
 		folder := &models.Folder{
 			Name:     params.Name,
@@ -285,14 +740,15 @@ func CreateFolder(params CreateFolderParams) tea.Cmd {
 			return messages.NewError(err, "create folder")
 		}
 
-		return ReloadFolders(params.FolderService)()
+		return r.ReloadFolders(params.FolderService)()
 	}
 }
 
 // ReloadFolders returns a command that reloads folders.
-func ReloadFolders(folderService FolderService) tea.Cmd {
+func (r *Runner) ReloadFolders(folderService FolderService) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
+		ctx, cancel := r.opContext()
+		defer cancel()
 		folders, err := folderService.GetTree(ctx)
 		if err != nil {
 			return messages.NewError(err, "reload folders")
@@ -313,25 +769,41 @@ func ReloadFolders(folderService FolderService) tea.Cmd {
 }
 
 // ToggleFolderStar returns a command that toggles a folder's starred status.
-func ToggleFolderStar(folderService FolderService, folderID int64) tea.Cmd {
+func (r *Runner) ToggleFolderStar(folderService FolderService, folderID int64) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
+		ctx, cancel := r.opContext()
+		defer cancel()
 		if err := folderService.ToggleStar(ctx, folderID); err != nil {
 			return messages.NewError(err, "toggle folder star")
 		}
 		// Reload folders to update UI
-		return ReloadFolders(folderService)()
+		return r.ReloadFolders(folderService)()
+	}
+}
+
+// CountFolderNotes returns a command that counts the notes a folder
+// deletion would affect, including those in subfolders.
+func (r *Runner) CountFolderNotes(folderService FolderService, folder *models.Folder) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := r.opContext()
+		defer cancel()
+		count, err := folderService.CountNotesRecursive(ctx, folder.ID)
+		if err != nil {
+			return messages.NewError(err, "count folder notes")
+		}
+		return messages.FolderNoteCountMsg{Folder: folder, Count: count}
 	}
 }
 
 // DeleteFolder returns a command that deletes a folder.
-func DeleteFolder(folderService FolderService, folderID int64) tea.Cmd {
+func (r *Runner) DeleteFolder(folderService FolderService, folderID int64) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
+		ctx, cancel := r.opContext()
+		defer cancel()
 		if err := folderService.Delete(ctx, folderID); err != nil {
 			return messages.NewError(err, "delete folder")
 		}
 		// Reload folders
-		return ReloadFolders(folderService)()
+		return r.ReloadFolders(folderService)()
 	}
 }
@@ -0,0 +1,251 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/tui/constants"
+	"github.com/tranducquang/kiroku/internal/tui/messages"
+)
+
+// fakeNotes answers the few NoteService calls a test makes; any other
+// call panics on the nil embedded interface
+type fakeNotes struct {
+	NoteService
+	all     []*models.Note
+	today   []*models.Note
+	recent  []*models.Note
+	starred []*models.Note
+	matches []models.SearchResult
+}
+
+func (f *fakeNotes) GetAllNotes(ctx context.Context) ([]*models.Note, error) {
+	return f.all, nil
+}
+
+func (f *fakeNotes) GetToday(ctx context.Context) ([]*models.Note, error) {
+	return f.today, nil
+}
+
+func (f *fakeNotes) GetRecent(ctx context.Context, limit int) ([]*models.Note, error) {
+	if limit < len(f.recent) {
+		return f.recent[:limit], nil
+	}
+	return f.recent, nil
+}
+
+func (f *fakeNotes) GetStarred(ctx context.Context) ([]*models.Note, error) {
+	return f.starred, nil
+}
+
+func (f *fakeNotes) Search(ctx context.Context, query string, opts models.ListOptions) ([]models.SearchResult, error) {
+	return append([]models.SearchResult(nil), f.matches...), nil
+}
+
+// testRunner runs commands without a deadline
+var testRunner = NewRunner(context.Background(), 0)
+
+func notesWithIDs(ids ...int64) []*models.Note {
+	notes := make([]*models.Note, len(ids))
+	for i, id := range ids {
+		notes[i] = &models.Note{ID: id}
+	}
+	return notes
+}
+
+func resultIDs(notes []*models.Note) []int64 {
+	ids := []int64{}
+	for _, note := range notes {
+		ids = append(ids, note.ID)
+	}
+	return ids
+}
+
+func TestSearch_ScopedToView(t *testing.T) {
+	svc := &fakeNotes{
+		today:  notesWithIDs(4, 2),
+		recent: notesWithIDs(3, 1, 2),
+	}
+	for _, id := range []int64{1, 2, 3, 4} {
+		svc.matches = append(svc.matches, models.SearchResult{Note: models.Note{ID: id}})
+	}
+
+	tests := []struct {
+		name string
+		view *ReloadNotesParams
+		want []int64
+	}{
+		{"unscoped", nil, []int64{1, 2, 3, 4}},
+		{"today", &ReloadNotesParams{CurrentFilter: constants.FilterToday}, []int64{2, 4}},
+		{"recent", &ReloadNotesParams{CurrentFilter: constants.FilterRecent, RecentLimit: 2}, []int64{1, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.view != nil {
+				tt.view.NoteService = svc
+			}
+			msg := testRunner.Search(SearchParams{NoteService: svc, Query: "x", View: tt.view})()
+			got, ok := msg.(messages.SearchResultsMsg)
+			if !ok {
+				t.Fatalf("got %T, want SearchResultsMsg", msg)
+			}
+			if ids := resultIDs(got.Notes); !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("got notes %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestReloadNotes_Tag(t *testing.T) {
+	svc := &fakeNotes{all: []*models.Note{
+		{ID: 1, Tags: "work,urgent"},
+		{ID: 2, Tags: "home"},
+		{ID: 3, Tags: "Work"},
+		{ID: 4},
+	}}
+	tests := []struct {
+		tag  string
+		want []int64
+	}{
+		{"", []int64{1, 2, 3, 4}},
+		{"work", []int64{1, 3}},
+		{"#URGENT", []int64{1}},
+		{"missing", []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			msg := testRunner.ReloadNotes(ReloadNotesParams{NoteService: svc, CurrentFilter: constants.FilterAll, Tag: tt.tag})()
+			got, ok := msg.(messages.DataLoadedMsg)
+			if !ok {
+				t.Fatalf("got %T, want DataLoadedMsg", msg)
+			}
+			if ids := resultIDs(got.Notes); !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("got notes %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+// fakeFolders answers the folder calls loading makes; any other call
+// panics on the nil embedded interface
+type fakeFolders struct {
+	FolderService
+	tree    []*models.Folder
+	starred []*models.Folder
+}
+
+func (f *fakeFolders) GetTree(ctx context.Context) ([]*models.Folder, error) {
+	return f.tree, nil
+}
+
+func (f *fakeFolders) GetStarred(ctx context.Context) ([]*models.Folder, error) {
+	return f.starred, nil
+}
+
+type fakeTemplates struct{}
+
+func (fakeTemplates) List(ctx context.Context) ([]models.Template, error) {
+	return nil, nil
+}
+
+func TestLoadData_MatchesReload(t *testing.T) {
+	notes := &fakeNotes{
+		all:     notesWithIDs(1, 2, 3),
+		starred: notesWithIDs(2),
+	}
+	folders := &fakeFolders{
+		tree:    []*models.Folder{{ID: 1}, {ID: 2}},
+		starred: []*models.Folder{{ID: 2}},
+	}
+
+	tests := []struct {
+		filter      string
+		wantNotes   []int64
+		wantFolders int
+	}{
+		{constants.FilterStarred, []int64{2}, 1},
+		{constants.FilterAll, []int64{1, 2, 3}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			view := ReloadNotesParams{NoteService: notes, FolderService: folders, CurrentFilter: tt.filter}
+			loaded, ok := testRunner.LoadData(LoadDataParams{
+				FolderService:   folders,
+				NoteService:     notes,
+				TemplateService: fakeTemplates{},
+				Notes:           view,
+			})().(messages.DataLoadedMsg)
+			if !ok {
+				t.Fatal("LoadData did not return DataLoadedMsg")
+			}
+			reloaded, ok := testRunner.ReloadNotes(view)().(messages.DataLoadedMsg)
+			if !ok {
+				t.Fatal("ReloadNotes did not return DataLoadedMsg")
+			}
+
+			if ids := resultIDs(loaded.Notes); !reflect.DeepEqual(ids, tt.wantNotes) {
+				t.Errorf("loaded notes %v, want %v", ids, tt.wantNotes)
+			}
+			if ids := resultIDs(reloaded.Notes); !reflect.DeepEqual(ids, tt.wantNotes) {
+				t.Errorf("reloaded notes %v, want %v", ids, tt.wantNotes)
+			}
+			if len(loaded.StarredFolders) != tt.wantFolders || len(reloaded.StarredFolders) != tt.wantFolders {
+				t.Errorf("starred folders loaded %d, reloaded %d, want %d",
+					len(loaded.StarredFolders), len(reloaded.StarredFolders), tt.wantFolders)
+			}
+		})
+	}
+}
+
+// blockingNotes holds every query until its context is done
+type blockingNotes struct {
+	NoteService
+}
+
+func (blockingNotes) GetAllNotes(ctx context.Context) ([]*models.Note, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestRunner_CancelsQueries(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		timeout time.Duration
+		wantErr error
+	}{
+		{"at the deadline", context.Background(), 20 * time.Millisecond, context.DeadlineExceeded},
+		{"when the app stops", canceled, 0, context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := NewRunner(tt.ctx, tt.timeout)
+			params := ReloadNotesParams{NoteService: blockingNotes{}, CurrentFilter: constants.FilterAll}
+
+			done := make(chan tea.Msg, 1)
+			go func() { done <- runner.ReloadNotes(params)() }()
+
+			select {
+			case msg := <-done:
+				got, ok := msg.(messages.ErrorMsg)
+				if !ok {
+					t.Fatalf("got %T, want ErrorMsg", msg)
+				}
+				if !errors.Is(got.Err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", got.Err, tt.wantErr)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("query was not canceled")
+			}
+		})
+	}
+}
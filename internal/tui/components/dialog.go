@@ -8,6 +8,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/tranducquang/kiroku/internal/tui/keys"
+	"github.com/tranducquang/kiroku/internal/tui/messages"
 	"github.com/tranducquang/kiroku/internal/tui/styles"
 )
 
@@ -20,28 +21,42 @@ const (
 	DialogSelect
 )
 
+// dialogChromeHeight is the number of rows used by the dialog border,
+// padding and title around a select list.
+const dialogChromeHeight = 8
+
 // Dialog represents a modal dialog component
 type Dialog struct {
 	dialogType DialogType
+	// resultType tags the DialogResultMsg sent when the dialog closes
+	resultType string
 	title      string
 	message    string
 	input      textinput.Model
 	options    []string
-	cursor     int
+	list       *ScrollList
 	visible    bool
-	confirmed  bool
 	width      int
 	height     int
+
+	// validate checks input before confirming; err is its last failure
+	validate func(string) error
+	err      string
 }
 
+// inputCharLimit is how much an input dialog accepts unless SetCharLimit
+// says otherwise
+const inputCharLimit = 500
+
 // NewDialog creates a new dialog component
 func NewDialog() *Dialog {
 	ti := textinput.New()
-	ti.CharLimit = 100
+	ti.CharLimit = inputCharLimit
 	ti.Width = 40
 
 	return &Dialog{
 		input: ti,
+		list:  NewScrollList(),
 	}
 }
 
@@ -51,9 +66,11 @@ func (d *Dialog) ShowConfirm(title, message string) {
 	d.title = title
 	d.message = message
 	d.options = []string{"Yes", "No"}
-	d.cursor = 1 // Default to "No"
+	d.list.SetTotal(len(d.options))
+	d.list.SetCursor(1) // Default to "No"
 	d.visible = true
-	d.confirmed = false
+	d.validate = nil
+	d.err = ""
 }
 
 // ShowInput shows an input dialog
@@ -63,9 +80,12 @@ func (d *Dialog) ShowInput(title, placeholder string) {
 	d.message = ""
 	d.input.Placeholder = placeholder
 	d.input.SetValue("")
+	d.input.EchoMode = textinput.EchoNormal
+	d.input.CharLimit = inputCharLimit
 	d.input.Focus()
 	d.visible = true
-	d.confirmed = false
+	d.validate = nil
+	d.err = ""
 }
 
 // ShowSelect shows a selection dialog
@@ -73,9 +93,49 @@ func (d *Dialog) ShowSelect(title string, options []string) {
 	d.dialogType = DialogSelect
 	d.title = title
 	d.options = options
-	d.cursor = 0
+	d.list.SetTotal(len(options))
+	d.list.SetHeight(d.selectHeight())
+	d.list.Home()
 	d.visible = true
-	d.confirmed = false
+	d.validate = nil
+	d.err = ""
+}
+
+// SetInputValue prefills the input of an input dialog
+func (d *Dialog) SetInputValue(value string) {
+	d.input.SetValue(value)
+	d.input.CursorEnd()
+}
+
+// SetMasked hides what is typed into an input dialog, e.g. for a passphrase
+func (d *Dialog) SetMasked(masked bool) {
+	d.input.EchoMode = textinput.EchoNormal
+	if masked {
+		d.input.EchoMode = textinput.EchoPassword
+	}
+}
+
+// SetType names the action the dialog was opened for. It is sent back as
+// the Type of the DialogResultMsg, so the caller can act on the answer.
+func (d *Dialog) SetType(resultType string) {
+	d.resultType = resultType
+}
+
+// SetCharLimit caps how many characters an input dialog accepts; 0
+// removes the cap
+func (d *Dialog) SetCharLimit(limit int) {
+	d.input.CharLimit = limit
+}
+
+// SetValidator sets a check that must pass before an input dialog can be
+// confirmed. Failures are shown inline and rechecked as the user types.
+func (d *Dialog) SetValidator(validate func(string) error) {
+	d.validate = validate
+}
+
+// SetSelectedIndex moves the selection to the option at index
+func (d *Dialog) SetSelectedIndex(index int) {
+	d.list.SetCursor(index)
 }
 
 // Hide hides the dialog
@@ -89,11 +149,6 @@ func (d *Dialog) IsVisible() bool {
 	return d.visible
 }
 
-// IsConfirmed returns whether the dialog was confirmed
-func (d *Dialog) IsConfirmed() bool {
-	return d.confirmed
-}
-
 // InputValue returns the input value
 func (d *Dialog) InputValue() string {
 	return d.input.Value()
@@ -101,13 +156,14 @@ func (d *Dialog) InputValue() string {
 
 // SelectedIndex returns the selected option index
 func (d *Dialog) SelectedIndex() int {
-	return d.cursor
+	return d.list.Cursor()
 }
 
 // SelectedOption returns the selected option string
 func (d *Dialog) SelectedOption() string {
-	if d.cursor >= 0 && d.cursor < len(d.options) {
-		return d.options[d.cursor]
+	cursor := d.list.Cursor()
+	if cursor >= 0 && cursor < len(d.options) {
+		return d.options[cursor]
 	}
 	return ""
 }
@@ -117,6 +173,17 @@ func (d *Dialog) SetSize(width, height int) {
 	d.width = width
 	d.height = height
 	d.input.Width = width - 10
+	if d.dialogType == DialogSelect {
+		d.list.SetHeight(d.selectHeight())
+	}
+}
+
+// selectHeight returns how many select options fit on screen
+func (d *Dialog) selectHeight() int {
+	if d.height <= dialogChromeHeight {
+		return len(d.options)
+	}
+	return min(len(d.options), d.height-dialogChromeHeight)
 }
 
 // Update handles input
@@ -129,37 +196,27 @@ func (d *Dialog) Update(msg tea.Msg) (*Dialog, tea.Cmd) {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, keys.DefaultKeyMap.Escape):
-			d.Hide()
-			return d, nil
+			return d, d.close(false)
 
 		case key.Matches(msg, keys.DefaultKeyMap.Enter):
-			if d.dialogType == DialogConfirm {
-				d.confirmed = d.cursor == 0 // "Yes" is at index 0
-			} else {
-				d.confirmed = true
+			if d.dialogType == DialogInput && !d.valid() {
+				return d, nil
 			}
-			d.Hide()
-			return d, nil
+			// "Yes" is at index 0 of a confirm dialog
+			return d, d.close(d.dialogType != DialogConfirm || d.list.Cursor() == 0)
 
 		case key.Matches(msg, keys.DefaultKeyMap.Left):
-			if d.dialogType == DialogConfirm && d.cursor > 0 {
-				d.cursor--
+			if d.dialogType == DialogConfirm {
+				d.list.Up()
 			}
 
 		case key.Matches(msg, keys.DefaultKeyMap.Right):
-			if d.dialogType == DialogConfirm && d.cursor < len(d.options)-1 {
-				d.cursor++
-			}
-
-		case key.Matches(msg, keys.DefaultKeyMap.Up):
-			if d.dialogType == DialogSelect && d.cursor > 0 {
-				d.cursor--
+			if d.dialogType == DialogConfirm {
+				d.list.Down()
 			}
 
-		case key.Matches(msg, keys.DefaultKeyMap.Down):
-			if d.dialogType == DialogSelect && d.cursor < len(d.options)-1 {
-				d.cursor++
-			}
+		case d.dialogType == DialogSelect:
+			d.list.HandleKey(msg)
 		}
 	}
 
@@ -167,12 +224,41 @@ func (d *Dialog) Update(msg tea.Msg) (*Dialog, tea.Cmd) {
 	if d.dialogType == DialogInput {
 		var cmd tea.Cmd
 		d.input, cmd = d.input.Update(msg)
+		if d.err != "" {
+			d.valid()
+		}
 		return d, cmd
 	}
 
 	return d, nil
 }
 
+// close hides the dialog and reports how it was answered
+func (d *Dialog) close(confirmed bool) tea.Cmd {
+	d.Hide()
+	result := messages.DialogResultMsg{Type: d.resultType, Confirmed: confirmed}
+	switch d.dialogType {
+	case DialogInput:
+		result.Value = d.input.Value()
+	case DialogSelect:
+		result.Value = d.SelectedOption()
+	}
+	return func() tea.Msg { return result }
+}
+
+// valid runs the validator against the input and records any error
+func (d *Dialog) valid() bool {
+	d.err = ""
+	if d.validate == nil {
+		return true
+	}
+	if err := d.validate(d.input.Value()); err != nil {
+		d.err = err.Error()
+		return false
+	}
+	return true
+}
+
 // View renders the dialog
 func (d *Dialog) View() string {
 	if !d.visible {
@@ -192,7 +278,7 @@ func (d *Dialog) View() string {
 		b.WriteString("\n\n")
 		// Buttons
 		for i, opt := range d.options {
-			if i == d.cursor {
+			if i == d.list.Cursor() {
 				b.WriteString(styles.ButtonFocusedStyle.Render(opt))
 			} else {
 				b.WriteString(styles.ButtonStyle.Render(opt))
@@ -201,17 +287,24 @@ func (d *Dialog) View() string {
 
 	case DialogInput:
 		b.WriteString(d.input.View())
-		b.WriteString("\n\n")
+		b.WriteString("\n")
+		if d.err != "" {
+			b.WriteString(styles.ErrorStyle.Render(d.err))
+		}
+		b.WriteString("\n")
 		b.WriteString(styles.TextMuted.Render("Press Enter to confirm, Esc to cancel"))
 
 	case DialogSelect:
-		for i, opt := range d.options {
-			if i == d.cursor {
-				b.WriteString(styles.NoteItemSelectedStyle.Render("▸ " + opt))
+		cursor := d.list.Cursor()
+		start, end := d.list.Window()
+		for i := start; i < end; i++ {
+			opt := d.options[i]
+			if i == cursor {
+				b.WriteString(styles.NoteItemSelectedStyle.Render(styles.Icons.Collapse + " " + opt))
 			} else {
 				b.WriteString(styles.NoteItemStyle.Render("  " + opt))
 			}
-			if i < len(d.options)-1 {
+			if i < end-1 {
 				b.WriteString("\n")
 			}
 		}
@@ -0,0 +1,289 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/tranducquang/kiroku/internal/tui/styles"
+)
+
+// maxDiffCells caps the LCS table size. Larger changed regions are shown
+// as a single replacement instead of a minimal diff.
+const maxDiffCells = 4_000_000
+
+// diffOp is the kind of change a diff line represents
+type diffOp int
+
+const (
+	diffEqual diffOp = iota
+	diffDelete
+	diffInsert
+)
+
+// diffLine is one line of a line-level diff
+type diffLine struct {
+	op   diffOp
+	text string
+}
+
+// diffRow pairs an old line with a new line for side-by-side display.
+// hasLeft or hasRight is false when that side of the row is blank.
+type diffRow struct {
+	left, right       string
+	leftOp, rightOp   diffOp
+	hasLeft, hasRight bool
+}
+
+// DiffView shows old and new text side by side for confirming an edit
+type DiffView struct {
+	visible bool
+	width   int
+	height  int
+	title   string
+	rows    []diffRow
+	added   int
+	removed int
+	scroll  int
+}
+
+// NewDiffView creates a new diff view
+func NewDiffView() *DiffView {
+	return &DiffView{}
+}
+
+// Show computes the diff between oldText and newText and shows the view
+func (d *DiffView) Show(title, oldText, newText string) {
+	lines := lineDiff(strings.Split(oldText, "\n"), strings.Split(newText, "\n"))
+	d.title = title
+	d.rows = diffRows(lines)
+	d.added, d.removed = 0, 0
+	for _, l := range lines {
+		switch l.op {
+		case diffInsert:
+			d.added++
+		case diffDelete:
+			d.removed++
+		}
+	}
+	d.scroll = d.firstChange()
+	d.visible = true
+}
+
+// Hide hides the diff view and drops the diff
+func (d *DiffView) Hide() {
+	d.visible = false
+	d.rows = nil
+}
+
+// IsVisible returns whether the diff view is visible
+func (d *DiffView) IsVisible() bool {
+	return d.visible
+}
+
+// SetSize sets the diff view dimensions
+func (d *DiffView) SetSize(width, height int) {
+	d.width = width
+	d.height = height
+}
+
+// ScrollUp scrolls up by n rows
+func (d *DiffView) ScrollUp(n int) {
+	d.scroll = max(d.scroll-n, 0)
+}
+
+// ScrollDown scrolls down by n rows
+func (d *DiffView) ScrollDown(n int) {
+	d.scroll = min(d.scroll+n, max(len(d.rows)-d.visibleRows(), 0))
+}
+
+// PageSize returns how many rows one page scroll moves
+func (d *DiffView) PageSize() int {
+	return d.visibleRows()
+}
+
+// View renders the diff view
+func (d *DiffView) View() string {
+	if !d.visible {
+		return ""
+	}
+
+	boxWidth := max(d.width-4, 40)
+	inner := boxWidth - 6
+	colWidth := (inner - 3) / 2
+
+	var b strings.Builder
+	b.WriteString(styles.DialogTitleStyle.Render("Review changes: " + d.title))
+	b.WriteString("  ")
+	b.WriteString(styles.DiffInsertStyle.Render(fmt.Sprintf("+%d", d.added)))
+	b.WriteString(" ")
+	b.WriteString(styles.DiffDeleteStyle.Render(fmt.Sprintf("−%d", d.removed)))
+	b.WriteString("\n\n")
+
+	header := lipgloss.NewStyle().Width(colWidth).Render(styles.TextMuted.Render("Before")) +
+		" │ " + styles.TextMuted.Render("After")
+	b.WriteString(header)
+	b.WriteString("\n")
+
+	start := min(d.scroll, max(len(d.rows)-d.visibleRows(), 0))
+	end := min(start+d.visibleRows(), len(d.rows))
+	for _, row := range d.rows[start:end] {
+		b.WriteString(diffCell(row.left, row.leftOp, row.hasLeft, colWidth))
+		b.WriteString(" │ ")
+		b.WriteString(diffCell(row.right, row.rightOp, row.hasRight, colWidth))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.TextMuted.Render("Enter save • Esc discard • ↑/↓ scroll"))
+
+	return styles.HelpStyle.Width(boxWidth).Render(b.String())
+}
+
+// visibleRows is the number of diff rows that fit below the title and
+// column header and above the footer
+func (d *DiffView) visibleRows() int {
+	return max(d.height-12, 3)
+}
+
+// firstChange returns a scroll offset that shows the first changed row
+// with a little context above it
+func (d *DiffView) firstChange() int {
+	for i, row := range d.rows {
+		if row.leftOp != diffEqual || row.rightOp != diffEqual {
+			return max(i-2, 0)
+		}
+	}
+	return 0
+}
+
+// diffCell renders one side of a row clipped and padded to width
+func diffCell(text string, op diffOp, present bool, width int) string {
+	if !present {
+		return strings.Repeat(" ", width)
+	}
+
+	prefix, style := "  ", styles.DiffContextStyle
+	switch op {
+	case diffDelete:
+		prefix, style = "- ", styles.DiffDeleteStyle
+	case diffInsert:
+		prefix, style = "+ ", styles.DiffInsertStyle
+	}
+	text = ansi.Truncate(prefix+strings.ReplaceAll(text, "\t", "    "), width, "…")
+	return style.Width(width).Render(text)
+}
+
+// diffRows lays a line diff out side by side. Runs of deletions and
+// insertions between unchanged lines share rows so replaced lines line up.
+func diffRows(lines []diffLine) []diffRow {
+	var rows []diffRow
+	var dels, ins []string
+
+	flush := func() {
+		for i := 0; i < max(len(dels), len(ins)); i++ {
+			var row diffRow
+			if i < len(dels) {
+				row.left, row.leftOp, row.hasLeft = dels[i], diffDelete, true
+			}
+			if i < len(ins) {
+				row.right, row.rightOp, row.hasRight = ins[i], diffInsert, true
+			}
+			rows = append(rows, row)
+		}
+		dels, ins = dels[:0], ins[:0]
+	}
+
+	for _, l := range lines {
+		switch l.op {
+		case diffDelete:
+			dels = append(dels, l.text)
+		case diffInsert:
+			ins = append(ins, l.text)
+		default:
+			flush()
+			rows = append(rows, diffRow{left: l.text, right: l.text, hasLeft: true, hasRight: true})
+		}
+	}
+	flush()
+	return rows
+}
+
+// lineDiff returns a line-level diff of a and b using the longest common
+// subsequence of the region between their common prefix and suffix.
+func lineDiff(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var out []diffLine
+	for _, line := range a[:prefix] {
+		out = append(out, diffLine{diffEqual, line})
+	}
+	out = append(out, lcsDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		out = append(out, diffLine{diffEqual, line})
+	}
+	return out
+}
+
+// lcsDiff diffs a and b with a dynamic programming LCS table, falling back
+// to delete-all/insert-all when the table would be too large.
+func lcsDiff(a, b []string) []diffLine {
+	var out []diffLine
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			out = append(out, diffLine{diffDelete, line})
+		}
+		for _, line := range b {
+			out = append(out, diffLine{diffInsert, line})
+		}
+		return out
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, diffLine{diffEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, diffLine{diffDelete, a[i]})
+			i++
+		default:
+			out = append(out, diffLine{diffInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, diffLine{diffDelete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		out = append(out, diffLine{diffInsert, b[j]})
+	}
+	return out
+}
@@ -0,0 +1,373 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/tui/styles"
+)
+
+// folderPickerRows is the most rows the picker lists at once
+const folderPickerRows = 12
+
+// pickerRow is one visible row of the picker
+type pickerRow struct {
+	folder *models.Folder
+	path   string
+	depth  int
+}
+
+// FolderPicker is an overlay for choosing a folder. With an empty query it
+// shows the folder tree, which left and right collapse and expand; typing
+// fuzzy-matches folder paths instead.
+type FolderPicker struct {
+	visible  bool
+	width    int
+	height   int
+	title    string
+	input    textinput.Model
+	tree     []*models.Folder
+	paths    map[int64]string
+	expanded map[int64]bool
+	rows     []pickerRow
+	list     *ScrollList
+}
+
+// NewFolderPicker creates a new folder picker
+func NewFolderPicker() *FolderPicker {
+	ti := textinput.New()
+	ti.Placeholder = "Type to filter..."
+	ti.CharLimit = 100
+	ti.Width = 40
+
+	return &FolderPicker{
+		input: ti,
+		list:  NewScrollList(),
+	}
+}
+
+// Show opens the picker over the folder tree. When selectedID is set, that
+// folder starts highlighted with its ancestors expanded.
+func (p *FolderPicker) Show(title string, tree []*models.Folder, selectedID *int64) {
+	p.title = title
+	p.tree = tree
+	p.paths = make(map[int64]string)
+	p.expanded = make(map[int64]bool)
+	p.indexFolders(tree, "")
+	if selectedID != nil {
+		p.expandTo(tree, *selectedID)
+	}
+
+	p.input.SetValue("")
+	p.input.Focus()
+	p.refresh()
+	p.visible = true
+	if selectedID != nil {
+		p.selectID(*selectedID)
+	}
+}
+
+// indexFolders records each folder's path and starts it expanded if it
+// is expanded in the sidebar
+func (p *FolderPicker) indexFolders(folders []*models.Folder, parent string) {
+	for _, folder := range folders {
+		path := folder.Name
+		if parent != "" {
+			path = parent + models.FolderPathSeparator + folder.Name
+		}
+		p.paths[folder.ID] = path
+		p.expanded[folder.ID] = folder.Expanded
+		p.indexFolders(folder.Children, path)
+	}
+}
+
+// expandTo expands the ancestors of id and reports whether id was found
+func (p *FolderPicker) expandTo(folders []*models.Folder, id int64) bool {
+	for _, folder := range folders {
+		if folder.ID == id {
+			return true
+		}
+		if p.expandTo(folder.Children, id) {
+			p.expanded[folder.ID] = true
+			return true
+		}
+	}
+	return false
+}
+
+// selectID moves the cursor to the row for folder id, if it is visible
+func (p *FolderPicker) selectID(id int64) {
+	for i, row := range p.rows {
+		if row.folder.ID == id {
+			p.list.SetCursor(i)
+			return
+		}
+	}
+}
+
+// Hide hides the picker
+func (p *FolderPicker) Hide() {
+	p.visible = false
+	p.input.Blur()
+}
+
+// IsVisible returns whether the picker is visible
+func (p *FolderPicker) IsVisible() bool {
+	return p.visible
+}
+
+// SetSize sets the picker dimensions
+func (p *FolderPicker) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+	p.list.SetHeight(p.visibleRows())
+}
+
+// Selected returns the highlighted folder, or nil when nothing matches
+func (p *FolderPicker) Selected() *models.Folder {
+	cursor := p.list.Cursor()
+	if cursor < 0 || cursor >= len(p.rows) {
+		return nil
+	}
+	return p.rows[cursor].folder
+}
+
+// filtering reports whether the input holds a query
+func (p *FolderPicker) filtering() bool {
+	return strings.TrimSpace(p.input.Value()) != ""
+}
+
+// Update moves the selection with the arrow keys, expands and collapses
+// the tree with left and right, and passes everything else to the input.
+// Enter and Esc are left to the caller.
+func (p *FolderPicker) Update(msg tea.Msg) (*FolderPicker, tea.Cmd) {
+	if !p.visible {
+		return p, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.Type {
+		case tea.KeyUp, tea.KeyCtrlP:
+			p.list.Up()
+			return p, nil
+		case tea.KeyDown, tea.KeyCtrlN:
+			p.list.Down()
+			return p, nil
+		case tea.KeyPgUp:
+			p.list.PageUp()
+			return p, nil
+		case tea.KeyPgDown:
+			p.list.PageDown()
+			return p, nil
+		case tea.KeyLeft, tea.KeyRight:
+			if !p.filtering() {
+				p.toggle(msg.Type == tea.KeyRight)
+				return p, nil
+			}
+		}
+	}
+
+	before := p.input.Value()
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	if p.input.Value() != before {
+		p.refresh()
+		p.list.Home()
+	}
+	return p, cmd
+}
+
+// toggle expands or collapses the highlighted folder. Collapsing a folder
+// that is already collapsed moves up to its parent.
+func (p *FolderPicker) toggle(expand bool) {
+	folder := p.Selected()
+	if folder == nil {
+		return
+	}
+	switch {
+	case expand && len(folder.Children) > 0:
+		p.expanded[folder.ID] = true
+	case !expand && p.expanded[folder.ID] && len(folder.Children) > 0:
+		p.expanded[folder.ID] = false
+	case !expand && folder.ParentID != nil:
+		p.refresh()
+		p.selectID(*folder.ParentID)
+		return
+	}
+	p.refresh()
+	p.selectID(folder.ID)
+}
+
+// refresh rebuilds the visible rows: the expanded tree, or the folders
+// matching the query ranked best first
+func (p *FolderPicker) refresh() {
+	p.rows = p.rows[:0]
+	if p.filtering() {
+		p.addMatches(strings.TrimSpace(p.input.Value()))
+	} else {
+		p.addTree(p.tree, 0)
+	}
+	p.list.SetTotal(len(p.rows))
+	p.list.SetHeight(p.visibleRows())
+}
+
+// addTree adds the folders under expanded parents
+func (p *FolderPicker) addTree(folders []*models.Folder, depth int) {
+	for _, folder := range folders {
+		p.rows = append(p.rows, pickerRow{folder: folder, path: p.paths[folder.ID], depth: depth})
+		if p.expanded[folder.ID] {
+			p.addTree(folder.Children, depth+1)
+		}
+	}
+}
+
+// addMatches adds every folder whose path fuzzy-matches query
+func (p *FolderPicker) addMatches(query string) {
+	scores := make(map[int64]int)
+	var walk func(folders []*models.Folder)
+	walk = func(folders []*models.Folder) {
+		for _, folder := range folders {
+			path := p.paths[folder.ID]
+			if score, ok := fuzzyScore(query, path); ok {
+				scores[folder.ID] = score
+				p.rows = append(p.rows, pickerRow{folder: folder, path: path})
+			}
+			walk(folder.Children)
+		}
+	}
+	walk(p.tree)
+	sort.SliceStable(p.rows, func(a, b int) bool {
+		return scores[p.rows[a].folder.ID] > scores[p.rows[b].folder.ID]
+	})
+}
+
+// visibleRows returns how many rows fit on screen
+func (p *FolderPicker) visibleRows() int {
+	rows := folderPickerRows
+	if p.height > 0 {
+		rows = min(rows, p.height-dialogChromeHeight-2)
+	}
+	return max(rows, 1)
+}
+
+// View renders the picker
+func (p *FolderPicker) View() string {
+	if !p.visible {
+		return ""
+	}
+
+	dialogWidth := 50
+	if p.width > 0 && p.width < dialogWidth {
+		dialogWidth = p.width - 4
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.DialogTitleStyle.Render(p.title))
+	b.WriteString("\n\n")
+	b.WriteString(p.input.View())
+	b.WriteString("\n\n")
+
+	if len(p.rows) == 0 {
+		b.WriteString(styles.TextMuted.Render("No matching folders"))
+		b.WriteString("\n")
+	}
+	cursor := p.list.Cursor()
+	start, end := p.list.Window()
+	for i := start; i < end; i++ {
+		text := ansi.Truncate(p.renderRow(p.rows[i]), dialogWidth-8, "…")
+		if i == cursor {
+			// The tree already uses arrows, so the highlight alone marks the row
+			b.WriteString(styles.NoteItemSelectedStyle.Render("  " + text))
+		} else {
+			b.WriteString(styles.NoteItemStyle.Render("  " + text))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	help := "Enter choose • Esc cancel • ←/→ fold"
+	if p.filtering() {
+		help = "Enter choose • Esc cancel • ↑/↓ select"
+	}
+	b.WriteString(styles.TextMuted.Render(help))
+
+	return styles.DialogStyle.Width(dialogWidth).Render(b.String())
+}
+
+// renderRow renders a folder as a tree row or, while filtering, by path
+func (p *FolderPicker) renderRow(row pickerRow) string {
+	folder := row.folder
+	name := folder.Name
+	indent := ""
+	if p.filtering() {
+		name = row.path
+	} else {
+		indent = strings.Repeat("  ", row.depth)
+		switch {
+		case len(folder.Children) == 0:
+			indent += "  "
+		case p.expanded[folder.ID]:
+			indent += styles.Icons.Expanded + " "
+		default:
+			indent += styles.Icons.Collapse + " "
+		}
+	}
+
+	count := ""
+	if folder.NoteCount > 0 {
+		count = styles.FolderCountStyle.Render(fmt.Sprintf(" (%d)", folder.NoteCount))
+	}
+	return indent + styles.FolderIcon(folder.Icon) + " " + name + count
+}
+
+// fuzzyScore matches query against text as a case-insensitive
+// subsequence. Matches score higher when the characters are consecutive,
+// start a word, or fall in the last path segment (the folder's own name).
+func fuzzyScore(query, text string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+
+	q := []rune(strings.ToLower(query))
+	t := []rune(text)
+	nameStart := strings.LastIndex(text, models.FolderPathSeparator)
+	if nameStart >= 0 {
+		nameStart = len([]rune(text[:nameStart+len(models.FolderPathSeparator)]))
+	} else {
+		nameStart = 0
+	}
+
+	score, qi, last := 0, 0, -1
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if unicode.ToLower(t[ti]) != q[qi] {
+			continue
+		}
+		score++
+		if last >= 0 && ti == last+1 {
+			score += 5
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 3
+		}
+		if ti >= nameStart {
+			score += 2
+		}
+		if last >= 0 {
+			score -= min(ti-last-1, 3)
+		}
+		last = ti
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	// Prefer shorter paths among equal matches
+	return score*100 - len(t), true
+}
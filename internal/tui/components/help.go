@@ -74,7 +74,7 @@ func (h *Help) View() string {
 
 	var b strings.Builder
 
-	b.WriteString(styles.HelpTitleStyle.Render("⌨️  Keyboard Shortcuts"))
+	b.WriteString(styles.HelpTitleStyle.Render(styles.Icons.Help + " Keyboard Shortcuts"))
 	b.WriteString("\n\n")
 
 	sections := []struct {
@@ -86,9 +86,14 @@ func (h *Help) View() string {
 			keys: []struct{ key, desc string }{
 				{"↑/k", "Move up"},
 				{"↓/j", "Move down"},
+				{"PgUp/PgDn", "Page up/down"},
+				{"Home/End", "First/last item"},
 				{"←/h", "Collapse/Left"},
 				{"→/l", "Expand/Right"},
 				{"Tab", "Switch panel"},
+				{"g", "Go to folder"},
+				{"Alt+←/→", "Previous/next folder"},
+				{"Ctrl+←/→", "Resize sidebar"},
 				{"Enter", "Select/Confirm"},
 				{"Esc", "Back/Cancel"},
 			},
@@ -99,13 +104,29 @@ func (h *Help) View() string {
 				{"n", "New note"},
 				{"t", "New todo"},
 				{"f", "New folder"},
+				{"c", "Quick capture"},
+				{"S", "Scratch note (again to edit)"},
 				{"e", "Edit note"},
+				{"o", "Open in default app"},
 				{"d", "Delete"},
 				{"/", "Search"},
 				{"s", "Toggle star"},
 				{"x/Space", "Toggle done"},
+				{"X", "Complete all shown todos"},
 				{"p", "Cycle priority"},
+				{"P", "Pick priority"},
+				{"D", "Set due date"},
 				{"m", "Move to folder"},
+				{"M", "Repeat last move"},
+				{"Shift+↑/↓", "Move todo up/down (manual order)"},
+				{"y", "Copy content"},
+				{"Y", "Copy title"},
+				{"L", "Toggle lock"},
+				{"U", "Unlock encrypted note for this session"},
+				{"C", "Cycle label color"},
+				{"H", "Hide done todos in folders"},
+				{"Ctrl+Z", "Undo"},
+				{"Ctrl+Y", "Redo"},
 			},
 		},
 		{
@@ -113,6 +134,7 @@ func (h *Help) View() string {
 			keys: []struct{ key, desc string }{
 				{"?", "Toggle help"},
 				{"v", "Toggle preview"},
+				{"R", "Raw/rendered preview"},
 				{"r", "Refresh"},
 				{"q", "Quit"},
 			},
@@ -3,9 +3,12 @@ package components
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/tranducquang/kiroku/internal/models"
 	"github.com/tranducquang/kiroku/internal/tui/keys"
@@ -16,12 +19,42 @@ import (
 type NoteList struct {
 	notes      []*models.Note
 	folders    []*models.Folder
-	cursor     int
+	rows       []noteListRow
+	list       *ScrollList
 	height     int
 	width      int
 	focused    bool
 	showTodos  bool
 	folderName string
+
+	// showSnippets adds a dimmed content line under each note
+	showSnippets bool
+
+	// loadErr replaces the rows when the last load failed
+	loadErr error
+
+	// groupCompleted moves done todos under a collapsible section
+	groupCompleted    bool
+	completedExpanded bool
+
+	// sectionOf labels each note; a header row starts each new label
+	sectionOf func(*models.Note) string
+}
+
+type noteListRowKind int
+
+const (
+	rowFolder noteListRowKind = iota
+	rowNote
+	rowCompletedHeader
+	rowSectionHeader
+)
+
+type noteListRow struct {
+	kind    noteListRowKind
+	folder  *models.Folder
+	note    *models.Note
+	section string
 }
 
 // NewNoteList creates a new note list component
@@ -29,35 +62,137 @@ func NewNoteList() *NoteList {
 	return &NoteList{
 		notes:   make([]*models.Note, 0),
 		folders: make([]*models.Folder, 0),
+		list:    NewScrollList(),
 	}
 }
 
-// SetNotes sets the notes to display
+// SetNotes sets the notes to display and clears any load error
 func (n *NoteList) SetNotes(notes []*models.Note) {
+	n.loadErr = nil
 	n.notes = notes
-	n.correctCursor()
+	n.buildRows()
+	n.list.SetTotal(len(n.rows))
+}
+
+// SetError shows a load failure in place of the (now stale) rows until
+// the next successful SetNotes. A nil error clears it.
+func (n *NoteList) SetError(err error) {
+	n.loadErr = err
 }
 
 // SetFolders sets the folders to display
 func (n *NoteList) SetFolders(folders []*models.Folder) {
 	n.folders = folders
-	n.correctCursor()
+	n.buildRows()
+	n.list.SetTotal(len(n.rows))
+}
+
+// SetGroupCompleted sets whether done todos are grouped under a
+// collapsible "Completed" section at the bottom of the list.
+func (n *NoteList) SetGroupCompleted(group bool) {
+	if n.groupCompleted == group {
+		return
+	}
+	n.groupCompleted = group
+	n.completedExpanded = false
+	n.buildRows()
+	n.list.SetTotal(len(n.rows))
 }
 
-func (n *NoteList) correctCursor() {
-	total := len(n.notes) + len(n.folders)
-	if n.cursor >= total {
-		n.cursor = total - 1
+// SetSectionFunc groups consecutive notes under a header row for each
+// label returned by sectionOf. A nil func disables section headers.
+func (n *NoteList) SetSectionFunc(sectionOf func(*models.Note) string) {
+	n.sectionOf = sectionOf
+	n.buildRows()
+	n.list.SetTotal(len(n.rows))
+}
+
+// ToggleCompleted expands or collapses the "Completed" section.
+func (n *NoteList) ToggleCompleted() {
+	n.completedExpanded = !n.completedExpanded
+	n.buildRows()
+	n.list.SetTotal(len(n.rows))
+}
+
+// buildRows flattens folders and notes into the rows shown in the list.
+func (n *NoteList) buildRows() {
+	n.rows = make([]noteListRow, 0, len(n.folders)+len(n.notes)+1)
+	for _, folder := range n.folders {
+		n.rows = append(n.rows, noteListRow{kind: rowFolder, folder: folder})
+	}
+
+	if n.sectionOf != nil {
+		section := ""
+		for i, note := range n.notes {
+			if label := n.sectionOf(note); i == 0 || label != section {
+				section = label
+				n.rows = append(n.rows, noteListRow{kind: rowSectionHeader, section: label})
+			}
+			n.rows = append(n.rows, noteListRow{kind: rowNote, note: note})
+		}
+		return
+	}
+
+	if !n.groupCompleted {
+		for _, note := range n.notes {
+			n.rows = append(n.rows, noteListRow{kind: rowNote, note: note})
+		}
+		return
+	}
+
+	var completed []*models.Note
+	for _, note := range n.notes {
+		if note.IsTodo && note.IsDone {
+			completed = append(completed, note)
+			continue
+		}
+		n.rows = append(n.rows, noteListRow{kind: rowNote, note: note})
 	}
-	if n.cursor < 0 {
-		n.cursor = 0
+	if len(completed) == 0 {
+		return
 	}
+
+	n.rows = append(n.rows, noteListRow{kind: rowCompletedHeader})
+	if !n.completedExpanded {
+		return
+	}
+	for _, note := range completed {
+		n.rows = append(n.rows, noteListRow{kind: rowNote, note: note})
+	}
+}
+
+// completedCount returns the number of done todos in the list.
+func (n *NoteList) completedCount() int {
+	count := 0
+	for _, note := range n.notes {
+		if note.IsTodo && note.IsDone {
+			count++
+		}
+	}
+	return count
 }
 
 // SetSize sets the note list dimensions
 func (n *NoteList) SetSize(width, height int) {
 	n.width = width
 	n.height = height
+	n.updateListHeight()
+}
+
+// SetShowSnippets sets whether each note shows a line of its content
+func (n *NoteList) SetShowSnippets(show bool) {
+	n.showSnippets = show
+	n.updateListHeight()
+}
+
+// updateListHeight sizes the scroll window in rows. With snippets every
+// note takes two lines, so the window counts rows as two lines each.
+func (n *NoteList) updateListHeight() {
+	lines := listVisibleHeight(n.height)
+	if n.showSnippets {
+		lines /= 2
+	}
+	n.list.SetHeight(lines)
 }
 
 // SetFocused sets the focus state
@@ -82,36 +217,98 @@ func (n *NoteList) IsFocused() bool {
 
 // Cursor returns the current cursor position
 func (n *NoteList) Cursor() int {
-	return n.cursor
+	return n.list.Cursor()
 }
 
 // SelectedFolder returns the currently selected folder
 func (n *NoteList) SelectedFolder() *models.Folder {
-	if n.cursor < 0 {
+	row := n.selectedRow()
+	if row == nil {
 		return nil
 	}
-	if n.cursor < len(n.folders) {
-		return n.folders[n.cursor]
-	}
-	return nil
+	return row.folder
 }
 
 // SelectedNote returns the currently selected note
 func (n *NoteList) SelectedNote() *models.Note {
-	if n.cursor < 0 {
+	row := n.selectedRow()
+	if row == nil {
 		return nil
 	}
-	// Notes start after folders
-	idx := n.cursor - len(n.folders)
-	if idx >= 0 && idx < len(n.notes) {
-		return n.notes[idx]
+	return row.note
+}
+
+// IsCompletedHeaderSelected returns whether the cursor is on the "Completed" header
+func (n *NoteList) IsCompletedHeaderSelected() bool {
+	row := n.selectedRow()
+	return row != nil && row.kind == rowCompletedHeader
+}
+
+func (n *NoteList) selectedRow() *noteListRow {
+	cursor := n.list.Cursor()
+	if cursor < 0 || cursor >= len(n.rows) {
+		return nil
+	}
+	return &n.rows[cursor]
+}
+
+// SelectNote moves the cursor to the note with the given ID and reports
+// whether it is in the list
+func (n *NoteList) SelectNote(id int64) bool {
+	for i, row := range n.rows {
+		if row.kind == rowNote && row.note.ID == id {
+			n.list.SetCursor(i)
+			return true
+		}
+	}
+	return false
+}
+
+// Neighbor returns the note to select once note id is gone: the next note
+// in the list, or the previous one when id is last. It is nil when id is
+// not listed or is the only note.
+func (n *NoteList) Neighbor(id int64) *models.Note {
+	var prev *models.Note
+	for i, row := range n.rows {
+		if row.kind != rowNote || row.note.ID != id {
+			if row.kind == rowNote {
+				prev = row.note
+			}
+			continue
+		}
+		for _, next := range n.rows[i+1:] {
+			if next.kind == rowNote {
+				return next.note
+			}
+		}
+		return prev
 	}
 	return nil
 }
 
+// Adjacent returns the note step rows of notes away from note id, skipping
+// folders and headers, or nil when there is none in that direction
+func (n *NoteList) Adjacent(id int64, step int) *models.Note {
+	var listed []*models.Note
+	at := -1
+	for _, row := range n.rows {
+		if row.kind != rowNote {
+			continue
+		}
+		if row.note.ID == id {
+			at = len(listed)
+		}
+		listed = append(listed, row.note)
+	}
+	if at < 0 || at+step < 0 || at+step >= len(listed) {
+		return nil
+	}
+	return listed[at+step]
+}
+
 // ResetCursor resets the cursor to the top
 func (n *NoteList) ResetCursor() {
-	n.cursor = 0
+	n.list.Home()
 }
 
 // Update handles input
@@ -122,16 +319,11 @@ func (n *NoteList) Update(msg tea.Msg) (*NoteList, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch {
-		case key.Matches(msg, keys.DefaultKeyMap.Up):
-			if n.cursor > 0 {
-				n.cursor--
-			}
-		case key.Matches(msg, keys.DefaultKeyMap.Down):
-			total := len(n.notes) + len(n.folders)
-			if n.cursor < total-1 {
-				n.cursor++
-			}
+		if n.list.HandleKey(msg) {
+			return n, nil
+		}
+		if key.Matches(msg, keys.DefaultKeyMap.Enter) && n.IsCompletedHeaderSelected() {
+			n.ToggleCompleted()
 		}
 	}
 
@@ -159,7 +351,7 @@ func (n *NoteList) View() string {
 	if title == "" {
 		title = "All Notes"
 	}
-	b.WriteString(styles.NoteListTitleStyle.Render(fmt.Sprintf("📝 %s (%d)", title, len(n.notes))))
+	b.WriteString(styles.PanelTitle(styles.NoteListTitleStyle, fmt.Sprintf("%s %s (%d)", styles.Icons.NoteList, title, len(n.notes)), n.focused))
 	b.WriteString("\n")
 
 	sepWidth := width - 6
@@ -169,41 +361,28 @@ func (n *NoteList) View() string {
 	b.WriteString(strings.Repeat("─", sepWidth))
 	b.WriteString("\n")
 
-	totalItems := len(n.folders) + len(n.notes)
+	totalItems := len(n.rows)
 
-	if totalItems == 0 {
+	switch {
+	case n.loadErr != nil:
+		msg := fmt.Sprintf("Failed to load notes: %v — press r to retry", n.loadErr)
+		b.WriteString(styles.ErrorStyle.Width(width - 6).Render(msg))
+	case totalItems == 0:
 		b.WriteString(styles.TextMuted.Render("No notes yet. Press 'n' to create one."))
-	} else {
-		// Calculate visible range (subtract 3 for title, separator, padding)
-		visibleHeight := contentHeight - 3
-		if visibleHeight < 1 {
-			visibleHeight = 1
-		}
-
-		startIdx := 0
-		if n.cursor >= visibleHeight {
-			startIdx = n.cursor - visibleHeight + 1
-		}
-		endIdx := startIdx + visibleHeight
-		if endIdx > totalItems {
-			endIdx = totalItems
-		}
-
-		// Render items
+	default:
+		cursor := n.list.Cursor()
+		startIdx, endIdx := n.list.Window()
 		for i := startIdx; i < endIdx; i++ {
-			if i < len(n.folders) {
-				// Render folder
-				folder := n.folders[i]
-				line := n.renderFolder(folder, i == n.cursor)
-				b.WriteString(line)
-			} else {
-				// Render note
-				noteIdx := i - len(n.folders)
-				if noteIdx < len(n.notes) {
-					note := n.notes[noteIdx]
-					line := n.renderNote(note, i == n.cursor)
-					b.WriteString(line)
-				}
+			row := n.rows[i]
+			switch row.kind {
+			case rowFolder:
+				b.WriteString(n.renderFolder(row.folder, i == cursor))
+			case rowNote:
+				b.WriteString(n.renderNote(row.note, i == cursor))
+			case rowCompletedHeader:
+				b.WriteString(n.renderCompletedHeader(i == cursor))
+			case rowSectionHeader:
+				b.WriteString(n.renderSectionHeader(row.section, i == cursor))
 			}
 
 			if i < endIdx-1 {
@@ -212,10 +391,7 @@ func (n *NoteList) View() string {
 		}
 	}
 
-	style := styles.NoteListStyle.Width(width - 4).Height(contentHeight)
-	if n.focused {
-		style = style.BorderForeground(styles.Primary)
-	}
+	style := styles.PanelBorder(styles.NoteListStyle.Width(width-4).Height(contentHeight), n.focused)
 	return style.Render(b.String())
 }
 
@@ -237,6 +413,14 @@ func (n *NoteList) renderNote(note *models.Note, selected bool) string {
 		parts = append(parts, styles.RenderStar(true))
 	}
 
+	// Lock
+	if note.Locked {
+		parts = append(parts, styles.RenderLock(true))
+	}
+	if note.Encrypted {
+		parts = append(parts, styles.Icons.Encrypted)
+	}
+
 	// Title - calculate available space for title
 	title := note.Title
 	maxTitleLen := n.width - 25 // Reserve space for icons and date
@@ -248,9 +432,13 @@ func (n *NoteList) renderNote(note *models.Note, selected bool) string {
 	}
 	parts = append(parts, title)
 
-	// Date
-	date := note.UpdatedAt.Format("Jan 02")
-	parts = append(parts, styles.NoteDateStyle.Render(date))
+	// Date: the due date for open todos, otherwise the last update
+	if note.IsTodo && !note.IsDone && note.DueDate != nil {
+		parts = append(parts, styles.RenderDueDate(note.DueDate.Format("Jan 02"), note.IsOverdue(time.Now())))
+	} else {
+		date := note.UpdatedAt.Format("Jan 02")
+		parts = append(parts, styles.NoteDateStyle.Render(date))
+	}
 
 	text := strings.Join(parts, " ")
 
@@ -260,21 +448,45 @@ func (n *NoteList) renderNote(note *models.Note, selected bool) string {
 		renderWidth = 20
 	}
 
-	if selected {
-		return styles.NoteItemSelectedStyle.Width(renderWidth).Render(text)
+	style := styles.NoteItemStyle
+	switch {
+	case selected:
+		style = styles.NoteItemSelectedStyle
+	case note.IsTodo && note.IsDone:
+		style = styles.TodoDoneStyle
 	}
+	// A label takes one column of the row as a gutter, so the total width
+	// matches unlabeled rows
+	gutter := styles.RenderLabel(note.Label)
+	line := gutter + style.Width(renderWidth-lipgloss.Width(gutter)).Render(text)
 
-	if note.IsTodo && note.IsDone {
-		return styles.TodoDoneStyle.Width(renderWidth).Render(text)
+	if !n.showSnippets {
+		return line
 	}
+	return line + "\n" + n.renderSnippet(note, selected, renderWidth)
+}
 
-	return styles.NoteItemStyle.Width(renderWidth).Render(text)
+// renderSnippet renders the dimmed content line shown under a note
+func (n *NoteList) renderSnippet(note *models.Note, selected bool, width int) string {
+	snippet := note.Snippet()
+	switch {
+	case note.Encrypted:
+		snippet = "Encrypted"
+	case snippet == "":
+		snippet = "No content"
+	}
+	snippet = ansi.Truncate("  "+snippet, width, "…")
+
+	if selected {
+		return styles.NoteItemSelectedStyle.Bold(false).Faint(true).Width(width).Render(snippet)
+	}
+	return styles.NoteSnippetStyle.Width(width).Render(snippet)
 }
 
 func (n *NoteList) renderFolder(folder *models.Folder, selected bool) string {
-	icon := "📁"
+	icon := styles.FolderIcon(folder.Icon)
 	if folder.Starred {
-		icon = "⭐"
+		icon = styles.Icons.Starred
 	}
 	text := fmt.Sprintf("%s %s", icon, folder.Name)
 
@@ -289,6 +501,36 @@ func (n *NoteList) renderFolder(folder *models.Folder, selected bool) string {
 	return styles.NoteItemStyle.Width(renderWidth).Render(text)
 }
 
+func (n *NoteList) renderCompletedHeader(selected bool) string {
+	indicator := styles.Icons.Collapse
+	if n.completedExpanded {
+		indicator = styles.Icons.Expanded
+	}
+	text := fmt.Sprintf("%s Completed (%d)", indicator, n.completedCount())
+
+	renderWidth := n.width - 4
+	if renderWidth < 20 {
+		renderWidth = 20
+	}
+
+	if selected {
+		return styles.NoteItemSelectedStyle.Width(renderWidth).Render(text)
+	}
+	return styles.TodoDoneStyle.Width(renderWidth).Render(text)
+}
+
+func (n *NoteList) renderSectionHeader(section string, selected bool) string {
+	renderWidth := n.width - 4
+	if renderWidth < 20 {
+		renderWidth = 20
+	}
+
+	if selected {
+		return styles.NoteItemSelectedStyle.Width(renderWidth).Render(section)
+	}
+	return styles.SidebarTitleStyle.Width(renderWidth).Render(section)
+}
+
 // Width returns the note list width
 func (n *NoteList) Width() int {
 	return n.width
@@ -0,0 +1,88 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+// newTestNoteList returns a focused list of notes titled "note 00" on,
+// sized to show seven rows
+func newTestNoteList(count int) *NoteList {
+	notes := make([]*models.Note, count)
+	for i := range notes {
+		notes[i] = &models.Note{ID: int64(i + 1), Title: fmt.Sprintf("note %02d", i)}
+	}
+	n := NewNoteList()
+	n.SetSize(60, 12)
+	n.SetFocused(true)
+	n.SetNotes(notes)
+	return n
+}
+
+func pressKey(n *NoteList, keyType tea.KeyType, times int) {
+	for i := 0; i < times; i++ {
+		n.Update(tea.KeyMsg{Type: keyType})
+		n.View()
+	}
+}
+
+func TestNoteList_WindowFollowsCursorUp(t *testing.T) {
+	tests := []struct {
+		name      string
+		move      func(n *NoteList)
+		wantShown []string
+		wantGone  []string
+	}{
+		{
+			name:      "reset after scrolling to the end",
+			move:      func(n *NoteList) { n.ResetCursor() },
+			wantShown: []string{"note 00"},
+			wantGone:  []string{"note 29"},
+		},
+		{
+			name:      "home after scrolling to the end",
+			move:      func(n *NoteList) { pressKey(n, tea.KeyHome, 1) },
+			wantShown: []string{"note 00"},
+			wantGone:  []string{"note 29"},
+		},
+		{
+			name:      "up past the window top",
+			move:      func(n *NoteList) { pressKey(n, tea.KeyUp, 8) },
+			wantShown: []string{"note 21"},
+			wantGone:  []string{"note 29"},
+		},
+		{
+			name:      "selecting a note above the window",
+			move:      func(n *NoteList) { n.SelectNote(6) },
+			wantShown: []string{"note 05"},
+			wantGone:  []string{"note 29"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newTestNoteList(30)
+			pressKey(n, tea.KeyEnd, 1)
+			if view := n.View(); !strings.Contains(view, "note 29") {
+				t.Fatalf("after End the last note is not shown:\n%s", view)
+			}
+
+			tt.move(n)
+			view := n.View()
+			for _, title := range tt.wantShown {
+				if !strings.Contains(view, title) {
+					t.Errorf("%q is not shown:\n%s", title, view)
+				}
+			}
+			for _, title := range tt.wantGone {
+				if strings.Contains(view, title) {
+					t.Errorf("%q is still shown:\n%s", title, view)
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,14 @@
 package components
 
 import (
+	"fmt"
 	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 
 	"github.com/tranducquang/kiroku/internal/models"
 	"github.com/tranducquang/kiroku/internal/tui/styles"
@@ -13,6 +20,41 @@ type Preview struct {
 	height int
 	width  int
 	scroll int
+	raw    bool
+
+	// largeLines is the line count above which content is shown as plain,
+	// unwrapped text and only the visible window is styled. Zero disables it.
+	largeLines int
+
+	// source caches the split of sourceContent into lines
+	source        []string
+	sourceContent string
+
+	// lines caches the display lines of linesSource for linesWidth and linesRaw
+	lines       []string
+	linesSource string
+	linesWidth  int
+	linesRaw    bool
+
+	// rendered caches the markdown rendering of renderedSource at renderedWidth
+	rendered       string
+	renderedSource string
+	renderedWidth  int
+
+	// progressDone and progressTotal cache the checklist counts of
+	// progressSource
+	progressDone   int
+	progressTotal  int
+	progressSource string
+
+	// highlight holds the lowercased search words to mark in the content;
+	// seekMatch scrolls the next view to the first line with a match
+	highlight [][]rune
+	seekMatch bool
+
+	// pending shows just the title while the selection is still moving,
+	// so notes passed over are never rendered
+	pending bool
 }
 
 // NewPreview creates a new preview component
@@ -24,6 +66,37 @@ func NewPreview() *Preview {
 func (p *Preview) SetNote(note *models.Note) {
 	p.note = note
 	p.scroll = 0
+	p.seekMatch = len(p.highlight) > 0
+	p.pending = false
+}
+
+// SetPending shows a lightweight placeholder for note until SetNote
+// renders it in full
+func (p *Preview) SetPending(note *models.Note) {
+	p.SetNote(note)
+	p.pending = note != nil
+}
+
+// SetHighlight marks the words of a search query wherever they appear in
+// the content. FTS syntax such as quotes, prefix stars, AND/OR/NOT and
+// column filters is ignored. An empty query clears the highlight.
+func (p *Preview) SetHighlight(query string) {
+	p.highlight = nil
+	for _, word := range strings.Fields(strings.NewReplacer(`"`, " ", "*", " ", "(", " ", ")", " ").Replace(query)) {
+		switch word {
+		case "AND", "OR", "NOT":
+			continue
+		}
+		// A column filter highlights just its term; tags are not in the content
+		if field, term, ok := strings.Cut(word, ":"); ok && field != "" && term != "" {
+			if strings.EqualFold(field, "tag") || strings.EqualFold(field, "tags") {
+				continue
+			}
+			word = term
+		}
+		p.highlight = append(p.highlight, []rune(strings.ToLower(word)))
+	}
+	p.seekMatch = len(p.highlight) > 0
 }
 
 // SetSize sets the preview dimensions
@@ -32,6 +105,38 @@ func (p *Preview) SetSize(width, height int) {
 	p.height = height
 }
 
+// SetRaw selects between raw markdown and rendered output
+func (p *Preview) SetRaw(raw bool) {
+	p.raw = raw
+}
+
+// SetLargeThreshold sets the line count above which a note is treated as
+// large. Zero renders every note in full.
+func (p *Preview) SetLargeThreshold(lines int) {
+	p.largeLines = max(lines, 0)
+}
+
+// Raw reports whether the preview shows raw markdown
+func (p *Preview) Raw() bool {
+	return p.raw
+}
+
+// ToggleRaw switches between raw and rendered output, keeping the scroll
+// position at roughly the same fraction of the content.
+func (p *Preview) ToggleRaw() {
+	if p.note == nil {
+		p.raw = !p.raw
+		return
+	}
+
+	before := len(p.contentLines(p.contentWidth()))
+	p.raw = !p.raw
+	after := len(p.contentLines(p.contentWidth()))
+	if before > 0 {
+		p.scroll = p.scroll * after / before
+	}
+}
+
 // ScrollUp scrolls the preview up
 func (p *Preview) ScrollUp() {
 	if p.scroll > 0 {
@@ -49,10 +154,7 @@ func (p *Preview) View() string {
 	var b strings.Builder
 
 	// Ensure minimum dimensions
-	width := p.width
-	if width < 30 {
-		width = 30
-	}
+	width := p.contentWidth() + 6
 
 	// Account for border (2) and no vertical padding (0)
 	contentHeight := p.height - 2
@@ -69,13 +171,18 @@ func (p *Preview) View() string {
 	b.WriteString(styles.PreviewTitleStyle.Render(p.note.Title))
 	b.WriteString("\n")
 
+	if p.pending {
+		b.WriteString(styles.TextMuted.Render("…"))
+		return styles.PreviewStyle.Width(width - 4).Height(contentHeight).Render(b.String())
+	}
+
 	// Meta info
 	meta := []string{}
 	if p.note.IsTodo {
 		if p.note.IsDone {
-			meta = append(meta, "✓ Done")
+			meta = append(meta, styles.Icons.Done+" Done")
 		} else {
-			meta = append(meta, "☐ Todo")
+			meta = append(meta, styles.Icons.Todo+" Todo")
 		}
 	}
 	if p.note.Priority > 0 {
@@ -83,13 +190,35 @@ func (p *Preview) View() string {
 		meta = append(meta, "Priority: "+priority)
 	}
 	if p.note.DueDate != nil {
-		meta = append(meta, "Due: "+p.note.DueDate.Format("Jan 02, 2006"))
+		due := "Due: " + p.note.DueDate.Format("Jan 02, 2006")
+		if p.note.IsOverdue(time.Now()) {
+			due = styles.OverdueStyle.Render(due + " (overdue)")
+		}
+		meta = append(meta, due)
 	}
 	meta = append(meta, "Updated: "+p.note.UpdatedAt.Format("Jan 02, 2006 15:04"))
+	if p.note.EditedExternally {
+		meta = append(meta, "Edited outside kiroku")
+	}
+	if p.note.Encrypted {
+		meta = append(meta, styles.Icons.Encrypted+" Encrypted")
+	}
+	large := p.isLarge()
+	if large {
+		meta = append(meta, "Large note: plain text")
+	}
 
 	b.WriteString(styles.PreviewMetaStyle.Render(strings.Join(meta, " • ")))
 	b.WriteString("\n")
 
+	// Checklist progress for todos, on its own line under the meta
+	header := 3
+	if progress := p.progressBar(); progress != "" {
+		b.WriteString(progress)
+		b.WriteString("\n")
+		header++
+	}
+
 	// Separator
 	sepWidth := width - 6
 	if sepWidth < 10 {
@@ -98,29 +227,256 @@ func (p *Preview) View() string {
 	b.WriteString(strings.Repeat("─", sepWidth))
 	b.WriteString("\n")
 
-	// Content
-	content := p.note.Content
-	lines := strings.Split(content, "\n")
+	// Content, wrapped to the panel width so overflow counts match what is shown
+	lines := p.contentLines(width - 6)
+	total := len(lines)
 
-	// Apply scroll
-	if p.scroll < len(lines) {
-		lines = lines[p.scroll:]
-	}
-
-	// Limit visible lines (account for title, meta, separator = 3 lines)
-	visibleLines := contentHeight - 3
+	// Limit visible lines (account for title, meta, progress and separator)
+	visibleLines := contentHeight - header
 	if visibleLines < 1 {
 		visibleLines = 1
 	}
-	if len(lines) > visibleLines {
-		lines = lines[:visibleLines]
+
+	// Reserve a footer line when the content overflows
+	truncated := total > visibleLines
+	if truncated && visibleLines > 1 {
+		visibleLines--
 	}
 
-	b.WriteString(styles.PreviewContentStyle.Render(strings.Join(lines, "\n")))
+	// Apply scroll, starting at the first match after a search
+	if p.seekMatch {
+		p.seekMatch = false
+		if line := p.firstMatch(lines); line > 0 {
+			p.scroll = line - 1
+		}
+	}
+	start := min(p.scroll, max(total-visibleLines, 0))
+	end := min(start+visibleLines, total)
+	p.scroll = start
+	lines = lines[start:end]
+
+	if large {
+		// Lines are unwrapped source lines here, so clip just the visible ones
+		clipped := make([]string, len(lines))
+		for i, line := range lines {
+			clipped[i] = p.highlightLine(ansi.Truncate(line, width-6, "…"))
+		}
+		b.WriteString(styles.PreviewContentStyle.Render(strings.Join(clipped, "\n")))
+	} else {
+		if len(p.highlight) > 0 {
+			marked := make([]string, len(lines))
+			for i, line := range lines {
+				marked[i] = p.highlightLine(line)
+			}
+			lines = marked
+		}
+		if p.raw {
+			b.WriteString(styles.PreviewContentStyle.Render(strings.Join(lines, "\n")))
+		} else {
+			b.WriteString(strings.Join(lines, "\n"))
+		}
+	}
+	if truncated {
+		b.WriteString("\n")
+		b.WriteString(styles.TextMuted.Render(previewFooter(start, end, total)))
+	}
 
 	return styles.PreviewStyle.Width(width - 4).Height(contentHeight).Render(b.String())
 }
 
+// progressBarSegments is the length of the checklist progress bar
+const progressBarSegments = 10
+
+// progressBar renders the checklist progress of a todo, e.g.
+// "▰▰▰▰▰▰▱▱▱▱ 3/5 (60%)", or "" when it has no checklist. The counts are
+// recomputed only when the content changes.
+func (p *Preview) progressBar() string {
+	if !p.note.IsTodo {
+		return ""
+	}
+	if p.progressSource != p.note.Content {
+		p.progressDone, p.progressTotal = p.note.ChecklistProgress()
+		p.progressSource = p.note.Content
+	}
+	if p.progressTotal == 0 {
+		return ""
+	}
+
+	filled := p.progressDone * progressBarSegments / p.progressTotal
+	return styles.SuccessStyle.Render(strings.Repeat(styles.Icons.ProgressDone, filled)) +
+		styles.TextMuted.Render(strings.Repeat(styles.Icons.ProgressLeft, progressBarSegments-filled)) +
+		styles.PreviewMetaStyle.Render(fmt.Sprintf(" %d/%d (%d%%)", p.progressDone, p.progressTotal, p.progressDone*100/p.progressTotal))
+}
+
+// contentWidth returns the width available to note content
+func (p *Preview) contentWidth() int {
+	return max(p.width, 30) - 6
+}
+
+// contentLines returns the note content as display lines for the current
+// mode. Large notes return their source lines unwrapped, so scrolling maps
+// one-to-one onto the note and nothing is rendered beyond the window.
+func (p *Preview) contentLines(width int) []string {
+	if p.isLarge() {
+		return p.sourceLines()
+	}
+
+	content := p.note.Content
+	if p.lines != nil && content == p.linesSource && width == p.linesWidth && p.raw == p.linesRaw {
+		return p.lines
+	}
+
+	var out string
+	if p.raw {
+		out = lipgloss.NewStyle().Width(width).Render(content)
+	} else {
+		out = p.renderMarkdown(content, width)
+	}
+	p.lines = strings.Split(out, "\n")
+	p.linesSource = content
+	p.linesWidth = width
+	p.linesRaw = p.raw
+	return p.lines
+}
+
+// sourceLines returns the note content split into lines, reusing the last
+// split while the content is unchanged.
+func (p *Preview) sourceLines() []string {
+	if p.source == nil || p.note.Content != p.sourceContent {
+		p.source = strings.Split(p.note.Content, "\n")
+		p.sourceContent = p.note.Content
+	}
+	return p.source
+}
+
+// isLarge reports whether the note exceeds the large note threshold
+func (p *Preview) isLarge() bool {
+	return p.largeLines > 0 && p.note != nil && len(p.sourceLines()) > p.largeLines
+}
+
+// renderMarkdown renders content with glamour, reusing the last result
+// when neither the content nor the width changed. It falls back to plain
+// wrapping if rendering fails.
+func (p *Preview) renderMarkdown(content string, width int) string {
+	if content == p.renderedSource && width == p.renderedWidth && p.rendered != "" {
+		return p.rendered
+	}
+
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle("dark"),
+		glamour.WithWordWrap(width),
+	)
+	var out string
+	if err == nil {
+		out, err = renderer.Render(content)
+	}
+	if err != nil {
+		return lipgloss.NewStyle().Width(width).Render(content)
+	}
+
+	p.rendered = trimBlankLines(out)
+	p.renderedSource = content
+	p.renderedWidth = width
+	return p.rendered
+}
+
+// firstMatch returns the index of the first display line containing a
+// highlighted word, or -1
+func (p *Preview) firstMatch(lines []string) int {
+	for i, line := range lines {
+		if len(matchSpans([]rune(ansi.Strip(line)), p.highlight)) > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// highlightLine marks the highlighted words in a display line. The line
+// may carry the markdown renderer's ANSI styling, so matching runs on the
+// visible text and the styled line is cut around each match; each cut
+// keeps the escape codes before it, so the styling resumes after a match.
+func (p *Preview) highlightLine(line string) string {
+	if len(p.highlight) == 0 {
+		return line
+	}
+	plain := []rune(ansi.Strip(line))
+	spans := matchSpans(plain, p.highlight)
+	if len(spans) == 0 {
+		return line
+	}
+
+	var b strings.Builder
+	pos := 0
+	for _, span := range spans {
+		start := ansi.StringWidth(string(plain[:span[0]]))
+		end := ansi.StringWidth(string(plain[:span[1]]))
+		b.WriteString(ansi.Cut(line, pos, start))
+		b.WriteString(styles.SearchMatchStyle.Render(ansi.Strip(ansi.Cut(line, start, end))))
+		pos = end
+	}
+	b.WriteString(ansi.Cut(line, pos, ansi.StringWidth(line)))
+	return b.String()
+}
+
+// matchSpans returns the rune ranges of text matching any of the lowercase
+// words, case-insensitively, in order and without overlaps
+func matchSpans(text []rune, words [][]rune) [][2]int {
+	var spans [][2]int
+	for i := 0; i < len(text); {
+		longest := 0
+		for _, word := range words {
+			if len(word) > longest && hasPrefixFold(text[i:], word) {
+				longest = len(word)
+			}
+		}
+		if longest == 0 {
+			i++
+			continue
+		}
+		spans = append(spans, [2]int{i, i + longest})
+		i += longest
+	}
+	return spans
+}
+
+// hasPrefixFold reports whether text starts with the lowercase word,
+// ignoring case
+func hasPrefixFold(text, word []rune) bool {
+	if len(text) < len(word) {
+		return false
+	}
+	for i, r := range word {
+		if unicode.ToLower(text[i]) != r {
+			return false
+		}
+	}
+	return true
+}
+
+// trimBlankLines drops leading and trailing lines that hold no visible text,
+// such as the margins and trailing reset codes glamour emits.
+func trimBlankLines(s string) string {
+	lines := strings.Split(s, "\n")
+	blank := func(line string) bool {
+		return strings.TrimSpace(ansi.Strip(line)) == ""
+	}
+	for len(lines) > 0 && blank(lines[0]) {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && blank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// previewFooter describes the hidden part of overflowing content
+func previewFooter(start, end, total int) string {
+	if start == 0 {
+		return fmt.Sprintf("— %d more lines ↓", total-end)
+	}
+	return fmt.Sprintf("lines %d–%d of %d", start+1, end, total)
+}
+
 // Width returns the preview width
 func (p *Preview) Width() int {
 	return p.width
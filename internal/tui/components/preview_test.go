@@ -0,0 +1,127 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+// largeContent returns a note body of lines "line 0000" on
+func largeContent(lines int) string {
+	out := make([]string, lines)
+	for i := range out {
+		out[i] = fmt.Sprintf("line %04d", i)
+	}
+	return strings.Join(out, "\n")
+}
+
+// newTestPreview previews content in a panel showing 14 content lines
+func newTestPreview(content string, threshold int) *Preview {
+	p := NewPreview()
+	p.SetSize(60, 20)
+	p.SetRaw(true)
+	p.SetLargeThreshold(threshold)
+	p.SetNote(&models.Note{Title: "big", Content: content})
+	return p
+}
+
+func TestPreview_LargeNoteWindow(t *testing.T) {
+	const total = 5000
+	tests := []struct {
+		name       string
+		scroll     int
+		wantScroll int
+		wantShown  []string
+		wantGone   []string
+		wantFooter string
+	}{
+		{"top", 0, 0, []string{"line 0000", "line 0013"}, []string{"line 0014"}, "— 4986 more lines ↓"},
+		{"middle", 2000, 2000, []string{"line 2000", "line 2013"}, []string{"line 1999", "line 2014"}, "lines 2001–2014 of 5000"},
+		{"past the end", total + 100, total - 14, []string{"line 4986", "line 4999"}, []string{"line 4985"}, "lines 4987–5000 of 5000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPreview(largeContent(total), 1000)
+			p.scroll = tt.scroll
+
+			view := ansi.Strip(p.View())
+			if p.scroll != tt.wantScroll {
+				t.Errorf("scroll = %d, want %d", p.scroll, tt.wantScroll)
+			}
+			for _, line := range tt.wantShown {
+				if !strings.Contains(view, line) {
+					t.Errorf("view is missing %q", line)
+				}
+			}
+			for _, line := range tt.wantGone {
+				if strings.Contains(view, line) {
+					t.Errorf("view shows %q outside the window", line)
+				}
+			}
+			if !strings.Contains(view, tt.wantFooter) {
+				t.Errorf("view is missing footer %q", tt.wantFooter)
+			}
+			if !strings.Contains(view, "Large note: plain text") {
+				t.Error("view does not mark the note as large")
+			}
+		})
+	}
+}
+
+func TestPreview_IsLarge(t *testing.T) {
+	tests := []struct {
+		name      string
+		lines     int
+		threshold int
+		want      bool
+	}{
+		{"below", 99, 100, false},
+		{"at", 100, 100, false},
+		{"above", 101, 100, true},
+		{"disabled", 5000, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPreview(largeContent(tt.lines), tt.threshold)
+			if got := p.isLarge(); got != tt.want {
+				t.Errorf("isLarge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreview_SourceLinesCached(t *testing.T) {
+	p := newTestPreview(largeContent(2000), 1000)
+	first := p.sourceLines()
+	if second := p.sourceLines(); &first[0] != &second[0] {
+		t.Error("sourceLines() split the unchanged content again")
+	}
+
+	p.SetNote(&models.Note{Title: "big", Content: largeContent(3000)})
+	if got := len(p.sourceLines()); got != 3000 {
+		t.Errorf("got %d lines after the content changed, want 3000", got)
+	}
+}
+
+func BenchmarkPreview_View(b *testing.B) {
+	content := largeContent(20000)
+	for _, bb := range []struct {
+		name      string
+		threshold int
+	}{
+		{"windowed", 1000},
+		{"full", 0},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			p := newTestPreview(content, bb.threshold)
+			for i := 0; i < b.N; i++ {
+				p.ScrollDown()
+				p.View()
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package components
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tranducquang/kiroku/internal/tui/keys"
+)
+
+// ScrollList tracks a cursor over a list of items and the window of
+// items that fits in the visible height. Components compose it instead
+// of reimplementing cursor movement and viewport math.
+type ScrollList struct {
+	cursor int
+	offset int
+	total  int
+	height int
+}
+
+// NewScrollList creates a new scroll list
+func NewScrollList() *ScrollList {
+	return &ScrollList{height: 1}
+}
+
+// SetTotal sets the number of items and keeps the cursor in range
+func (l *ScrollList) SetTotal(total int) {
+	l.total = total
+	l.SetCursor(l.cursor)
+}
+
+// Total returns the number of items
+func (l *ScrollList) Total() int {
+	return l.total
+}
+
+// SetHeight sets how many items fit in the visible window
+func (l *ScrollList) SetHeight(height int) {
+	if height < 1 {
+		height = 1
+	}
+	l.height = height
+}
+
+// Height returns how many items fit in the visible window
+func (l *ScrollList) Height() int {
+	return l.height
+}
+
+// Cursor returns the current cursor position
+func (l *ScrollList) Cursor() int {
+	return l.cursor
+}
+
+// SetCursor moves the cursor, clamped to the item range
+func (l *ScrollList) SetCursor(cursor int) {
+	if cursor >= l.total {
+		cursor = l.total - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	l.cursor = cursor
+}
+
+// Up moves the cursor up one item
+func (l *ScrollList) Up() {
+	l.SetCursor(l.cursor - 1)
+}
+
+// Down moves the cursor down one item
+func (l *ScrollList) Down() {
+	l.SetCursor(l.cursor + 1)
+}
+
+// PageUp moves the cursor up one window
+func (l *ScrollList) PageUp() {
+	l.SetCursor(l.cursor - l.height)
+}
+
+// PageDown moves the cursor down one window
+func (l *ScrollList) PageDown() {
+	l.SetCursor(l.cursor + l.height)
+}
+
+// Home moves the cursor to the first item
+func (l *ScrollList) Home() {
+	l.SetCursor(0)
+}
+
+// End moves the cursor to the last item
+func (l *ScrollList) End() {
+	l.SetCursor(l.total - 1)
+}
+
+// HandleKey applies the navigation keys and reports whether the key was handled
+func (l *ScrollList) HandleKey(msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, keys.DefaultKeyMap.Up):
+		l.Up()
+	case key.Matches(msg, keys.DefaultKeyMap.Down):
+		l.Down()
+	case key.Matches(msg, keys.DefaultKeyMap.PageUp):
+		l.PageUp()
+	case key.Matches(msg, keys.DefaultKeyMap.PageDown):
+		l.PageDown()
+	case key.Matches(msg, keys.DefaultKeyMap.Home):
+		l.Home()
+	case key.Matches(msg, keys.DefaultKeyMap.End):
+		l.End()
+	default:
+		return false
+	}
+	return true
+}
+
+// Window returns the [start, end) range of items to render. The window
+// keeps its previous offset and only scrolls as far as needed to keep
+// the cursor visible, in either direction.
+func (l *ScrollList) Window() (start, end int) {
+	if l.cursor < l.offset {
+		l.offset = l.cursor
+	}
+	if l.cursor >= l.offset+l.height {
+		l.offset = l.cursor - l.height + 1
+	}
+	if l.offset > l.total-l.height {
+		l.offset = l.total - l.height
+	}
+	if l.offset < 0 {
+		l.offset = 0
+	}
+
+	end = l.offset + l.height
+	if end > l.total {
+		end = l.total
+	}
+	return l.offset, end
+}
+
+// listVisibleHeight returns how many rows fit in a bordered panel of the
+// given height after the title, separator and padding.
+func listVisibleHeight(height int) int {
+	contentHeight := height - 2
+	if contentHeight < 5 {
+		contentHeight = 5
+	}
+	return contentHeight - 3
+}
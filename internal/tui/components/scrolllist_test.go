@@ -0,0 +1,196 @@
+package components
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newTestList returns a list of total items showing height at a time
+func newTestList(total, height int) *ScrollList {
+	l := NewScrollList()
+	l.SetTotal(total)
+	l.SetHeight(height)
+	return l
+}
+
+func TestScrollList_SetCursorClamps(t *testing.T) {
+	tests := []struct {
+		name   string
+		total  int
+		cursor int
+		want   int
+	}{
+		{name: "in range", total: 10, cursor: 4, want: 4},
+		{name: "below the first item", total: 10, cursor: -3, want: 0},
+		{name: "past the last item", total: 10, cursor: 12, want: 9},
+		{name: "empty list", total: 0, cursor: 5, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestList(tt.total, 5)
+			l.SetCursor(tt.cursor)
+			if got := l.Cursor(); got != tt.want {
+				t.Errorf("Cursor() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrollList_SetTotalKeepsCursorInRange(t *testing.T) {
+	l := newTestList(10, 5)
+	l.End()
+	l.SetTotal(4)
+	if got := l.Cursor(); got != 3 {
+		t.Errorf("Cursor() after shrinking to 4 items = %d, want 3", got)
+	}
+}
+
+func TestScrollList_Window(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		height    int
+		moves     func(l *ScrollList)
+		wantStart int
+		wantEnd   int
+	}{
+		{
+			name: "fits without scrolling", total: 3, height: 5,
+			moves:     func(l *ScrollList) { l.End() },
+			wantStart: 0, wantEnd: 3,
+		},
+		{
+			name: "top", total: 20, height: 5,
+			moves:     func(l *ScrollList) {},
+			wantStart: 0, wantEnd: 5,
+		},
+		{
+			name: "last row of the window", total: 20, height: 5,
+			moves:     func(l *ScrollList) { l.SetCursor(4) },
+			wantStart: 0, wantEnd: 5,
+		},
+		{
+			name: "one past the window scrolls by one", total: 20, height: 5,
+			moves:     func(l *ScrollList) { l.SetCursor(5) },
+			wantStart: 1, wantEnd: 6,
+		},
+		{
+			name: "end", total: 20, height: 5,
+			moves:     func(l *ScrollList) { l.End() },
+			wantStart: 15, wantEnd: 20,
+		},
+		{
+			name: "home after end", total: 20, height: 5,
+			moves: func(l *ScrollList) {
+				l.End()
+				l.Window()
+				l.Home()
+			},
+			wantStart: 0, wantEnd: 5,
+		},
+		{
+			name: "moving up inside the window keeps it", total: 20, height: 5,
+			moves: func(l *ScrollList) {
+				l.SetCursor(12)
+				l.Window()
+				l.SetCursor(9)
+			},
+			wantStart: 8, wantEnd: 13,
+		},
+		{
+			name: "moving above the window scrolls it up", total: 20, height: 5,
+			moves: func(l *ScrollList) {
+				l.SetCursor(12)
+				l.Window()
+				l.SetCursor(6)
+			},
+			wantStart: 6, wantEnd: 11,
+		},
+		{
+			name: "page down", total: 20, height: 5,
+			moves:     func(l *ScrollList) { l.PageDown(); l.Window(); l.PageDown() },
+			wantStart: 6, wantEnd: 11,
+		},
+		{
+			name: "page up past the top", total: 20, height: 5,
+			moves: func(l *ScrollList) {
+				l.SetCursor(3)
+				l.Window()
+				l.PageUp()
+			},
+			wantStart: 0, wantEnd: 5,
+		},
+		{
+			name: "growing the height pulls the window back", total: 20, height: 5,
+			moves: func(l *ScrollList) {
+				l.End()
+				l.Window()
+				l.SetHeight(8)
+			},
+			wantStart: 12, wantEnd: 20,
+		},
+		{
+			name: "shrinking the height keeps the cursor visible", total: 20, height: 8,
+			moves: func(l *ScrollList) {
+				l.SetCursor(7)
+				l.Window()
+				l.SetHeight(3)
+			},
+			wantStart: 5, wantEnd: 8,
+		},
+		{
+			name: "removing items pulls the window back", total: 20, height: 5,
+			moves: func(l *ScrollList) {
+				l.End()
+				l.Window()
+				l.SetTotal(7)
+			},
+			wantStart: 2, wantEnd: 7,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestList(tt.total, tt.height)
+			tt.moves(l)
+			start, end := l.Window()
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("Window() = [%d, %d), want [%d, %d)", start, end, tt.wantStart, tt.wantEnd)
+			}
+			if c := l.Cursor(); tt.total > 0 && (c < start || c >= end) {
+				t.Errorf("cursor %d is outside the window [%d, %d)", c, start, end)
+			}
+		})
+	}
+}
+
+func TestScrollList_HandleKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         tea.KeyMsg
+		wantCursor  int
+		wantHandled bool
+	}{
+		{name: "up", key: tea.KeyMsg{Type: tea.KeyUp}, wantCursor: 9, wantHandled: true},
+		{name: "k", key: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")}, wantCursor: 9, wantHandled: true},
+		{name: "down", key: tea.KeyMsg{Type: tea.KeyDown}, wantCursor: 11, wantHandled: true},
+		{name: "j", key: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}, wantCursor: 11, wantHandled: true},
+		{name: "page up", key: tea.KeyMsg{Type: tea.KeyPgUp}, wantCursor: 6, wantHandled: true},
+		{name: "page down", key: tea.KeyMsg{Type: tea.KeyPgDown}, wantCursor: 14, wantHandled: true},
+		{name: "home", key: tea.KeyMsg{Type: tea.KeyHome}, wantCursor: 0, wantHandled: true},
+		{name: "end", key: tea.KeyMsg{Type: tea.KeyEnd}, wantCursor: 19, wantHandled: true},
+		{name: "other key", key: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")}, wantCursor: 10, wantHandled: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestList(20, 4)
+			l.SetCursor(10)
+			if handled := l.HandleKey(tt.key); handled != tt.wantHandled {
+				t.Errorf("HandleKey() = %v, want %v", handled, tt.wantHandled)
+			}
+			if got := l.Cursor(); got != tt.wantCursor {
+				t.Errorf("Cursor() = %d, want %d", got, tt.wantCursor)
+			}
+		})
+	}
+}
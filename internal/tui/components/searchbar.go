@@ -79,7 +79,7 @@ func (s *SearchBar) Update(msg tea.Msg) (*SearchBar, tea.Cmd) {
 
 // View renders the search bar
 func (s *SearchBar) View() string {
-	icon := styles.SearchIconStyle.Render("🔍 ")
+	icon := styles.SearchIconStyle.Render(styles.Icons.Search + " ")
 
 	style := styles.SearchBarStyle.Width(s.width - 4)
 	if s.active {
@@ -0,0 +1,117 @@
+package components
+
+import (
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/tranducquang/kiroku/internal/tui/styles"
+)
+
+// SelectOption is one choice in a SelectMenu
+type SelectOption struct {
+	Label string
+	// Marker is rendered before the label, e.g. a colored icon
+	Marker string
+}
+
+// SelectMenu is a compact overlay for picking one of a few options with
+// the arrow keys, or directly with the option's number
+type SelectMenu struct {
+	visible bool
+	width   int
+	title   string
+	options []SelectOption
+	cursor  int
+}
+
+// NewSelectMenu creates a new select menu
+func NewSelectMenu() *SelectMenu {
+	return &SelectMenu{}
+}
+
+// Show opens the menu with the option at selected highlighted
+func (m *SelectMenu) Show(title string, options []SelectOption, selected int) {
+	m.title = title
+	m.options = options
+	m.cursor = max(min(selected, len(options)-1), 0)
+	m.visible = true
+}
+
+// Hide hides the menu
+func (m *SelectMenu) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns whether the menu is visible
+func (m *SelectMenu) IsVisible() bool {
+	return m.visible
+}
+
+// SetSize sets the screen width the menu is centered in
+func (m *SelectMenu) SetSize(width, height int) {
+	m.width = width
+}
+
+// Selected returns the index of the highlighted option
+func (m *SelectMenu) Selected() int {
+	return m.cursor
+}
+
+// Update moves the highlight with the arrow keys. A number key highlights
+// that option and reports true, so the caller can apply it at once. Enter
+// and Esc are left to the caller.
+func (m *SelectMenu) Update(msg tea.Msg) (*SelectMenu, bool) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !m.visible || !ok {
+		return m, false
+	}
+
+	switch keyMsg.String() {
+	case "up", "k", "ctrl+p":
+		m.cursor = (m.cursor - 1 + len(m.options)) % len(m.options)
+	case "down", "j", "ctrl+n":
+		m.cursor = (m.cursor + 1) % len(m.options)
+	default:
+		if n, err := strconv.Atoi(keyMsg.String()); err == nil && n >= 0 && n < len(m.options) {
+			m.cursor = n
+			return m, true
+		}
+	}
+	return m, false
+}
+
+// View renders the menu
+func (m *SelectMenu) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	dialogWidth := 40
+	if m.width > 0 && m.width < dialogWidth+4 {
+		dialogWidth = m.width - 4
+	}
+
+	var b strings.Builder
+	b.WriteString(styles.DialogTitleStyle.Render(ansi.Truncate(m.title, dialogWidth-6, "…")))
+	b.WriteString("\n\n")
+	for i, option := range m.options {
+		marker := option.Marker
+		if marker == "" {
+			marker = " "
+		}
+		row := strconv.Itoa(i) + " " + marker + " " + option.Label
+		if i == m.cursor {
+			b.WriteString(styles.NoteItemSelectedStyle.Render("  " + row))
+		} else {
+			b.WriteString(styles.NoteItemStyle.Render("  " + row))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(styles.TextMuted.Render("Enter or number choose • Esc cancel"))
+
+	return styles.DialogStyle.Width(dialogWidth).Render(b.String())
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/tranducquang/kiroku/internal/models"
 	"github.com/tranducquang/kiroku/internal/tui/keys"
@@ -16,19 +17,23 @@ import (
 type Sidebar struct {
 	folders     []*models.Folder
 	flatList    []sidebarItem
-	cursor      int
+	list        *ScrollList
 	height      int
 	width       int
 	focused     bool
 	showAll     bool
 	showTodos   bool
+	showToday   bool
+	showRecent  bool
+	showReview  bool
 	showStarred bool
+	unfiled     int
 }
 
 type sidebarItem struct {
 	folder    *models.Folder
 	isSpecial bool
-	special   string // "all", "todos", "starred"
+	special   string // "all", "todos", "today", "recent", "review", "starred", "unfiled"
 	level     int
 }
 
@@ -37,8 +42,12 @@ func NewSidebar() *Sidebar {
 	return &Sidebar{
 		folders:     make([]*models.Folder, 0),
 		flatList:    make([]sidebarItem, 0),
+		list:        NewScrollList(),
 		showAll:     true,
 		showTodos:   true,
+		showToday:   true,
+		showRecent:  true,
+		showReview:  true,
 		showStarred: true,
 	}
 }
@@ -49,10 +58,21 @@ func (s *Sidebar) SetFolders(folders []*models.Folder) {
 	s.buildFlatList()
 }
 
+// SetUnfiledCount sets how many notes are outside any folder. The
+// Unfiled item is only listed while there are some.
+func (s *Sidebar) SetUnfiledCount(count int) {
+	if count == s.unfiled {
+		return
+	}
+	s.unfiled = count
+	s.buildFlatList()
+}
+
 // SetSize sets the sidebar dimensions
 func (s *Sidebar) SetSize(width, height int) {
 	s.width = width
 	s.height = height
+	s.list.SetHeight(listVisibleHeight(height))
 }
 
 // SetFocused sets the focus state
@@ -60,17 +80,51 @@ func (s *Sidebar) SetFocused(focused bool) {
 	s.focused = focused
 }
 
-// SelectFolder selects a folder by ID
+// SelectFolder selects a folder by ID, expanding any collapsed ancestors
+// so the folder is in the list
 func (s *Sidebar) SelectFolder(folderID int64) {
+	if expandAncestors(s.folders, folderID) {
+		s.buildFlatList()
+	}
 	for i, item := range s.flatList {
 		if !item.isSpecial && item.folder != nil && item.folder.ID == folderID {
-			s.cursor = i
-			// Ensure we expand parents if necessary?
-			// For now just selecting it is enough given flatList structure.
-			// But wait, if parents are collapsed, it might not be in the list?
-			// The flatList is rebuilt based on Expanded state.
-			// If we jump to a folder that is hidden, we need to expand its parents.
-			// That's complex. Let's assume for now it's reachable or we force expand.
+			s.list.SetCursor(i)
+			return
+		}
+	}
+}
+
+// expandAncestors expands every folder on the path to folderID and reports
+// whether any of them was collapsed
+func expandAncestors(folders []*models.Folder, folderID int64) bool {
+	for _, folder := range folders {
+		if folder.ID == folderID {
+			return false
+		}
+		if containsFolder(folder.Children, folderID) {
+			changed := !folder.Expanded
+			folder.Expanded = true
+			return expandAncestors(folder.Children, folderID) || changed
+		}
+	}
+	return false
+}
+
+// containsFolder reports whether folderID is anywhere in the tree
+func containsFolder(folders []*models.Folder, folderID int64) bool {
+	for _, folder := range folders {
+		if folder.ID == folderID || containsFolder(folder.Children, folderID) {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectSpecial selects a special item such as "todos" or "starred"
+func (s *Sidebar) SelectSpecial(special string) {
+	for i, item := range s.flatList {
+		if item.isSpecial && item.special == special {
+			s.list.SetCursor(i)
 			return
 		}
 	}
@@ -83,31 +137,33 @@ func (s *Sidebar) IsFocused() bool {
 
 // Cursor returns the current cursor position
 func (s *Sidebar) Cursor() int {
-	return s.cursor
+	return s.list.Cursor()
 }
 
 // SelectedFolder returns the currently selected folder
 func (s *Sidebar) SelectedFolder() *models.Folder {
-	if s.cursor < 0 || s.cursor >= len(s.flatList) {
-		return nil
-	}
-	item := s.flatList[s.cursor]
-	if item.isSpecial {
+	item := s.selectedItem()
+	if item == nil || item.isSpecial {
 		return nil
 	}
 	return item.folder
 }
 
-// SelectedSpecial returns the selected special item ("all", "todos", "starred")
+// SelectedSpecial returns the selected special item ("all", "todos", "today", "recent", "review", "starred", "unfiled")
 func (s *Sidebar) SelectedSpecial() string {
-	if s.cursor < 0 || s.cursor >= len(s.flatList) {
+	item := s.selectedItem()
+	if item == nil || !item.isSpecial {
 		return ""
 	}
-	item := s.flatList[s.cursor]
-	if item.isSpecial {
-		return item.special
+	return item.special
+}
+
+func (s *Sidebar) selectedItem() *sidebarItem {
+	cursor := s.list.Cursor()
+	if cursor < 0 || cursor >= len(s.flatList) {
+		return nil
 	}
-	return ""
+	return &s.flatList[cursor]
 }
 
 // buildFlatList builds a flat list of items for display
@@ -123,11 +179,25 @@ func (s *Sidebar) buildFlatList() {
 
 	// Add folders
 	s.addFoldersToList(s.folders, 0)
+	if s.unfiled > 0 {
+		s.flatList = append(s.flatList, sidebarItem{isSpecial: true, special: "unfiled"})
+	}
 
 	// Add quick access
+	if s.showToday {
+		s.flatList = append(s.flatList, sidebarItem{isSpecial: true, special: "today"})
+	}
+	if s.showRecent {
+		s.flatList = append(s.flatList, sidebarItem{isSpecial: true, special: "recent"})
+	}
 	if s.showTodos {
 		s.flatList = append(s.flatList, sidebarItem{isSpecial: true, special: "todos"})
 	}
+	if s.showReview {
+		s.flatList = append(s.flatList, sidebarItem{isSpecial: true, special: "review"})
+	}
+
+	s.list.SetTotal(len(s.flatList))
 }
 
 func (s *Sidebar) addFoldersToList(folders []*models.Folder, level int) {
@@ -150,34 +220,23 @@ func (s *Sidebar) Update(msg tea.Msg) (*Sidebar, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if s.list.HandleKey(msg) {
+			return s, nil
+		}
 		switch {
-		case key.Matches(msg, keys.DefaultKeyMap.Up):
-			if s.cursor > 0 {
-				s.cursor--
-			}
-		case key.Matches(msg, keys.DefaultKeyMap.Down):
-			if s.cursor < len(s.flatList)-1 {
-				s.cursor++
-			}
 		case key.Matches(msg, keys.DefaultKeyMap.Right), key.Matches(msg, keys.DefaultKeyMap.Enter):
 			// Expand folder
-			if s.cursor >= 0 && s.cursor < len(s.flatList) {
-				item := s.flatList[s.cursor]
-				if !item.isSpecial && item.folder != nil && len(item.folder.Children) > 0 {
-					item.folder.Expanded = true
-					s.buildFlatList()
-				}
+			folder := s.SelectedFolder()
+			if folder != nil && len(folder.Children) > 0 {
+				folder.Expanded = true
+				s.buildFlatList()
 			}
 		case key.Matches(msg, keys.DefaultKeyMap.Left):
 			// Collapse folder
-			if s.cursor >= 0 && s.cursor < len(s.flatList) {
-				item := s.flatList[s.cursor]
-				if !item.isSpecial && item.folder != nil {
-					if item.folder.Expanded {
-						item.folder.Expanded = false
-						s.buildFlatList()
-					}
-				}
+			folder := s.SelectedFolder()
+			if folder != nil && folder.Expanded {
+				folder.Expanded = false
+				s.buildFlatList()
 			}
 		}
 	}
@@ -199,47 +258,43 @@ func (s *Sidebar) View() string {
 		contentHeight = 5
 	}
 
-	// Title
-	title := styles.SidebarTitleStyle.Render("📁 FOLDERS")
-	b.WriteString(title)
-	b.WriteString("\n")
-
 	sepWidth := width - 6
 	if sepWidth < 10 {
 		sepWidth = 10
 	}
-	b.WriteString(strings.Repeat("─", sepWidth))
-	b.WriteString("\n")
 
-	// Calculate visible range (subtract 3 for title, separator, paddings)
-	visibleHeight := contentHeight - 3
-	if visibleHeight < 1 {
-		visibleHeight = 1
-	}
+	cursor := s.list.Cursor()
+	startIdx, endIdx := s.list.Window()
 
-	startIdx := 0
-	if s.cursor >= visibleHeight {
-		startIdx = s.cursor - visibleHeight + 1
-	}
-	endIdx := startIdx + visibleHeight
-	if endIdx > len(s.flatList) {
-		endIdx = len(s.flatList)
+	// Title, with a hint on the right when items are scrolled off the top
+	title := styles.PanelTitle(styles.SidebarTitleStyle, styles.Icons.Folder+" FOLDERS", s.focused)
+	if startIdx > 0 {
+		hint := styles.TextMuted.Render(fmt.Sprintf("▲ %d", startIdx))
+		gap := max(sepWidth-lipgloss.Width(title)-lipgloss.Width(hint), 1)
+		title += strings.Repeat(" ", gap) + hint
 	}
+	b.WriteString(title)
+	b.WriteString("\n")
+
+	b.WriteString(strings.Repeat("─", sepWidth))
+	b.WriteString("\n")
 
-	// Render items
 	for i := startIdx; i < endIdx; i++ {
 		item := s.flatList[i]
-		line := s.renderItem(item, i == s.cursor)
+		line := s.renderItem(item, i == cursor)
 		b.WriteString(line)
 		if i < endIdx-1 {
 			b.WriteString("\n")
 		}
 	}
 
-	style := styles.SidebarStyle.Width(width - 4).Height(contentHeight)
-	if s.focused {
-		style = style.BorderForeground(styles.Primary)
+	// The list leaves one spare row above the border for this hint
+	if below := len(s.flatList) - endIdx; below > 0 {
+		b.WriteString("\n")
+		b.WriteString(styles.TextMuted.Render(fmt.Sprintf("▼ %d more", below)))
 	}
+
+	style := styles.PanelBorder(styles.SidebarStyle.Width(width-4).Height(contentHeight), s.focused)
 	return style.Render(b.String())
 }
 
@@ -250,27 +305,40 @@ func (s *Sidebar) renderItem(item sidebarItem, selected bool) string {
 	if item.isSpecial {
 		switch item.special {
 		case "all":
-			icon = "📋"
+			icon = styles.Icons.AllNotes
 			name = "All Notes"
+		case "today":
+			icon = styles.Icons.Today
+			name = "Today"
+		case "recent":
+			icon = styles.Icons.Recent
+			name = "Recent"
 		case "todos":
-			icon = "☐"
+			icon = styles.Icons.Todos
 			name = "Todos"
+		case "review":
+			icon = styles.Icons.Review
+			name = "Review"
 		case "starred":
-			icon = "⭐"
+			icon = styles.Icons.Starred
 			name = "Starred"
+		case "unfiled":
+			icon = styles.Icons.Unfiled
+			name = "Unfiled"
+			count = fmt.Sprintf(" (%d)", s.unfiled)
 		}
 	} else if item.folder != nil {
-		icon = item.folder.Icon
+		icon = styles.FolderIcon(item.folder.Icon)
 		if item.folder.Starred {
-			icon = "⭐"
+			icon = styles.Icons.Starred
 		}
 		name = item.folder.Name
 		// Show expand/collapse indicator
 		if len(item.folder.Children) > 0 {
 			if item.folder.Expanded {
-				indent += "▾ "
+				indent += styles.Icons.Expanded + " "
 			} else {
-				indent += "▸ "
+				indent += styles.Icons.Collapse + " "
 			}
 		} else {
 			indent += "  "
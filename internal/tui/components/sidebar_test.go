@@ -0,0 +1,99 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tranducquang/kiroku/internal/models"
+)
+
+// newTestSidebar returns a focused sidebar sized to show seven items
+func newTestSidebar(folders []*models.Folder) *Sidebar {
+	s := NewSidebar()
+	s.SetSize(30, 12)
+	s.SetFocused(true)
+	s.SetFolders(folders)
+	return s
+}
+
+// flatFolders returns count top-level folders named "folder 00" on
+func flatFolders(count int) []*models.Folder {
+	folders := make([]*models.Folder, count)
+	for i := range folders {
+		folders[i] = &models.Folder{ID: int64(i + 1), Name: fmt.Sprintf("folder %02d", i)}
+	}
+	return folders
+}
+
+func TestSidebar_WindowFollowsCursorUp(t *testing.T) {
+	tests := []struct {
+		name      string
+		move      func(s *Sidebar)
+		wantShown string
+	}{
+		{name: "select a folder above the window", move: func(s *Sidebar) { s.SelectFolder(1) }, wantShown: "folder 00"},
+		{name: "select a special item above the window", move: func(s *Sidebar) { s.SelectSpecial("starred") }, wantShown: "Starred"},
+		{name: "home", move: func(s *Sidebar) { s.Update(tea.KeyMsg{Type: tea.KeyHome}) }, wantShown: "Starred"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSidebar(flatFolders(20))
+			s.Update(tea.KeyMsg{Type: tea.KeyEnd})
+			if view := s.View(); strings.Contains(view, "folder 00") {
+				t.Fatalf("after End the first folder is still shown:\n%s", view)
+			}
+
+			tt.move(s)
+			if view := s.View(); !strings.Contains(view, tt.wantShown) {
+				t.Errorf("%q is not shown:\n%s", tt.wantShown, view)
+			}
+		})
+	}
+}
+
+// nestedFolders returns a collapsed tree three levels deep under each of
+// count top-level folders: "top 0" > "mid 0" > "leaf 0", with IDs
+// 10*i+1, 10*i+2 and 10*i+3
+func nestedFolders(count int) []*models.Folder {
+	folders := make([]*models.Folder, count)
+	for i := range folders {
+		base := int64(10 * i)
+		leaf := &models.Folder{ID: base + 3, Name: fmt.Sprintf("leaf %d", i)}
+		mid := &models.Folder{ID: base + 2, Name: fmt.Sprintf("mid %d", i), Children: []*models.Folder{leaf}}
+		folders[i] = &models.Folder{ID: base + 1, Name: fmt.Sprintf("top %d", i), Children: []*models.Folder{mid}}
+	}
+	return folders
+}
+
+func TestSidebar_SelectFolder_Nested(t *testing.T) {
+	tests := []struct {
+		name     string
+		folderID int64
+		wantName string
+	}{
+		{"top level", 1, "top 0"},
+		{"child of a collapsed folder", 12, "mid 1"},
+		{"grandchild of collapsed folders", 23, "leaf 2"},
+		{"grandchild below the window", 83, "leaf 8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSidebar(nestedFolders(10))
+			if view := s.View(); strings.Contains(view, "mid ") || strings.Contains(view, "leaf ") {
+				t.Fatalf("collapsed tree shows nested folders:\n%s", view)
+			}
+
+			s.SelectFolder(tt.folderID)
+			selected := s.SelectedFolder()
+			if selected == nil || selected.ID != tt.folderID {
+				t.Fatalf("selected %v, want folder %d", selected, tt.folderID)
+			}
+			if view := s.View(); !strings.Contains(view, tt.wantName) {
+				t.Errorf("%q is not shown:\n%s", tt.wantName, view)
+			}
+		})
+	}
+}
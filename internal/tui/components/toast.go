@@ -0,0 +1,111 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/tranducquang/kiroku/internal/tui/styles"
+)
+
+// ToastKind selects how a toast is styled
+type ToastKind int
+
+const (
+	ToastInfo ToastKind = iota
+	ToastSuccess
+	ToastError
+)
+
+const (
+	// maxToasts is how many toasts are shown at once; older ones are dropped
+	maxToasts = 4
+	// maxToastWidth caps the rendered width of a single toast
+	maxToastWidth = 48
+)
+
+type toast struct {
+	id   int
+	kind ToastKind
+	text string
+}
+
+// Toasts stacks short-lived notifications in the top-right corner so
+// rapid actions do not overwrite each other's feedback.
+type Toasts struct {
+	items  []toast
+	nextID int
+}
+
+// NewToasts creates a new toast stack
+func NewToasts() *Toasts {
+	return &Toasts{}
+}
+
+// Push adds a toast and returns its ID so the caller can dismiss it later
+func (t *Toasts) Push(kind ToastKind, text string) int {
+	t.nextID++
+	t.items = append(t.items, toast{id: t.nextID, kind: kind, text: text})
+	if len(t.items) > maxToasts {
+		t.items = t.items[len(t.items)-maxToasts:]
+	}
+	return t.nextID
+}
+
+// Dismiss removes the toast with the given ID if it is still shown
+func (t *Toasts) Dismiss(id int) {
+	for i, item := range t.items {
+		if item.id == id {
+			t.items = append(t.items[:i], t.items[i+1:]...)
+			return
+		}
+	}
+}
+
+// Empty reports whether there are no toasts to show
+func (t *Toasts) Empty() bool {
+	return len(t.items) == 0
+}
+
+// Overlay draws the toasts over the right edge of view, starting at the
+// given row. Lines underneath are truncated to make room.
+func (t *Toasts) Overlay(view string, width, row int) string {
+	if t.Empty() {
+		return view
+	}
+
+	lines := strings.Split(view, "\n")
+	for i := len(t.items) - 1; i >= 0 && row < len(lines); i-- {
+		rendered := t.render(t.items[i], width)
+		toastWidth := lipgloss.Width(rendered)
+
+		left := ansi.Truncate(lines[row], width-toastWidth, "")
+		if pad := width - toastWidth - lipgloss.Width(left); pad > 0 {
+			left += strings.Repeat(" ", pad)
+		}
+		lines[row] = left + rendered
+		row++
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (t *Toasts) render(item toast, width int) string {
+	icon, style := styles.Icons.Info, styles.ToastInfoStyle
+	switch item.kind {
+	case ToastSuccess:
+		icon, style = styles.Icons.Success, styles.ToastSuccessStyle
+	case ToastError:
+		icon, style = styles.Icons.Error, styles.ToastErrorStyle
+	}
+
+	// Leave room for the icon, a space and the style padding
+	maxText := maxToastWidth
+	if width/2 < maxText {
+		maxText = width / 2
+	}
+	text := ansi.Truncate(item.text, maxText-4, "…")
+
+	return style.Render(icon + " " + text)
+}
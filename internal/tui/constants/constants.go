@@ -10,12 +10,22 @@ const (
 	SidebarMinWidth = 20
 	// SidebarMaxWidth is the maximum width for the sidebar panel.
 	SidebarMaxWidth = 40
-	// SidebarWidthPercent is the percentage of screen width for sidebar.
+	// SidebarWidthPercent is the percentage of screen width for sidebar
+	// when no explicit width is configured.
 	SidebarWidthPercent = 25
+	// SidebarResizeStep is how many columns one resize key press changes.
+	SidebarResizeStep = 2
 	// StatusBarHeight is the height reserved for status bar.
 	StatusBarHeight = 3
 	// PreviewHeightRatio is the ratio of note list height for preview.
 	PreviewHeightRatio = 0.5
+	// SplitPanelMinWidth is the narrowest the note list and preview get
+	// when placed side by side; below it the preview goes under the list.
+	SplitPanelMinWidth = 30
+	// MinTerminalWidth is the narrowest terminal the layout renders in.
+	MinTerminalWidth = 60
+	// MinTerminalHeight is the shortest terminal the layout renders in.
+	MinTerminalHeight = 20
 )
 
 // Timing constants
@@ -24,6 +34,19 @@ const (
 	StatusMessageDuration = 2 * time.Second
 	// ErrorMessageDuration is how long error messages are displayed.
 	ErrorMessageDuration = 3 * time.Second
+	// SessionSaveInterval is how often the current view is saved while running.
+	SessionSaveInterval = 30 * time.Second
+	// PreviewSettleDelay is how long the note list selection must rest
+	// before the preview renders it.
+	PreviewSettleDelay = 80 * time.Millisecond
+)
+
+// History constants
+const (
+	// UndoHistoryLimit is how many note changes can be undone.
+	UndoHistoryLimit = 20
+	// NavHistoryLimit is how many visited folders and filters Back can return to.
+	NavHistoryLimit = 50
 )
 
 // Priority constants for todos
@@ -40,6 +63,13 @@ const (
 	PriorityMax = 4
 )
 
+// Input limits
+const (
+	// MaxNameLength is the longest folder name accepted from the create
+	// dialog. Note titles are limited by database.title_max_length.
+	MaxNameLength = 100
+)
+
 // Dialog types
 const (
 	DialogTypeNewNote      = "new_note"
@@ -47,7 +77,15 @@ const (
 	DialogTypeNewFolder    = "new_folder"
 	DialogTypeDelete       = "delete"
 	DialogTypeDeleteFolder = "delete_folder"
+	DialogTypeTypedDelete  = "typed_delete_folder"
 	DialogTypeConfirm      = "confirm"
+	DialogTypeCapture      = "capture"
+	DialogTypeMove         = "move"
+	DialogTypeDueDate      = "due_date"
+	DialogTypeCompleteAll  = "complete_all"
+	DialogTypeGoToFolder   = "go_to_folder"
+	DialogTypeUnlock       = "unlock"
+	DialogTypeFilterTag    = "filter_tag"
 )
 
 // Filter types for sidebar
@@ -55,4 +93,8 @@ const (
 	FilterAll     = "all"
 	FilterTodos   = "todos"
 	FilterStarred = "starred"
+	FilterToday   = "today"
+	FilterRecent  = "recent"
+	FilterReview  = "review"
+	FilterUnfiled = "unfiled"
 )
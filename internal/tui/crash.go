@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/tranducquang/kiroku/internal/logging"
+	"github.com/tranducquang/kiroku/internal/tui/styles"
+)
+
+// Crashed returns the panic that stopped the TUI, or nil if it exited
+// normally. The caller reports it once the terminal has been restored.
+func (a *App) Crashed() error {
+	return a.crash
+}
+
+// recordPanic logs a recovered panic with its stack and remembers it for
+// Crashed. Only the first panic is kept; later ones are usually fallout.
+func (a *App) recordPanic(r any) {
+	logging.Error().
+		Interface("panic", r).
+		Bytes("stack", debug.Stack()).
+		Msg("TUI panic recovered")
+	if a.crash == nil {
+		a.crash = fmt.Errorf("panic: %v", r)
+	}
+}
+
+// recoverUpdate turns a panic in Update into a clean quit, so BubbleTea
+// restores the terminal instead of leaving it in raw mode
+func (a *App) recoverUpdate(model *tea.Model, cmd *tea.Cmd) {
+	if r := recover(); r != nil {
+		a.recordPanic(r)
+		*model = a
+		*cmd = tea.Quit
+	}
+}
+
+// recoverView replaces a view that panicked with the crash screen. View
+// cannot return a command, so the next message quits from Update.
+func (a *App) recoverView(view *string) {
+	if r := recover(); r != nil {
+		a.recordPanic(r)
+		*view = a.renderCrashed()
+	}
+}
+
+// renderCrashed renders the screen shown when rendering itself panicked
+func (a *App) renderCrashed() string {
+	msg := lipgloss.JoinVertical(lipgloss.Center,
+		styles.ErrorStyle.Render("Kiroku crashed"),
+		styles.TextMuted.Render("press any key to exit"),
+	)
+	if a.width == 0 || a.height == 0 {
+		return msg
+	}
+	return lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, msg)
+}
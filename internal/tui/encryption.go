@@ -0,0 +1,102 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tranducquang/kiroku/internal/logging"
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/tui/components"
+	"github.com/tranducquang/kiroku/internal/tui/constants"
+	"github.com/tranducquang/kiroku/internal/tui/messages"
+)
+
+// lockedPreview is shown in place of an encrypted note's content until it
+// is unlocked
+const lockedPreview = "*Encrypted. Press U to unlock it for this session.*"
+
+// unlockedNote is an encrypted note opened during this session. It is only
+// kept in memory and only valid while the note still holds sealed.
+type unlockedNote struct {
+	passphrase string
+	sealed     string
+	content    string
+}
+
+// unlockedContent returns the plain content of an unlocked note
+func (a *App) unlockedContent(note *models.Note) (unlockedNote, bool) {
+	if note == nil || !note.Encrypted {
+		return unlockedNote{}, false
+	}
+	entry, ok := a.unlocked[note.ID]
+	if !ok || entry.sealed != note.Content {
+		return unlockedNote{}, false
+	}
+	return entry, true
+}
+
+// previewNote returns the note as the preview shows it: encrypted notes
+// carry their plain content once unlocked and a notice before that
+func (a *App) previewNote(note *models.Note) *models.Note {
+	if note == nil || !note.Encrypted {
+		return note
+	}
+	shown := *note
+	shown.Content = lockedPreview
+	if entry, ok := a.unlockedContent(note); ok {
+		shown.Content = entry.content
+	}
+	return &shown
+}
+
+// toggleUnlock asks for the passphrase of an encrypted note, or forgets it
+// when the note is already unlocked
+func (a *App) toggleUnlock(note *models.Note) tea.Cmd {
+	if !note.Encrypted {
+		return a.notify(components.ToastInfo, "Note is not encrypted")
+	}
+	if _, ok := a.unlockedContent(note); ok {
+		delete(a.unlocked, note.ID)
+		a.updatePreview()
+		return a.notify(components.ToastInfo, "Note locked again")
+	}
+
+	a.dialogNoteID = note.ID
+	a.dialog.ShowInput("Unlock: "+note.Title, "Passphrase")
+	a.dialog.SetMasked(true)
+	a.dialog.SetType(constants.DialogTypeUnlock)
+	a.showDialog = true
+	return nil
+}
+
+// handleNoteUnlocked keeps an opened note's content for the session
+func (a *App) handleNoteUnlocked(msg messages.NoteUnlockedMsg) (tea.Model, tea.Cmd) {
+	logging.Debug().Int64("note_id", msg.NoteID).Msg("Unlocked encrypted note")
+	a.unlocked[msg.NoteID] = unlockedNote{
+		passphrase: msg.Passphrase,
+		sealed:     msg.Sealed,
+		content:    msg.Content,
+	}
+	a.updatePreview()
+	return a, a.notify(components.ToastSuccess, "Note unlocked for this session")
+}
+
+// saveEdit saves an edited note, sealing it again when it is encrypted
+func (a *App) saveEdit(note *models.Note) tea.Cmd {
+	if note.Encrypted {
+		if entry, ok := a.unlocked[note.ID]; ok {
+			return a.runner.UpdateEncryptedNote(a.noteService, note, entry.passphrase)
+		}
+	}
+	return a.runner.UpdateNote(a.noteService, note)
+}
+
+// handleEncryptedNoteSaved keeps an unlocked note open after its edit is
+// sealed under a fresh salt
+func (a *App) handleEncryptedNoteSaved(msg messages.EncryptedNoteSavedMsg) (tea.Model, tea.Cmd) {
+	if entry, ok := a.unlocked[msg.Note.ID]; ok {
+		entry.sealed = msg.Note.Content
+		entry.content = msg.Content
+		a.unlocked[msg.Note.ID] = entry
+	}
+	return a.handleNoteUpdated(msg.NoteUpdatedMsg)
+}
@@ -5,31 +5,61 @@ import "github.com/charmbracelet/bubbles/key"
 // KeyMap defines all keybindings for the application
 type KeyMap struct {
 	// Navigation
-	Up     key.Binding
-	Down   key.Binding
-	Left   key.Binding
-	Right  key.Binding
-	Tab    key.Binding
-	Enter  key.Binding
-	Escape key.Binding
+	Up       key.Binding
+	Down     key.Binding
+	Left     key.Binding
+	Right    key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Home     key.Binding
+	End      key.Binding
+	Tab      key.Binding
+	Enter    key.Binding
+	Escape   key.Binding
+	GoTo     key.Binding
+	Back     key.Binding
+	Forward  key.Binding
+
+	// Layout
+	SidebarShrink key.Binding
+	SidebarGrow   key.Binding
 
 	// Actions
 	NewNote       key.Binding
 	NewTodo       key.Binding
 	NewFolder     key.Binding
 	Edit          key.Binding
+	OpenExternal  key.Binding
 	Delete        key.Binding
 	Search        key.Binding
 	ToggleStar    key.Binding
 	ToggleDone    key.Binding
+	CompleteAll   key.Binding
 	MoveNote      key.Binding
+	RepeatMove    key.Binding
+	MoveUp        key.Binding
+	MoveDown      key.Binding
+	CopyContent   key.Binding
+	CopyTitle     key.Binding
+	ToggleLock    key.Binding
+	Unlock        key.Binding
+	HideDone      key.Binding
+	FilterTag     key.Binding
+	Undo          key.Binding
+	Redo          key.Binding
 	CyclePriority key.Binding
+	PickPriority  key.Binding
+	CycleLabel    key.Binding
+	SetDueDate    key.Binding
+	Capture       key.Binding
+	Scratch       key.Binding
 
 	// Views
-	Help    key.Binding
-	Preview key.Binding
-	Quit    key.Binding
-	Refresh key.Binding
+	Help         key.Binding
+	Preview      key.Binding
+	ToggleRender key.Binding
+	Quit         key.Binding
+	Refresh      key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings
@@ -50,6 +80,22 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("right", "l"),
 		key.WithHelp("→/l", "right"),
 	),
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup"),
+		key.WithHelp("pgup", "page up"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys("pgdown"),
+		key.WithHelp("pgdn", "page down"),
+	),
+	Home: key.NewBinding(
+		key.WithKeys("home"),
+		key.WithHelp("home", "first item"),
+	),
+	End: key.NewBinding(
+		key.WithKeys("end"),
+		key.WithHelp("end", "last item"),
+	),
 	Tab: key.NewBinding(
 		key.WithKeys("tab"),
 		key.WithHelp("tab", "switch panel"),
@@ -62,6 +108,28 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("esc"),
 		key.WithHelp("esc", "back/cancel"),
 	),
+	GoTo: key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "go to folder"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("alt+left"),
+		key.WithHelp("alt+←", "previous folder"),
+	),
+	Forward: key.NewBinding(
+		key.WithKeys("alt+right"),
+		key.WithHelp("alt+→", "next folder"),
+	),
+
+	// Layout
+	SidebarShrink: key.NewBinding(
+		key.WithKeys("ctrl+left"),
+		key.WithHelp("ctrl+←", "shrink sidebar"),
+	),
+	SidebarGrow: key.NewBinding(
+		key.WithKeys("ctrl+right"),
+		key.WithHelp("ctrl+→", "grow sidebar"),
+	),
 
 	// Actions
 	NewNote: key.NewBinding(
@@ -80,6 +148,10 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("e"),
 		key.WithHelp("e", "edit"),
 	),
+	OpenExternal: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "open in default app"),
+	),
 	Delete: key.NewBinding(
 		key.WithKeys("d", "delete"),
 		key.WithHelp("d", "delete"),
@@ -96,14 +168,82 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("x", " "),
 		key.WithHelp("x/space", "toggle done"),
 	),
+	CompleteAll: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "complete all shown"),
+	),
 	MoveNote: key.NewBinding(
 		key.WithKeys("m"),
 		key.WithHelp("m", "move to folder"),
 	),
+	MoveUp: key.NewBinding(
+		key.WithKeys("shift+up"),
+		key.WithHelp("⇧↑", "move todo up"),
+	),
+	MoveDown: key.NewBinding(
+		key.WithKeys("shift+down"),
+		key.WithHelp("⇧↓", "move todo down"),
+	),
+	ToggleLock: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "toggle lock"),
+	),
+	Unlock: key.NewBinding(
+		key.WithKeys("U"),
+		key.WithHelp("U", "unlock encrypted"),
+	),
+	HideDone: key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H", "hide done in folders"),
+	),
+	FilterTag: key.NewBinding(
+		key.WithKeys("#"),
+		key.WithHelp("#", "filter by tag"),
+	),
+	Undo: key.NewBinding(
+		key.WithKeys("ctrl+z"),
+		key.WithHelp("ctrl+z", "undo"),
+	),
+	Redo: key.NewBinding(
+		key.WithKeys("ctrl+y"),
+		key.WithHelp("ctrl+y", "redo"),
+	),
+	CopyContent: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy content"),
+	),
+	CopyTitle: key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "copy title"),
+	),
+	RepeatMove: key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "repeat last move"),
+	),
 	CyclePriority: key.NewBinding(
 		key.WithKeys("p"),
 		key.WithHelp("p", "cycle priority"),
 	),
+	PickPriority: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "pick priority"),
+	),
+	CycleLabel: key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "cycle label"),
+	),
+	SetDueDate: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "set due date"),
+	),
+	Capture: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "quick capture"),
+	),
+	Scratch: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "scratch note"),
+	),
 
 	// Views
 	Help: key.NewBinding(
@@ -114,6 +254,10 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("v"),
 		key.WithHelp("v", "toggle preview"),
 	),
+	ToggleRender: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "raw/rendered preview"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "ctrl+c"),
 		key.WithHelp("q", "quit"),
@@ -133,10 +277,17 @@ func (k KeyMap) ShortHelp() []key.Binding {
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Left, k.Right},
-		{k.Tab, k.Enter, k.Escape},
-		{k.NewNote, k.NewTodo, k.NewFolder},
-		{k.Edit, k.Delete, k.Search},
-		{k.ToggleStar, k.ToggleDone, k.CyclePriority},
-		{k.Help, k.Preview, k.Quit, k.Refresh},
+		{k.PageUp, k.PageDown, k.Home, k.End},
+		{k.Tab, k.Enter, k.Escape, k.GoTo},
+		{k.Back, k.Forward},
+		{k.SidebarShrink, k.SidebarGrow},
+		{k.NewNote, k.NewTodo, k.NewFolder, k.Capture, k.Scratch},
+		{k.Edit, k.OpenExternal, k.Delete, k.Search},
+		{k.ToggleStar, k.ToggleDone, k.CyclePriority, k.SetDueDate, k.ToggleLock},
+		{k.PickPriority, k.CycleLabel, k.HideDone, k.FilterTag, k.CompleteAll, k.Unlock},
+		{k.MoveNote, k.RepeatMove, k.MoveUp, k.MoveDown},
+		{k.CopyContent, k.CopyTitle},
+		{k.Undo, k.Redo},
+		{k.Help, k.Preview, k.ToggleRender, k.Quit, k.Refresh},
 	}
 }
@@ -29,6 +29,17 @@ func (e ErrorMsg) Error() string {
 	return fmt.Sprintf("%s: %v", e.Context, e.Err)
 }
 
+// Error contexts for failures that leave the note list without fresh data.
+const (
+	ContextLoadNotes   = "load notes"
+	ContextReloadNotes = "reload notes"
+)
+
+// IsNoteLoad reports whether the error came from loading the note list.
+func (e ErrorMsg) IsNoteLoad() bool {
+	return e.Context == ContextLoadNotes || e.Context == ContextReloadNotes
+}
+
 // NewError creates a new ErrorMsg with context.
 func NewError(err error, context string) ErrorMsg {
 	return ErrorMsg{Err: err, Context: context}
@@ -37,6 +48,41 @@ func NewError(err error, context string) ErrorMsg {
 // StatusClearMsg indicates that the status message should be cleared.
 type StatusClearMsg struct{}
 
+// ToastExpiredMsg indicates that the toast with the given ID should be dismissed.
+type ToastExpiredMsg struct {
+	ID int
+}
+
+// DialogResultMsg is sent when a dialog closes. Type is what the dialog
+// was opened for, Confirmed is false when it was cancelled, and Value holds
+// the typed text or chosen option.
+type DialogResultMsg struct {
+	Type      string
+	Confirmed bool
+	Value     string
+}
+
+// ClipboardCopiedMsg indicates that text was copied to the system clipboard.
+type ClipboardCopiedMsg struct {
+	What string
+}
+
+// PreviewSettleMsg asks for the preview to render the selected note. Seq
+// identifies the selection change that scheduled it; a later change
+// reschedules with a new Seq.
+type PreviewSettleMsg struct {
+	Seq int
+}
+
+// AutosaveMsg asks for pending note edits to be saved. Seq identifies the
+// edit that scheduled it; a later edit reschedules with a new Seq.
+type AutosaveMsg struct {
+	Seq int
+}
+
+// SessionSaveMsg indicates that the current view should be saved for the next launch.
+type SessionSaveMsg struct{}
+
 // EditorFinishedMsg indicates that the external editor process has completed.
 type EditorFinishedMsg struct {
 	TempFile string
@@ -50,17 +96,102 @@ type SearchResultsMsg struct {
 	Notes []*models.Note
 }
 
+// NoteChange records a note before and after a mutation so it can be undone.
+// Before is nil for creations and After is nil for deletions. A zero
+// NoteChange marks a mutation that cannot be undone.
+type NoteChange struct {
+	Before *models.Note
+	After  *models.Note
+}
+
+// Undoable reports whether the change captured any state to revert.
+func (c NoteChange) Undoable() bool {
+	return c.Before != nil || c.After != nil
+}
+
 // NoteCreatedMsg indicates a note was created successfully.
 type NoteCreatedMsg struct {
 	Note *models.Note
+	NoteChange
 }
 
 // NoteDeletedMsg indicates a note was deleted.
 type NoteDeletedMsg struct {
 	NoteID int64
+	NoteChange
 }
 
 // NoteUpdatedMsg indicates a note was updated.
 type NoteUpdatedMsg struct {
 	Note *models.Note
+	NoteChange
+}
+
+// NoteUnlockedMsg carries the plain content of an encrypted note, and the
+// passphrase that opened it, for the rest of the session.
+type NoteUnlockedMsg struct {
+	NoteID     int64
+	Sealed     string
+	Content    string
+	Passphrase string
+}
+
+// EncryptedNoteSavedMsg indicates an edit to an unlocked note was sealed
+// and saved. Content is the plain text that was saved.
+type EncryptedNoteSavedMsg struct {
+	NoteUpdatedMsg
+	Content string
+}
+
+// NoteMovedMsg indicates a note was moved to another folder.
+type NoteMovedMsg struct {
+	NoteID int64
+	Folder *models.Folder
+	NoteChange
+}
+
+// NoteRestoredMsg indicates an undo or redo put a note back into an earlier state.
+// OldID differs from Note.ID when a deleted note was recreated.
+type NoteRestoredMsg struct {
+	Label string
+	OldID int64
+	Note  *models.Note
+}
+
+// FolderNoteCountMsg reports how many notes a folder deletion would affect.
+type FolderNoteCountMsg struct {
+	Folder *models.Folder
+	Count  int
+}
+
+// UnfiledCountMsg reports how many notes are outside any folder, either
+// without one or pointing at a folder that no longer exists.
+type UnfiledCountMsg struct {
+	Count int
+}
+
+// ScratchLoadedMsg carries the scratch note, created if it was missing.
+type ScratchLoadedMsg struct {
+	Note *models.Note
+}
+
+// LastNoteLoadedMsg carries the most recently updated note, or nil when
+// there are no notes.
+type LastNoteLoadedMsg struct {
+	Note *models.Note
+}
+
+// TodosCompletedMsg reports how many todos a bulk completion marked done.
+type TodosCompletedMsg struct {
+	Count int
+}
+
+// TodoReorderedMsg indicates a todo moved in the manual todo order.
+type TodoReorderedMsg struct {
+	NoteID int64
+}
+
+// NoteCapturedMsg indicates text was appended to today's daily note.
+type NoteCapturedMsg struct {
+	Note *models.Note
 }
@@ -0,0 +1,43 @@
+package tui
+
+// navHistory is a bounded in-session back/forward stack of viewed folders
+// and filters, each with the note that was selected there.
+type navHistory struct {
+	back    []sessionState
+	forward []sessionState
+	limit   int
+}
+
+func newNavHistory(limit int) *navHistory {
+	return &navHistory{limit: limit}
+}
+
+// push records the view being left and discards anything that could be
+// gone forward to.
+func (h *navHistory) push(view sessionState) {
+	h.back = append(h.back, view)
+	if len(h.back) > h.limit {
+		h.back = h.back[len(h.back)-h.limit:]
+	}
+	h.forward = nil
+}
+
+// move pops views off the back or forward stack until open accepts one,
+// then puts current on the opposite stack. Views open rejects, such as
+// folders deleted since, are dropped. It reports false when none is left.
+func (h *navHistory) move(back bool, current sessionState, open func(sessionState) bool) bool {
+	from, to := &h.forward, &h.back
+	if back {
+		from, to = &h.back, &h.forward
+	}
+
+	for len(*from) > 0 {
+		view := (*from)[len(*from)-1]
+		*from = (*from)[:len(*from)-1]
+		if open(view) {
+			*to = append(*to, current)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// sessionFileName is the state file kept next to the database.
+const sessionFileName = "session.json"
+
+// sessionState is the view restored on the next launch. Folders and notes
+// are stored by ID so a deleted folder or note is simply skipped.
+type sessionState struct {
+	Filter   string `json:"filter,omitempty"`
+	FolderID *int64 `json:"folder_id,omitempty"`
+	NoteID   *int64 `json:"note_id,omitempty"`
+}
+
+// loadSession reads the saved session. A missing file is not an error.
+func loadSession(path string) (sessionState, error) {
+	var state sessionState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("read session: %w", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return sessionState{}, fmt.Errorf("parse session: %w", err)
+	}
+	return state, nil
+}
+
+// saveSession writes the session atomically so a crash never leaves a
+// truncated file behind.
+func saveSession(path string, state sessionState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encode session: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), sessionFileName+".*")
+	if err != nil {
+		return fmt.Errorf("create session file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write session: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write session: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replace session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package styles
+
+import "github.com/charmbracelet/lipgloss"
+
+// Focus indicators accepted by ui.focus_indicator
+const (
+	// FocusColor colours the focused panel's border
+	FocusColor = "color"
+	// FocusTitle marks the focused panel's title with an arrow in reverse
+	// video, for users who cannot tell the border colours apart
+	FocusTitle = "title"
+	// FocusBoth does both
+	FocusBoth = "both"
+)
+
+// focusIndicator is the active focus indicator
+var focusIndicator = FocusColor
+
+// UseFocusIndicator sets how the focused panel is marked. Unknown values
+// fall back to FocusColor.
+func UseFocusIndicator(indicator string) {
+	switch indicator {
+	case FocusTitle, FocusBoth:
+		focusIndicator = indicator
+	default:
+		focusIndicator = FocusColor
+	}
+}
+
+// PanelTitle renders a panel title in style, marked when the panel is
+// focused and the indicator includes the title
+func PanelTitle(style lipgloss.Style, title string, focused bool) string {
+	if focused && focusIndicator != FocusColor {
+		return style.Reverse(true).Render(Icons.Focus + " " + title)
+	}
+	return style.Render(title)
+}
+
+// PanelBorder colours the border of a focused panel when the indicator
+// includes the border colour
+func PanelBorder(style lipgloss.Style, focused bool) lipgloss.Style {
+	if focused && focusIndicator != FocusTitle {
+		return style.BorderForeground(Primary)
+	}
+	return style
+}
@@ -0,0 +1,139 @@
+package styles
+
+// IconSet holds the glyphs the TUI uses for indicators. Components read
+// them from Icons rather than hardcoding emoji, so switching sets changes
+// every panel at once.
+type IconSet struct {
+	AppTitle string
+	Help     string
+	Search   string
+
+	// Sidebar entries and panel titles
+	Folder   string
+	AllNotes string
+	Today    string
+	Recent   string
+	Review   string
+	Todos    string
+	Unfiled  string
+	NoteList string
+	Expanded string
+	Collapse string
+	Focus    string // marks the focused panel's title
+
+	// Note indicators
+	Todo     string
+	Done     string
+	Star     string
+	Starred  string // starred folders and the Starred filter
+	Lock     string
+	Overdue  string
+	Priority string
+	Label    string
+
+	// Toasts
+	Info    string
+	Success string
+	Error   string
+
+	// Checklist progress bar segments
+	ProgressDone string
+	ProgressLeft string
+
+	// Encrypted marks notes whose content is sealed with a passphrase
+	Encrypted string
+}
+
+// EmojiIcons is the default icon set
+var EmojiIcons = IconSet{
+	AppTitle: "記録 Kiroku",
+	Help:     "⌨️ ",
+	Search:   "🔍",
+	Folder:   "📁",
+	AllNotes: "📋",
+	Today:    "📅",
+	Recent:   "🕘",
+	Review:   "🧹",
+	Todos:    "☐",
+	Unfiled:  "📭",
+	NoteList: "📝",
+	Expanded: "▾",
+	Collapse: "▸",
+	Focus:    "▶",
+	Todo:     "☐",
+	Done:     "☑",
+	Star:     "★",
+	Starred:  "⭐",
+	Lock:     "🔒",
+	Overdue:  "⚠",
+	Priority: "●",
+	Label:    "▌",
+	Info:     "ℹ",
+	Success:  "✓",
+	Error:    "✗",
+
+	ProgressDone: "▰",
+	ProgressLeft: "▱",
+
+	Encrypted: "🔐",
+}
+
+// ASCIIIcons replaces every indicator with single-width ASCII for
+// terminals that draw emoji as boxes or at the wrong width
+var ASCIIIcons = IconSet{
+	AppTitle: "Kiroku",
+	Help:     "?",
+	Search:   ">",
+	Folder:   "[D]",
+	AllNotes: "[A]",
+	Today:    "[T]",
+	Recent:   "[R]",
+	Review:   "[V]",
+	Todos:    "[ ]",
+	Unfiled:  "[?]",
+	NoteList: "[N]",
+	Expanded: "v",
+	Collapse: ">",
+	Focus:    ">",
+	Todo:     "[ ]",
+	Done:     "[x]",
+	Star:     "*",
+	Starred:  "*",
+	Lock:     "[L]",
+	Overdue:  "!",
+	Priority: "o",
+	Label:    "|",
+	Info:     "i",
+	Success:  "+",
+	Error:    "x",
+
+	ProgressDone: "#",
+	ProgressLeft: "-",
+
+	Encrypted: "[E]",
+}
+
+// Icons is the active icon set
+var Icons = EmojiIcons
+
+// asciiMode records whether the ASCII set is active
+var asciiMode bool
+
+// UseASCIIIcons switches between the ASCII and emoji icon sets
+func UseASCIIIcons(ascii bool) {
+	asciiMode = ascii
+	if ascii {
+		Icons = ASCIIIcons
+	} else {
+		Icons = EmojiIcons
+	}
+}
+
+// FolderIcon returns the icon for a folder. Folders carry their own emoji,
+// which is replaced by the set's folder icon in ASCII mode.
+func FolderIcon(icon string) string {
+	if icon == "" || asciiMode {
+		return Icons.Folder
+	}
+	return icon
+}
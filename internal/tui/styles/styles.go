@@ -87,9 +87,25 @@ var (
 				Foreground(lipgloss.Color("#FFFFFF")).
 				Bold(true)
 
+	NoteSnippetStyle = lipgloss.NewStyle().
+				Foreground(TextMutedC).
+				Italic(true)
+
 	NoteDateStyle = lipgloss.NewStyle().
 			Foreground(TextMutedC)
 
+	DueDateStyle = lipgloss.NewStyle().
+			Foreground(Warning)
+
+	OverdueStyle = lipgloss.NewStyle().
+			Foreground(Danger).
+			Bold(true)
+
+	// SearchMatchStyle marks search query matches in the preview
+	SearchMatchStyle = lipgloss.NewStyle().
+				Foreground(Warning).
+				Bold(true)
+
 	TodoDoneStyle = lipgloss.NewStyle().
 			Foreground(TextMutedC) // Removed Strikethrough - renders raw ANSI in some terminals
 
@@ -122,6 +138,28 @@ var (
 	StatusDescStyle = lipgloss.NewStyle().
 			Foreground(TextSecondary)
 
+	// Toast styles
+	ToastInfoStyle = lipgloss.NewStyle().
+			Background(Surface).
+			Foreground(Secondary).
+			Padding(0, 1)
+
+	ToastSuccessStyle = lipgloss.NewStyle().
+				Background(Surface).
+				Foreground(Success).
+				Padding(0, 1)
+
+	ToastErrorStyle = lipgloss.NewStyle().
+			Background(Surface).
+			Foreground(Danger).
+			Bold(true).
+			Padding(0, 1)
+
+	// Diff styles
+	DiffInsertStyle  = lipgloss.NewStyle().Foreground(Success)
+	DiffDeleteStyle  = lipgloss.NewStyle().Foreground(Danger)
+	DiffContextStyle = lipgloss.NewStyle().Foreground(TextSecondary)
+
 	// Search bar styles
 	SearchBarStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
@@ -193,11 +231,11 @@ var (
 func RenderPriority(priority int) string {
 	switch priority {
 	case 3:
-		return lipgloss.NewStyle().Foreground(PriorityHigh).Render("●")
+		return lipgloss.NewStyle().Foreground(PriorityHigh).Render(Icons.Priority)
 	case 2:
-		return lipgloss.NewStyle().Foreground(PriorityMedium).Render("●")
+		return lipgloss.NewStyle().Foreground(PriorityMedium).Render(Icons.Priority)
 	case 1:
-		return lipgloss.NewStyle().Foreground(PriorityLow).Render("●")
+		return lipgloss.NewStyle().Foreground(PriorityLow).Render(Icons.Priority)
 	default:
 		return ""
 	}
@@ -206,15 +244,51 @@ func RenderPriority(priority int) string {
 // RenderStar renders a star indicator
 func RenderStar(starred bool) string {
 	if starred {
-		return lipgloss.NewStyle().Foreground(Warning).Render("★")
+		return lipgloss.NewStyle().Foreground(Warning).Render(Icons.Star)
+	}
+	return ""
+}
+
+// labelColors maps note label names to theme colors
+var labelColors = map[string]lipgloss.Color{
+	"red":    Danger,
+	"orange": lipgloss.Color("#FB923C"),
+	"yellow": Warning,
+	"green":  Success,
+	"blue":   lipgloss.Color("#3B82F6"),
+	"purple": Primary,
+}
+
+// RenderLabel renders a one-column gutter bar in the label's color, or
+// nothing for an unlabeled note
+func RenderLabel(label string) string {
+	color, ok := labelColors[label]
+	if !ok {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(Icons.Label)
+}
+
+// RenderLock renders a lock indicator
+func RenderLock(locked bool) string {
+	if locked {
+		return Icons.Lock
 	}
 	return ""
 }
 
+// RenderDueDate renders a todo's due date, highlighted when overdue
+func RenderDueDate(due string, overdue bool) string {
+	if overdue {
+		return OverdueStyle.Render(Icons.Overdue + " " + due)
+	}
+	return DueDateStyle.Render("due " + due)
+}
+
 // RenderTodoStatus renders a todo status indicator
 func RenderTodoStatus(done bool) string {
 	if done {
-		return lipgloss.NewStyle().Foreground(Success).Render("☑")
+		return lipgloss.NewStyle().Foreground(Success).Render(Icons.Done)
 	}
-	return lipgloss.NewStyle().Foreground(TextSecondary).Render("☐")
+	return lipgloss.NewStyle().Foreground(TextSecondary).Render(Icons.Todo)
 }
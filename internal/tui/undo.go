@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"github.com/tranducquang/kiroku/internal/models"
+	"github.com/tranducquang/kiroku/internal/tui/messages"
+)
+
+// undoEntry is a recorded note change with a label for status messages.
+type undoEntry struct {
+	label  string
+	change messages.NoteChange
+}
+
+// undoHistory is a bounded in-session stack of note changes.
+type undoHistory struct {
+	undo  []undoEntry
+	redo  []undoEntry
+	limit int
+}
+
+func newUndoHistory(limit int) *undoHistory {
+	return &undoHistory{limit: limit}
+}
+
+// record pushes a new change and discards anything that could be redone.
+func (h *undoHistory) record(label string, change messages.NoteChange) {
+	if !change.Undoable() {
+		return
+	}
+	h.undo = append(h.undo, undoEntry{label: label, change: change})
+	if len(h.undo) > h.limit {
+		h.undo = h.undo[len(h.undo)-h.limit:]
+	}
+	h.redo = nil
+}
+
+// popUndo moves the latest change onto the redo stack and returns it.
+func (h *undoHistory) popUndo() (undoEntry, bool) {
+	if len(h.undo) == 0 {
+		return undoEntry{}, false
+	}
+	entry := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, entry)
+	return entry, true
+}
+
+// popRedo moves the latest undone change back onto the undo stack and returns it.
+func (h *undoHistory) popRedo() (undoEntry, bool) {
+	if len(h.redo) == 0 {
+		return undoEntry{}, false
+	}
+	entry := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, entry)
+	return entry, true
+}
+
+// remapID points recorded snapshots at a note recreated under a new ID.
+func (h *undoHistory) remapID(oldID, newID int64) {
+	remap := func(note *models.Note) {
+		if note != nil && note.ID == oldID {
+			note.ID = newID
+		}
+	}
+	for _, stack := range [][]undoEntry{h.undo, h.redo} {
+		for _, entry := range stack {
+			remap(entry.change.Before)
+			remap(entry.change.After)
+		}
+	}
+}
+
+// clear drops all recorded changes.
+func (h *undoHistory) clear() {
+	h.undo = nil
+	h.redo = nil
+}